@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/server"
+)
+
+// apiBackend talks to a running server's admin API.
+type apiBackend struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func newAPIBackend(baseURL, token string) *apiBackend {
+	return &apiBackend{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends an admin request and decodes the JSON response into out.
+func (b *apiBackend) do(method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, b.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+b.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (b *apiBackend) ListRooms() ([]server.AdminRoomSummary, error) {
+	var rooms []server.AdminRoomSummary
+	err := b.do(http.MethodGet, "/api/admin/rooms", nil, &rooms)
+	return rooms, err
+}
+
+func (b *apiBackend) Events(roomCode string, since int) (server.AdminEventsResponse, error) {
+	var page server.AdminEventsResponse
+	path := "/api/admin/rooms/" + url.PathEscape(roomCode) + "/events?since=" + strconv.Itoa(since)
+	err := b.do(http.MethodGet, path, nil, &page)
+	return page, err
+}
+
+func (b *apiBackend) Export(roomCode string) (core.RoomSnapshot, error) {
+	var snapshot core.RoomSnapshot
+	err := b.do(http.MethodGet, "/api/admin/rooms/"+url.PathEscape(roomCode)+"/export", nil, &snapshot)
+	return snapshot, err
+}
+
+func (b *apiBackend) Import(snapshot core.RoomSnapshot, replace bool) error {
+	path := "/api/admin/rooms/import"
+	if replace {
+		path += "?replace=true"
+	}
+	return b.do(http.MethodPost, path, snapshot, nil)
+}
@@ -0,0 +1,317 @@
+// Command roundtablectl is an operator tool for the roundtable server.
+//
+// It talks to the server's admin API, or with -store directly to the
+// snapshot directory of a file store (DATA_DIR), to list rooms, dump event
+// logs, export and import rooms, and tail live events.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/server"
+)
+
+// backend is where rooms are read from and written to.
+type backend interface {
+	ListRooms() ([]server.AdminRoomSummary, error)
+	Events(roomCode string, since int) (server.AdminEventsResponse, error)
+	Export(roomCode string) (core.RoomSnapshot, error)
+	Import(snapshot core.RoomSnapshot, replace bool) error
+}
+
+const usage = `Usage: roundtablectl [flags] <command> [arguments]
+
+Commands:
+  rooms                     List rooms
+  events <code>             Dump a room's full event log as JSON lines
+  export <code> [file]      Write a room snapshot to file (default stdout)
+  import [-replace] <file>  Import a room snapshot from file ("-" for stdin)
+  tail [-interval d] [-history] <code>
+                            Follow a room's events as JSON lines
+
+Flags:
+`
+
+func main() {
+	flags := flag.NewFlagSet("roundtablectl", flag.ExitOnError)
+	serverURL := flags.String("server", envOr("ROUNDTABLE_SERVER", "http://localhost:8080"), "server base URL (env ROUNDTABLE_SERVER)")
+	token := flags.String("token", os.Getenv("ROUNDTABLE_ADMIN_TOKEN"), "admin API token (env ROUNDTABLE_ADMIN_TOKEN)")
+	storeDir := flags.String("store", "", "use a file store directory directly instead of the admin API")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var b backend
+	if *storeDir != "" {
+		b = &storeBackend{dir: *storeDir}
+	} else {
+		if *token == "" {
+			fatalf("an admin token is required (-token or ROUNDTABLE_ADMIN_TOKEN)")
+		}
+		b = newAPIBackend(*serverURL, *token)
+	}
+
+	command, args := flags.Arg(0), flags.Args()[1:]
+
+	var err error
+	switch command {
+	case "rooms":
+		err = listRooms(b, os.Stdout)
+	case "events":
+		err = withRoomCode(args, func(roomCode string, _ []string) error {
+			return dumpEvents(b, roomCode, os.Stdout)
+		})
+	case "export":
+		err = withRoomCode(args, func(roomCode string, rest []string) error {
+			return exportRoom(b, roomCode, rest)
+		})
+	case "import":
+		err = importRoom(b, args)
+	case "tail":
+		err = tailEvents(b, args, os.Stdout)
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fatalf("%s: %v", command, err)
+	}
+}
+
+// withRoomCode runs fn with the room code argument and any remaining ones.
+func withRoomCode(args []string, fn func(roomCode string, rest []string) error) error {
+	if len(args) == 0 {
+		return errors.New("room code required")
+	}
+	return fn(args[0], args[1:])
+}
+
+// listRooms prints a table of rooms, most recently active first.
+func listRooms(b backend, out io.Writer) error {
+	rooms, err := b.ListRooms()
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].LastActivityAt.After(rooms[j].LastActivityAt)
+	})
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CODE\tGAME\tSTATUS\tPLAYERS\tEVENTS\tCREATED\tLAST ACTIVITY")
+	for _, room := range rooms {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d/%d (%d online)\t%d\t%s\t%s ago\n",
+			room.RoomCode, room.GameType, room.Status,
+			room.Players, room.MaxPlayers, room.ConnectedPlayers,
+			room.Events,
+			room.CreatedAt.Local().Format(time.DateTime),
+			time.Since(room.LastActivityAt).Round(time.Second),
+		)
+	}
+	return tw.Flush()
+}
+
+// dumpEvents writes a room's full event log as JSON lines.
+func dumpEvents(b backend, roomCode string, out io.Writer) error {
+	page, err := b.Events(roomCode, 0)
+	if err != nil {
+		return err
+	}
+	return writeEventLines(out, page.Events)
+}
+
+// exportRoom writes a room snapshot to a file or stdout.
+func exportRoom(b backend, roomCode string, args []string) error {
+	snapshot, err := b.Export(roomCode)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if len(args) > 0 && args[0] != "-" {
+		// Snapshots contain session tokens
+		out, err = os.OpenFile(args[0], os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snapshot)
+}
+
+// importRoom reads a room snapshot and imports it.
+func importRoom(b backend, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	replace := flags.Bool("replace", false, "replace an existing room with the same code")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		return errors.New("snapshot file required")
+	}
+
+	in := os.Stdin
+	if path := flags.Arg(0); path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	var snapshot core.RoomSnapshot
+	if err := json.NewDecoder(in).Decode(&snapshot); err != nil {
+		return fmt.Errorf("invalid snapshot: %w", err)
+	}
+
+	if err := b.Import(snapshot, *replace); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "imported room %s (%s, %d events)\n", snapshot.ID, snapshot.GameType, len(snapshot.EventLog))
+	return nil
+}
+
+// tailEvents prints new events of a room as they happen, until interrupted.
+func tailEvents(b backend, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("tail", flag.ExitOnError)
+	interval := flags.Duration("interval", time.Second, "polling interval")
+	history := flags.Bool("history", false, "print existing events first")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		return errors.New("room code required")
+	}
+
+	t := &tailer{backend: b, roomCode: flags.Arg(0)}
+	events, _, err := t.poll()
+	if err != nil {
+		return err
+	}
+	if *history {
+		if err := writeEventLines(out, events); err != nil {
+			return err
+		}
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-interrupt:
+			return nil
+		case <-ticker.C:
+			events, reset, err := t.poll()
+			if err != nil {
+				return err
+			}
+			if reset {
+				fmt.Fprintln(os.Stderr, "-- room was reset --")
+			}
+			if err := writeEventLines(out, events); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// tailer follows a room's event log. It remembers the last event it saw and
+// asks for the log from that event on, so a room that was reset (its log
+// replaced, even by a longer one) is noticed by the event no longer being
+// there.
+type tailer struct {
+	backend  backend
+	roomCode string
+	next     int    // Index after the last event seen
+	lastID   string // ID of the last event seen
+}
+
+// poll returns the events added since the last poll, or the whole log if the
+// room was reset in the meantime.
+func (t *tailer) poll() ([]core.EventRecord, bool, error) {
+	if t.next == 0 {
+		events, err := t.fetchAll()
+		return events, false, err
+	}
+
+	page, err := t.backend.Events(t.roomCode, t.next-1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(page.Events) == 0 || page.Events[0].ID != t.lastID || page.Next < t.next {
+		events, err := t.fetchAll()
+		return events, true, err
+	}
+
+	t.advance(page)
+	return page.Events[1:], false, nil
+}
+
+// fetchAll returns the whole log and moves past it.
+func (t *tailer) fetchAll() ([]core.EventRecord, error) {
+	page, err := t.backend.Events(t.roomCode, 0)
+	if err != nil {
+		return nil, err
+	}
+	t.advance(page)
+	return page.Events, nil
+}
+
+// advance moves past a page of events.
+func (t *tailer) advance(page server.AdminEventsResponse) {
+	t.next = page.Next
+	if len(page.Events) > 0 {
+		t.lastID = page.Events[len(page.Events)-1].ID
+	}
+}
+
+// writeEventLines writes one JSON object per event.
+func writeEventLines(out io.Writer, events []core.EventRecord) error {
+	encoder := json.NewEncoder(out)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envOr returns the environment variable, or fallback if it is unset.
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// fatalf prints an error and exits.
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "roundtablectl: "+format+"\n", args...)
+	os.Exit(1)
+}
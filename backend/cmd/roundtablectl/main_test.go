@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+// writeRoom writes a snapshot of a room with events of the given IDs.
+func writeRoom(t *testing.T, dir, roomCode string, eventIDs ...string) {
+	t.Helper()
+
+	snapshot := core.RoomSnapshot{
+		ID:             roomCode,
+		GameType:       "werewolf",
+		Status:         core.RoomStatusWaiting,
+		MaxPlayers:     10,
+		CreatedAt:      time.Now(),
+		LastActivityAt: time.Now(),
+	}
+	for _, id := range eventIDs {
+		snapshot.EventLog = append(snapshot.EventLog, core.EventRecord{
+			GameEvent: core.GameEvent{ID: id, Type: "player_joined", ActorID: "system", Payload: json.RawMessage(`{}`)},
+		})
+	}
+	if err := store.WriteSnapshot(dir, snapshot); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+}
+
+// eventIDs returns the IDs of events.
+func eventIDs(events []core.EventRecord) []string {
+	ids := make([]string, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestTailer_Poll(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeRoom(t, dir, "ABC123", "e1", "e2")
+	tail := &tailer{backend: &storeBackend{dir: dir}, roomCode: "ABC123"}
+
+	steps := []struct {
+		name      string
+		log       []string // Room's log before polling; nil to leave it
+		wantIDs   []string
+		wantReset bool
+	}{
+		{name: "history", wantIDs: []string{"e1", "e2"}},
+		{name: "nothing new", wantIDs: []string{}},
+		{name: "new events", log: []string{"e1", "e2", "e3", "e4"}, wantIDs: []string{"e3", "e4"}},
+		{name: "reset to a longer log", log: []string{"f1", "f2", "f3", "f4", "f5"}, wantIDs: []string{"f1", "f2", "f3", "f4", "f5"}, wantReset: true},
+		{name: "reset to a shorter log", log: []string{"g1"}, wantIDs: []string{"g1"}, wantReset: true},
+		{name: "new events after reset", log: []string{"g1", "g2"}, wantIDs: []string{"g2"}},
+	}
+
+	for _, step := range steps {
+		if step.log != nil {
+			writeRoom(t, dir, "ABC123", step.log...)
+		}
+
+		events, reset, err := tail.poll()
+		if err != nil {
+			t.Fatalf("%s: poll() error = %v", step.name, err)
+		}
+		if got := strings.Join(eventIDs(events), ","); got != strings.Join(step.wantIDs, ",") {
+			t.Errorf("%s: expected events %v, got %s", step.name, step.wantIDs, got)
+		}
+		if reset != step.wantReset {
+			t.Errorf("%s: expected reset %v, got %v", step.name, step.wantReset, reset)
+		}
+	}
+}
+
+func TestListRooms(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeRoom(t, dir, "ABC123", "e1", "e2")
+
+	var out bytes.Buffer
+	if err := listRooms(&storeBackend{dir: dir}, &out); err != nil {
+		t.Fatalf("listRooms() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "ABC123") || !strings.Contains(lines[1], "werewolf") {
+		t.Errorf("expected a header and one room row, got %q", out.String())
+	}
+}
+
+func TestDumpEvents(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeRoom(t, dir, "ABC123", "e1", "e2")
+
+	var out bytes.Buffer
+	if err := dumpEvents(&storeBackend{dir: dir}, "ABC123", &out); err != nil {
+		t.Fatalf("dumpEvents() error = %v", err)
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var event core.EventRecord
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("expected a JSON event per line, got %q: %v", scanner.Text(), err)
+		}
+		ids = append(ids, event.ID)
+	}
+	if strings.Join(ids, ",") != "e1,e2" {
+		t.Errorf("expected events e1,e2, got %v", ids)
+	}
+
+	if err := dumpEvents(&storeBackend{dir: dir}, "NOROOM", &out); err == nil {
+		t.Error("expected error for unknown room")
+	}
+}
+
+func TestStoreBackend_Import(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeRoom(t, dir, "ABC123", "e1")
+	b := &storeBackend{dir: dir}
+
+	snapshot, err := b.Export("ABC123")
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if err := b.Import(snapshot, false); err == nil {
+		t.Error("expected importing over an existing room to need replace")
+	}
+	if err := b.Import(snapshot, true); err != nil {
+		t.Errorf("expected replace to succeed, got %v", err)
+	}
+
+	snapshot.ID = "XYZ789"
+	if err := b.Import(snapshot, false); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if _, err := b.Export("XYZ789"); err != nil {
+		t.Errorf("expected imported room to be readable, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/server"
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+// storeBackend reads and writes the snapshot files of a file store directly.
+// A running server only writes its rooms periodically and only loads files at
+// startup, so use the admin API to work with live rooms.
+type storeBackend struct {
+	dir string
+}
+
+func (b *storeBackend) ListRooms() ([]server.AdminRoomSummary, error) {
+	snapshots, err := store.ReadSnapshots(b.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	rooms := make([]server.AdminRoomSummary, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		rooms = append(rooms, server.AdminRoomSummary{
+			RoomCode:       snapshot.ID,
+			Status:         snapshot.Status,
+			GameType:       snapshot.GameType,
+			Players:        len(snapshot.Players),
+			MaxPlayers:     snapshot.MaxPlayers,
+			Events:         len(snapshot.EventLog),
+			CreatedAt:      snapshot.CreatedAt,
+			LastActivityAt: snapshot.LastActivityAt,
+		})
+	}
+	return rooms, nil
+}
+
+func (b *storeBackend) Events(roomCode string, since int) (server.AdminEventsResponse, error) {
+	snapshot, err := store.ReadSnapshot(b.dir, roomCode)
+	if err != nil {
+		return server.AdminEventsResponse{}, err
+	}
+
+	if since > len(snapshot.EventLog) {
+		since = 0
+	}
+	return server.AdminEventsResponse{
+		Events: snapshot.EventLog[since:],
+		Next:   len(snapshot.EventLog),
+	}, nil
+}
+
+func (b *storeBackend) Export(roomCode string) (core.RoomSnapshot, error) {
+	return store.ReadSnapshot(b.dir, roomCode)
+}
+
+func (b *storeBackend) Import(snapshot core.RoomSnapshot, replace bool) error {
+	if _, err := store.ReadSnapshot(b.dir, snapshot.ID); err == nil && !replace {
+		return fmt.Errorf("room %s already exists (use -replace)", snapshot.ID)
+	}
+	return store.WriteSnapshot(b.dir, snapshot)
+}
@@ -2,14 +2,24 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"syscall"
 	"time"
 
+	"github.com/KonradHerman/roundtable/internal/auth"
+	"github.com/KonradHerman/roundtable/internal/config"
+	"github.com/KonradHerman/roundtable/internal/games"
+	"github.com/KonradHerman/roundtable/internal/profile"
 	"github.com/KonradHerman/roundtable/internal/server"
+	"github.com/KonradHerman/roundtable/internal/stats"
 	"github.com/KonradHerman/roundtable/internal/store"
+	"github.com/KonradHerman/roundtable/internal/tournament"
 )
 
 func main() {
@@ -19,28 +29,147 @@ func main() {
 	}))
 	slog.SetDefault(logger)
 
+	// Load configuration from file, environment and flags
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("loaded configuration", "config", cfg)
+
 	// Create root context for shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Create store
-	memStore := store.NewMemoryStore()
+	// Create store: in-memory, or persisted to the data directory when set
+	memStore := store.NewMemoryStoreWithOptions(cfg.Rooms)
+	var roomStore store.Store = memStore
+	var fileStore *store.FileStore
+	if cfg.DataDir != "" {
+		fileStore, err = store.NewFileStore(cfg.DataDir, games.NewRegistry().CreateGame, cfg.Rooms)
+		if err != nil {
+			slog.Error("failed to open file store", "dir", cfg.DataDir, "error", err)
+			os.Exit(1)
+		}
+		memStore = fileStore.MemoryStore
+		roomStore = fileStore
+		slog.Info("using file store", "dir", cfg.DataDir)
+	}
+
+	// Session tokens of persisted rooms only stay valid with the same key
+	options := cfg.ServerOptions()
+	if options.SessionKey == nil {
+		if cfg.DataDir != "" {
+			options.SessionKey, err = auth.LoadOrCreateKey(filepath.Join(cfg.DataDir, "session.key"))
+			if err != nil {
+				slog.Error("failed to load session key", "dir", cfg.DataDir, "error", err)
+				os.Exit(1)
+			}
+		} else {
+			slog.Warn("no session key configured; sessions will not survive a restart")
+		}
+	}
 
 	// Create server
-	srv := server.NewServer(memStore)
+	srv := server.NewServerWithOptions(roomStore, options)
+	memStore.SetCleanupListener(srv.RoomRemoved)
+
+	// Player profiles are kept with the rooms when persisting
+	if cfg.DataDir != "" {
+		profiles, err := profile.OpenStore(filepath.Join(cfg.DataDir, "profiles.json"))
+		if err != nil {
+			slog.Error("failed to open profile store", "dir", cfg.DataDir, "error", err)
+			os.Exit(1)
+		}
+		srv.SetProfiles(profiles)
+
+		statsStore, err := stats.OpenStore(filepath.Join(cfg.DataDir, "stats.json"))
+		if err != nil {
+			slog.Error("failed to open stats store", "dir", cfg.DataDir, "error", err)
+			os.Exit(1)
+		}
+		srv.SetStats(statsStore)
+
+		tournaments, err := tournament.OpenStore(filepath.Join(cfg.DataDir, "tournaments.json"))
+		if err != nil {
+			slog.Error("failed to open tournament store", "dir", cfg.DataDir, "error", err)
+			os.Exit(1)
+		}
+		srv.SetTournaments(tournaments)
+	}
+
+	// Rooms persisted before the last shutdown; players reconnect with their
+	// existing session tokens
+	if _, err := srv.RestoreRooms(); err != nil {
+		slog.Error("failed to restore rooms", "error", err)
+	}
 
 	// Setup routes
 	mux := http.NewServeMux()
 
 	// API routes
-	mux.HandleFunc("POST /api/rooms", srv.HandleCreateRoom)
-	mux.HandleFunc("GET /api/rooms/{code}", srv.HandleGetRoom)
-	mux.HandleFunc("POST /api/rooms/{code}/join", srv.HandleJoinRoom)
-	mux.HandleFunc("POST /api/rooms/{code}/start", srv.HandleStartGame)
-	mux.HandleFunc("POST /api/rooms/{code}/reset", srv.HandleResetGame)
+	mux.HandleFunc("POST /api/rooms", srv.RateLimited(server.ScopeCreateRoom, srv.HandleCreateRoom))
+	mux.HandleFunc("GET /api/rooms", srv.RateLimited(server.ScopeReads, srv.HandleListRooms))
+	mux.HandleFunc("POST /api/quickmatch", srv.RateLimited(server.ScopeCreateRoom, srv.HandleQuickMatch))
+	mux.HandleFunc("GET /api/rooms/{code}", srv.RateLimited(server.ScopeReads, srv.HandleGetRoom))
+	mux.HandleFunc("POST /api/rooms/{code}/join", srv.RateLimited(server.ScopeJoinRoom, srv.HandleJoinRoom))
+	mux.HandleFunc("POST /api/rooms/{code}/start", srv.RateLimited(server.ScopeWrites, srv.HandleStartGame))
+	mux.HandleFunc("POST /api/rooms/{code}/reset", srv.RateLimited(server.ScopeWrites, srv.HandleResetGame))
+	mux.HandleFunc("GET /api/rooms/{code}/session_summary", srv.RateLimited(server.ScopeReads, srv.HandleSessionSummary))
+	mux.HandleFunc("POST /api/rooms/{code}/end_session", srv.RateLimited(server.ScopeWrites, srv.HandleEndSession))
+	mux.HandleFunc("POST /api/rooms/{code}/actions", srv.HandleAction)
+	mux.HandleFunc("DELETE /api/rooms/{code}/players/{playerId}", srv.RateLimited(server.ScopeWrites, srv.HandleKickPlayer))
+	mux.HandleFunc("POST /api/rooms/{code}/chat", srv.HandleChat)
+	mux.HandleFunc("POST /api/rooms/{code}/reactions", srv.HandleReaction)
+	mux.HandleFunc("POST /api/rooms/{code}/players/{playerId}/mute", srv.RateLimited(server.ScopeWrites, srv.HandleMutePlayer))
+	mux.HandleFunc("DELETE /api/rooms/{code}/players/{playerId}/mute", srv.RateLimited(server.ScopeWrites, srv.HandleMutePlayer))
+	mux.HandleFunc("POST /api/rooms/{code}/invites", srv.RateLimited(server.ScopeWrites, srv.HandleCreateInvite))
+	mux.HandleFunc("POST /api/rooms/{code}/bots", srv.RateLimited(server.ScopeWrites, srv.HandleAddBot))
+
+	// Optional player profiles, authenticated with X-Profile-Key
+	mux.HandleFunc("POST /api/profiles", srv.RateLimited(server.ScopeCreateRoom, srv.HandleCreateProfile))
+	mux.HandleFunc("POST /api/profiles/login", srv.RateLimited(server.ScopeSignIn, srv.HandleLoginProfile))
+	mux.HandleFunc("POST /api/profiles/logout", srv.RateLimited(server.ScopeWrites, srv.HandleLogoutProfile))
+	mux.HandleFunc("GET /api/profiles/me", srv.RateLimited(server.ScopeReads, srv.HandleGetProfile))
+	mux.HandleFunc("PATCH /api/profiles/me", srv.RateLimited(server.ScopeWrites, srv.HandleUpdateProfile))
+	mux.HandleFunc("DELETE /api/profiles/me", srv.RateLimited(server.ScopeWrites, srv.HandleDeleteProfile))
+	mux.HandleFunc("GET /api/profiles/{id}/stats", srv.RateLimited(server.ScopeReads, srv.HandleProfileStats))
+	mux.HandleFunc("GET /api/leaderboard", srv.RateLimited(server.ScopeReads, srv.HandleLeaderboard))
+
+	// Tournaments across several rooms, run by an organizer's profile
+	mux.HandleFunc("POST /api/tournaments", srv.RateLimited(server.ScopeCreateRoom, srv.HandleCreateTournament))
+	mux.HandleFunc("GET /api/tournaments/{id}", srv.RateLimited(server.ScopeReads, srv.HandleGetTournament))
+	mux.HandleFunc("POST /api/tournaments/{id}/register", srv.RateLimited(server.ScopeJoinRoom, srv.HandleRegisterTournament))
+	mux.HandleFunc("POST /api/tournaments/{id}/rounds", srv.RateLimited(server.ScopeCreateRoom, srv.HandleStartTournamentRound))
+	mux.HandleFunc("POST /api/tournaments/{id}/tables/{table}/close", srv.RateLimited(server.ScopeWrites, srv.HandleCloseTournamentTable))
+	mux.HandleFunc("POST /api/tournaments/{id}/finish", srv.RateLimited(server.ScopeWrites, srv.HandleFinishTournament))
+	mux.HandleFunc("GET /api/tournaments/{id}/seat", srv.RateLimited(server.ScopeReads, srv.HandleTournamentSeat))
+
+	// QR codes of the join link, for showing on a shared screen
+	mux.HandleFunc("GET /api/rooms/{code}/qr.png", srv.RateLimited(server.ScopeReads, srv.HandleRoomQRPNG))
+	mux.HandleFunc("GET /api/rooms/{code}/qr.svg", srv.RateLimited(server.ScopeReads, srv.HandleRoomQRSVG))
+
+	// Server-Sent Events fallback for networks that block WebSockets
+	mux.HandleFunc("GET /api/rooms/{code}/events", srv.RateLimited(server.ScopeConnect, srv.HandleEvents))
 
 	// WebSocket route
-	mux.HandleFunc("GET /api/rooms/{code}/ws", srv.HandleWebSocket)
+	mux.HandleFunc("GET /api/rooms/{code}/ws", srv.RateLimited(server.ScopeConnect, srv.HandleWebSocket))
+
+	// Admin API (disabled unless ADMIN_TOKEN is set)
+	mux.HandleFunc("GET /api/admin/rooms", srv.RateLimited(server.ScopeAdmin, srv.AdminOnly(srv.HandleAdminListRooms)))
+	mux.HandleFunc("GET /api/admin/rooms/{code}", srv.RateLimited(server.ScopeAdmin, srv.AdminOnly(srv.HandleAdminGetRoom)))
+	mux.HandleFunc("POST /api/admin/rooms/{code}/reset", srv.RateLimited(server.ScopeAdmin, srv.AdminOnly(srv.HandleAdminResetRoom)))
+	mux.HandleFunc("DELETE /api/admin/rooms/{code}", srv.RateLimited(server.ScopeAdmin, srv.AdminOnly(srv.HandleAdminCloseRoom)))
+	mux.HandleFunc("GET /api/admin/rooms/{code}/events", srv.RateLimited(server.ScopeAdmin, srv.AdminOnly(srv.HandleAdminRoomEvents)))
+	mux.HandleFunc("GET /api/admin/rooms/{code}/export", srv.RateLimited(server.ScopeAdmin, srv.AdminOnly(srv.HandleAdminExportRoom)))
+	mux.HandleFunc("POST /api/admin/rooms/import", srv.RateLimited(server.ScopeAdmin, srv.AdminOnly(srv.HandleAdminImportRoom)))
+	mux.HandleFunc("POST /api/admin/notice", srv.RateLimited(server.ScopeAdmin, srv.AdminOnly(srv.HandleAdminNotice)))
+
+	// Prometheus metrics
+	mux.HandleFunc("GET /metrics", srv.HandleMetrics)
 
 	// Health check
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
@@ -48,17 +177,12 @@ func main() {
 		w.Write([]byte("OK"))
 	})
 
-	// CORS middleware (for development)
-	handler := corsMiddleware(mux)
+	// CORS for the allowed origins; WebSockets check the same policy
+	handler := srv.CORS(mux)
 
 	// HTTP server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-
 	httpServer := &http.Server{
-		Addr:         ":" + port,
+		Addr:         cfg.Addr(),
 		Handler:      handler,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
@@ -66,23 +190,28 @@ func main() {
 	}
 
 	// Start cleanup goroutine with context
-	go cleanupRoutine(ctx, memStore)
+	go cleanupRoutine(ctx, roomStore, cfg.CleanupInterval)
+
+	// Periodically persist rooms, since they are mutated in place
+	if fileStore != nil {
+		go flushRoutine(ctx, fileStore, cfg.FlushInterval)
+	}
 
 	// Start phase check routine for game timers
-	go phaseCheckRoutine(ctx, memStore, srv)
+	go phaseCheckRoutine(ctx, roomStore, srv, cfg.PhaseTick)
 
 	// Graceful shutdown
 	go func() {
-		slog.Info("server starting", "port", port)
+		slog.Info("server starting", "port", cfg.Port)
 		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			slog.Error("server error", "error", err)
 			os.Exit(1)
 		}
 	}()
 
-	// Wait for interrupt signal
+	// Wait for interrupt or termination signal
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 
 	slog.Info("shutting down server")
@@ -90,60 +219,39 @@ func main() {
 	// Cancel background routines
 	cancel()
 
-	// Shutdown HTTP server
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer shutdownCancel()
 
-	if err := httpServer.Shutdown(shutdownCtx); err != nil {
-		slog.Error("server forced to shutdown", "error", err)
-		os.Exit(1)
+	// Tell clients to reconnect and close WebSocket and SSE connections,
+	// which http.Server.Shutdown does not track
+	if err := srv.Drain(shutdownCtx, cfg.ReconnectAfter); err != nil {
+		slog.Warn("connections did not drain", "error", err)
 	}
 
-	slog.Info("server stopped")
-}
-
-// corsMiddleware adds CORS headers with environment-based origin restrictions.
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		allowedOrigin := os.Getenv("ALLOWED_ORIGIN")
-		if allowedOrigin == "" {
-			allowedOrigin = "http://localhost:5173" // Dev default
-		}
+	// Shutdown HTTP server
+	shutdownErr := httpServer.Shutdown(shutdownCtx)
+	if shutdownErr != nil {
+		slog.Error("server forced to shutdown", "error", shutdownErr)
+	}
 
-		origin := r.Header.Get("Origin")
-
-		// Check if origin is allowed and set appropriate CORS headers
-		if allowedOrigin == "*" {
-			// Wildcard: allow any origin
-			// Note: When using credentials, we must echo the specific origin, not "*"
-			// For now, we'll use "*" and note that credentials won't work with wildcard
-			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Session-Token")
-			// Cannot use credentials with "*", so we don't set Allow-Credentials
-		} else if origin == allowedOrigin {
-			// Specific origin match
-			w.Header().Set("Access-Control-Allow-Origin", origin)
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Session-Token")
-			w.Header().Set("Access-Control-Allow-Credentials", "true")
-		} else if origin != "" {
-			// Origin provided but doesn't match - log warning and don't set CORS headers
-			slog.Warn("rejected CORS request", "origin", origin, "allowed", allowedOrigin)
-		}
+	// Persist rooms last, after in-flight requests have finished
+	persisted, flushErr := srv.FlushStore()
+	if flushErr != nil {
+		slog.Error("failed to persist rooms", "error", flushErr)
+	} else if !persisted {
+		slog.Warn("rooms are not persisted, set DATA_DIR to keep them across restarts")
+	}
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
+	if shutdownErr != nil || flushErr != nil {
+		os.Exit(1)
+	}
 
-		next.ServeHTTP(w, r)
-	})
+	slog.Info("server stopped")
 }
 
 // cleanupRoutine periodically cleans up stale rooms.
-func cleanupRoutine(ctx context.Context, store store.Store) {
-	ticker := time.NewTicker(1 * time.Hour)
+func cleanupRoutine(ctx context.Context, store store.Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -161,9 +269,27 @@ func cleanupRoutine(ctx context.Context, store store.Store) {
 	}
 }
 
+// flushRoutine periodically writes every room to the file store.
+func flushRoutine(ctx context.Context, fileStore *store.FileStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("flush routine shutting down")
+			return
+		case <-ticker.C:
+			if err := fileStore.Flush(); err != nil {
+				slog.Error("flush error", "error", err)
+			}
+		}
+	}
+}
+
 // phaseCheckRoutine periodically checks if game phases should advance
-func phaseCheckRoutine(ctx context.Context, store store.Store, srv *server.Server) {
-	ticker := time.NewTicker(1 * time.Second) // Check every second
+func phaseCheckRoutine(ctx context.Context, store store.Store, srv *server.Server, tick time.Duration) {
+	ticker := time.NewTicker(tick)
 	defer ticker.Stop()
 
 	for {
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIssuer_Invite(t *testing.T) {
+	t.Parallel()
+
+	issuer, _ := NewIssuer(testKey(1), time.Hour)
+	start := time.Unix(1_700_000_000, 0)
+	issuer.now = func() time.Time { return start }
+
+	created := start.Add(-time.Hour)
+	invite, expiresAt, err := issuer.IssueInvite("ABC123", created, 30*time.Minute)
+	if err != nil {
+		t.Fatalf("IssueInvite() error = %v", err)
+	}
+	if !expiresAt.Equal(start.Add(30 * time.Minute)) {
+		t.Errorf("expected expiry %v, got %v", start.Add(30*time.Minute), expiresAt)
+	}
+
+	claims, err := issuer.VerifyInvite(invite)
+	if err != nil {
+		t.Fatalf("VerifyInvite() error = %v", err)
+	}
+	if !claims.ForRoom("ABC123", created) {
+		t.Errorf("expected invite for room ABC123 created at %v, got %+v", created, claims)
+	}
+	if claims.ForRoom("ABC123", start) {
+		t.Error("expected invite not to admit a later room reusing the code")
+	}
+
+	issuer.now = func() time.Time { return start.Add(30 * time.Minute) }
+	if _, err := issuer.VerifyInvite(invite); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("expected ErrExpiredToken, got %v", err)
+	}
+
+	if _, _, err := issuer.IssueInvite("ABC123", created, 0); err == nil {
+		t.Error("expected error for zero lifetime")
+	}
+}
+
+func TestIssuer_InviteAndSessionAreDistinct(t *testing.T) {
+	t.Parallel()
+
+	issuer, _ := NewIssuer(testKey(1), time.Hour)
+
+	session, _ := issuer.Issue("ABC123", "player-1")
+	if _, err := issuer.VerifyInvite(session); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected session token to be rejected as invite, got %v", err)
+	}
+
+	invite, _, _ := issuer.IssueInvite("ABC123", time.Now(), time.Hour)
+	if _, err := issuer.Verify(invite); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected invite to be rejected as session token, got %v", err)
+	}
+
+	other, _ := NewIssuer(testKey(2), time.Hour)
+	if _, err := other.VerifyInvite(invite); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected invite signed with another key to be rejected, got %v", err)
+	}
+}
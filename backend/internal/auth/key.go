@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DecodeKey parses a base64 signing key, as given in configuration.
+func DecodeKey(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		key, err := encoding.DecodeString(encoded)
+		if err != nil {
+			continue
+		}
+		if len(key) < KeySize {
+			return nil, fmt.Errorf("signing key must be at least %d bytes, got %d", KeySize, len(key))
+		}
+		return key, nil
+	}
+	return nil, errors.New("signing key is not valid base64")
+}
+
+// EncodeKey formats a signing key for configuration.
+func EncodeKey(key []byte) string {
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// LoadOrCreateKey reads the signing key stored at path, creating the file
+// with a new random key if it does not exist. Keeping the key across restarts
+// keeps persisted sessions valid.
+func LoadOrCreateKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return DecodeKey(string(data))
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	key, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// O_EXCL so concurrent starts never overwrite each other's key
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return LoadOrCreateKey(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.WriteString(EncodeKey(key) + "\n"); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodeKey(t *testing.T) {
+	t.Parallel()
+
+	key := testKey(7)
+
+	tests := []struct {
+		name    string
+		encoded string
+		wantErr bool
+	}{
+		{name: "standard", encoded: base64.StdEncoding.EncodeToString(key)},
+		{name: "raw standard", encoded: base64.RawStdEncoding.EncodeToString(key)},
+		{name: "url", encoded: base64.URLEncoding.EncodeToString(key)},
+		{name: "surrounding whitespace", encoded: " " + EncodeKey(key) + "\n"},
+		{name: "too short", encoded: base64.StdEncoding.EncodeToString(key[:16]), wantErr: true},
+		{name: "not base64", encoded: "not a key!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := DecodeKey(tt.encoded)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeKey() error = %v", err)
+			}
+			if !bytes.Equal(got, key) {
+				t.Errorf("DecodeKey() = %x, want %x", got, key)
+			}
+		})
+	}
+}
+
+func TestLoadOrCreateKey(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "session.key")
+
+	created, err := LoadOrCreateKey(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateKey() error = %v", err)
+	}
+	if len(created) != KeySize {
+		t.Errorf("expected %d byte key, got %d", KeySize, len(created))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected key file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected mode 0600, got %o", perm)
+	}
+
+	loaded, err := LoadOrCreateKey(path)
+	if err != nil {
+		t.Fatalf("LoadOrCreateKey() reload error = %v", err)
+	}
+	if !bytes.Equal(loaded, created) {
+		t.Error("expected the stored key to be reused")
+	}
+}
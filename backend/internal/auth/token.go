@@ -0,0 +1,158 @@
+// Package auth issues and verifies player session tokens and room invites.
+//
+// Both are HMAC-SHA256 signed claims with an expiry time:
+//
+//	base64url(claims JSON) "." base64url(signature)
+//
+// A session token binds a player to a room. Signature and expiry are checked
+// here; whether the token is still the player's current one (it may have been
+// rotated or revoked) is checked against the room.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// KeySize is the length of signing keys in bytes.
+const KeySize = 32
+
+// nonceSize is the number of random bytes in every token, so tokens are
+// unguessable even for a known room, player and time.
+const nonceSize = 16
+
+var (
+	ErrInvalidToken = errors.New("invalid session token")
+	ErrExpiredToken = errors.New("session token expired")
+)
+
+// Claims are the facts a session token vouches for.
+type Claims struct {
+	RoomCode  string `json:"r"`
+	PlayerID  string `json:"p"`
+	IssuedAt  int64  `json:"iat"` // Unix seconds
+	ExpiresAt int64  `json:"exp"` // Unix seconds
+	Nonce     string `json:"n"`
+}
+
+// Issuer signs and verifies session tokens with a secret key.
+// It is safe for concurrent use.
+type Issuer struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time // Replaced in tests
+}
+
+// NewIssuer creates an issuer whose tokens are valid for ttl.
+func NewIssuer(key []byte, ttl time.Duration) (*Issuer, error) {
+	if len(key) < KeySize {
+		return nil, fmt.Errorf("signing key must be at least %d bytes, got %d", KeySize, len(key))
+	}
+	if ttl <= 0 {
+		return nil, errors.New("token lifetime must be positive")
+	}
+
+	return &Issuer{
+		key: append([]byte(nil), key...),
+		ttl: ttl,
+		now: time.Now,
+	}, nil
+}
+
+// GenerateKey returns a new random signing key.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// TTL returns how long issued tokens are valid.
+func (i *Issuer) TTL() time.Duration {
+	return i.ttl
+}
+
+// Issue creates a token for a player in a room.
+func (i *Issuer) Issue(roomCode, playerID string) (string, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	now := i.now()
+	claims := Claims{
+		RoomCode:  roomCode,
+		PlayerID:  playerID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(i.ttl).Unix(),
+		Nonce:     base64.RawURLEncoding.EncodeToString(nonce),
+	}
+
+	return i.seal(claims)
+}
+
+// Verify checks a token's signature and expiry and returns its claims.
+func (i *Issuer) Verify(token string) (Claims, error) {
+	var claims Claims
+	if err := i.open(token, &claims); err != nil {
+		return Claims{}, err
+	}
+	if claims.RoomCode == "" || claims.PlayerID == "" {
+		return Claims{}, ErrInvalidToken
+	}
+
+	if i.now().Unix() >= claims.ExpiresAt {
+		return Claims{}, ErrExpiredToken
+	}
+
+	return claims, nil
+}
+
+// seal encodes and signs claims.
+func (i *Issuer) seal(claims interface{}) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(i.sign(encoded)), nil
+}
+
+// open checks a token's signature and decodes its claims.
+func (i *Issuer) open(token string, claims interface{}) error {
+	encoded, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, i.sign(encoded)) {
+		return ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// sign returns the HMAC of the encoded claims.
+func (i *Issuer) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, i.key)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
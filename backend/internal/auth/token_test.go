@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testKey(b byte) []byte {
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = b
+	}
+	return key
+}
+
+func TestNewIssuer_Invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewIssuer(make([]byte, KeySize-1), time.Hour); err == nil {
+		t.Error("expected error for short key")
+	}
+	if _, err := NewIssuer(testKey(1), 0); err == nil {
+		t.Error("expected error for zero lifetime")
+	}
+}
+
+func TestIssuer_IssueVerify(t *testing.T) {
+	t.Parallel()
+
+	issuer, err := NewIssuer(testKey(1), time.Hour)
+	if err != nil {
+		t.Fatalf("NewIssuer() error = %v", err)
+	}
+
+	token, err := issuer.Issue("ABC123", "player-1")
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	claims, err := issuer.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if claims.RoomCode != "ABC123" || claims.PlayerID != "player-1" {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+	if claims.ExpiresAt-claims.IssuedAt != int64(time.Hour/time.Second) {
+		t.Errorf("expected one hour lifetime, got %ds", claims.ExpiresAt-claims.IssuedAt)
+	}
+
+	other, _ := issuer.Issue("ABC123", "player-1")
+	if other == token {
+		t.Error("expected tokens for the same player to differ")
+	}
+}
+
+func TestIssuer_Verify_Rejects(t *testing.T) {
+	t.Parallel()
+
+	issuer, _ := NewIssuer(testKey(1), time.Hour)
+	token, _ := issuer.Issue("ABC123", "player-1")
+	claims, signature, _ := strings.Cut(token, ".")
+
+	otherIssuer, _ := NewIssuer(testKey(2), time.Hour)
+	forged, _ := otherIssuer.Issue("ABC123", "player-1")
+	otherClaims, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "no signature", token: claims},
+		{name: "tampered signature", token: claims + "." + signature[:len(signature)-2] + "AA"},
+		{name: "swapped claims", token: otherClaims + "." + signature},
+		{name: "other key", token: forged},
+		{name: "bad encoding", token: "!!!." + signature},
+		{name: "legacy token", token: "dG9rZW4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := issuer.Verify(tt.token); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+}
+
+func TestIssuer_Verify_Expired(t *testing.T) {
+	t.Parallel()
+
+	issuer, _ := NewIssuer(testKey(1), time.Hour)
+	start := time.Unix(1_700_000_000, 0)
+	issuer.now = func() time.Time { return start }
+
+	token, _ := issuer.Issue("ABC123", "player-1")
+
+	issuer.now = func() time.Time { return start.Add(59 * time.Minute) }
+	if _, err := issuer.Verify(token); err != nil {
+		t.Errorf("expected token valid before expiry, got %v", err)
+	}
+
+	issuer.now = func() time.Time { return start.Add(time.Hour) }
+	if _, err := issuer.Verify(token); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("expected ErrExpiredToken, got %v", err)
+	}
+}
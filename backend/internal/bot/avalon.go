@@ -0,0 +1,123 @@
+package bot
+
+import (
+	"math/rand/v2"
+	"sort"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/games/avalon"
+)
+
+func init() {
+	Register("avalon", StrategyRandom, func(rng *rand.Rand) Strategy {
+		return &avalonRandom{rng: rng}
+	})
+}
+
+// avalonRandom plays Avalon by coin flips: random teams, random votes, and
+// fail cards half the time when evil. It approves the fifth proposal of a
+// quest so games don't end on rejections alone.
+type avalonRandom struct {
+	rng *rand.Rand
+}
+
+func (s *avalonRandom) Observe(core.GameEvent) {}
+
+func (s *avalonRandom) Decide(view View) (core.Action, bool) {
+	state, ok := view.Player.(avalon.PlayerState)
+	if !ok {
+		return core.Action{}, false
+	}
+	public, ok := view.Public.(avalon.PublicState)
+	if !ok {
+		return core.Action{}, false
+	}
+
+	switch {
+	case state.Phase == avalon.PhaseRoleReveal && !state.HasAcknowledged:
+		return newAction("acknowledge_role", struct{}{})
+
+	case state.CanProposeTeam:
+		return proposeTeam(pick(s.rng, view.Players, public.RequiredTeamSize))
+
+	case state.CanVote:
+		if state.RejectionCount >= 4 || s.rng.IntN(2) == 0 {
+			return voteTeam(avalon.VoteApprove)
+		}
+		return voteTeam(avalon.VoteReject)
+
+	case state.CanPlayQuestCard:
+		if state.Team == avalon.TeamEvil && s.rng.IntN(2) == 0 {
+			return playQuestCard(avalon.CardFail)
+		}
+		return playQuestCard(avalon.CardSuccess)
+
+	case state.CanAssassinate:
+		// Known players are fellow evil (Oberon aside), so never Merlin
+		candidates := exclude(view.Others(), state.Knowledge)
+		if len(candidates) == 0 {
+			candidates = view.Others()
+		}
+		if len(candidates) == 0 {
+			return core.Action{}, false
+		}
+		return assassinate(pick(s.rng, candidates, 1)[0])
+	}
+
+	return core.Action{}, false
+}
+
+// Avalon actions.
+
+func proposeTeam(team []string) (core.Action, bool) {
+	return newAction("propose_team", map[string]interface{}{"team_members": team})
+}
+
+func voteTeam(vote avalon.Vote) (core.Action, bool) {
+	return newAction("vote_team", map[string]interface{}{"vote": vote})
+}
+
+func playQuestCard(card avalon.QuestCard) (core.Action, bool) {
+	return newAction("play_quest_card", map[string]interface{}{"card": card})
+}
+
+func assassinate(targetID string) (core.Action, bool) {
+	return newAction("assassinate", map[string]interface{}{"target_id": targetID})
+}
+
+// pick returns n distinct players chosen at random (all of them if fewer).
+func pick(rng *rand.Rand, players []string, n int) []string {
+	shuffled := append([]string(nil), players...)
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	if n > len(shuffled) {
+		n = len(shuffled)
+	}
+	return shuffled[:n]
+}
+
+// ranked orders players by score, lowest first, breaking ties at random.
+func ranked(rng *rand.Rand, players []string, score map[string]float64) []string {
+	ordered := pick(rng, players, len(players))
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return score[ordered[i]] < score[ordered[j]]
+	})
+	return ordered
+}
+
+// exclude returns the players not in excluded.
+func exclude(players []string, excluded []string) []string {
+	skip := make(map[string]bool, len(excluded))
+	for _, id := range excluded {
+		skip[id] = true
+	}
+
+	kept := make([]string, 0, len(players))
+	for _, id := range players {
+		if !skip[id] {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,409 @@
+package bot
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand/v2"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/games/avalon"
+)
+
+func init() {
+	Register("avalon", StrategyHeuristic, func(rng *rand.Rand) Strategy {
+		return &avalonHeuristic{rng: rng}
+	})
+}
+
+// How players are assumed to behave when weighing who is evil. Evil players
+// fail most quests they go on and back teams with evil on them; good players
+// lean the other way, but only slightly, since most of them are guessing.
+const (
+	evilFailChance    = 0.8
+	evilProposesEvil  = 0.9
+	goodProposesEvil  = 0.5
+	evilApprovesEvil  = 0.85
+	evilApprovesClean = 0.4
+	goodApprovesEvil  = 0.45
+	goodApprovesClean = 0.65
+)
+
+// approveMargin is how much more expected evil than the best possible team a
+// good bot accepts on a proposed team.
+const approveMargin = 0.3
+
+// proposal is a team proposal and, once the vote closes, how everyone voted.
+type proposal struct {
+	avalon.TeamProposedPayload
+	Votes map[string]avalon.Vote
+}
+
+// avalonHeuristic plays Avalon by estimating how likely each player is to be
+// evil. It weighs every assignment of the evil seats that fits what it knows
+// by how well it explains the quest results, proposals and votes so far.
+// Good bots send and approve the least suspicious teams; evil bots back teams
+// with evil on them, fail quests when it pays, and the assassin picks as
+// Merlin whoever voted as if they could see evil.
+type avalonHeuristic struct {
+	rng *rand.Rand
+
+	proposed  avalon.TeamProposedPayload // Latest proposal, from team_proposed
+	proposals []proposal                 // Proposals that were voted on
+}
+
+func (s *avalonHeuristic) Observe(event core.GameEvent) {
+	switch event.Type {
+	case "team_proposed":
+		var payload avalon.TeamProposedPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			s.proposed = payload
+		}
+	case "team_vote_result":
+		var payload avalon.TeamVoteResultPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			s.proposals = append(s.proposals, proposal{s.proposed, payload.Votes})
+		}
+	}
+}
+
+func (s *avalonHeuristic) Decide(view View) (core.Action, bool) {
+	state, ok := view.Player.(avalon.PlayerState)
+	if !ok {
+		return core.Action{}, false
+	}
+	public, ok := view.Public.(avalon.PublicState)
+	if !ok {
+		return core.Action{}, false
+	}
+
+	switch {
+	case state.Phase == avalon.PhaseRoleReveal && !state.HasAcknowledged:
+		return newAction("acknowledge_role", struct{}{})
+
+	case state.CanProposeTeam:
+		return proposeTeam(s.team(view, state, public))
+
+	case state.CanVote:
+		return voteTeam(s.vote(view, state, public))
+
+	case state.CanPlayQuestCard:
+		return playQuestCard(s.questCard(view, state, public))
+
+	case state.CanAssassinate:
+		if target := s.merlin(view, state); target != "" {
+			return assassinate(target)
+		}
+	}
+
+	return core.Action{}, false
+}
+
+// team picks a team of the required size with the bot on it. Good bots add
+// the least suspicious players; evil bots add players that look good, and a
+// fellow evil player when the quest takes two fails.
+func (s *avalonHeuristic) team(view View, state avalon.PlayerState, public avalon.PublicState) []string {
+	size := public.RequiredTeamSize
+	team := []string{view.PlayerID}
+
+	if state.Team == avalon.TeamEvil {
+		candidates := pick(s.rng, exclude(view.Others(), state.Knowledge), size-1)
+		fellows := pick(s.rng, state.Knowledge, 1)
+		if avalon.FailsRequired(len(view.Players), state.QuestNumber) > 1 && len(fellows) > 0 && len(candidates) > 0 {
+			candidates[len(candidates)-1] = fellows[0]
+		}
+		return append(team, candidates...)
+	}
+
+	suspicion := s.suspicion(view, state, public.QuestResults)
+	for _, id := range ranked(s.rng, view.Others(), suspicion) {
+		if len(team) == size {
+			break
+		}
+		team = append(team, id)
+	}
+	return team
+}
+
+// vote decides on the proposed team. Evil bots approve teams with evil on
+// them, and reject clean ones even when a fifth rejection hands evil the
+// game. Good bots approve teams close to the least suspicious they could
+// field, and always approve the fifth proposal.
+func (s *avalonHeuristic) vote(view View, state avalon.PlayerState, public avalon.PublicState) avalon.Vote {
+	if state.Team == avalon.TeamEvil {
+		if s.knownEvilOn(view, state, public.ProposedTeam) > 0 {
+			return avalon.VoteApprove
+		}
+		return avalon.VoteReject
+	}
+
+	if state.RejectionCount >= 4 {
+		return avalon.VoteApprove
+	}
+
+	suspicion := s.suspicion(view, state, public.QuestResults)
+	best := 0.0
+	for _, id := range ranked(s.rng, view.Players, suspicion)[:min(len(public.ProposedTeam), len(view.Players))] {
+		best += suspicion[id]
+	}
+	proposed := 0.0
+	for _, id := range public.ProposedTeam {
+		proposed += suspicion[id]
+	}
+
+	if proposed <= best+approveMargin {
+		return avalon.VoteApprove
+	}
+	return avalon.VoteReject
+}
+
+// questCard plays success when good. Evil bots fail whenever the quest
+// decides the game or needs two fails; otherwise the first evil player on the
+// team they know of fails alone, so a quest doesn't show more fails than it
+// needs, and on the first quest it sometimes succeeds to keep its cover.
+func (s *avalonHeuristic) questCard(view View, state avalon.PlayerState, public avalon.PublicState) avalon.QuestCard {
+	if state.Team != avalon.TeamEvil {
+		return avalon.CardSuccess
+	}
+
+	switch {
+	case state.GoodQuestWins == 2 || state.EvilQuestWins == 2:
+		return avalon.CardFail
+	case avalon.FailsRequired(len(view.Players), state.QuestNumber) > 1:
+		return avalon.CardFail
+	}
+
+	for _, id := range view.Players {
+		if id != view.PlayerID && contains(state.Knowledge, id) && contains(public.ProposedTeam, id) {
+			return avalon.CardSuccess
+		}
+		if id == view.PlayerID {
+			break
+		}
+	}
+
+	if state.QuestNumber == 1 && s.rng.IntN(2) == 0 {
+		return avalon.CardSuccess
+	}
+	return avalon.CardFail
+}
+
+// merlin guesses which good player is Merlin: Merlin sees evil, so they
+// propose clean teams and vote against teams with evil on them.
+func (s *avalonHeuristic) merlin(view View, state avalon.PlayerState) string {
+	// Known players are fellow evil (Oberon aside), so never Merlin
+	candidates := exclude(view.Others(), state.Knowledge)
+	if len(candidates) == 0 {
+		candidates = view.Others()
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	score := make(map[string]int)
+	for _, p := range s.proposals {
+		tainted := s.knownEvilOn(view, state, p.TeamMembers) > 0
+		if tainted {
+			score[p.LeaderID]--
+		} else {
+			score[p.LeaderID]++
+		}
+		for voter, vote := range p.Votes {
+			if (vote == avalon.VoteReject) == tainted {
+				score[voter]++
+			} else {
+				score[voter]--
+			}
+		}
+	}
+
+	candidates = pick(s.rng, candidates, len(candidates))
+	guess := candidates[0]
+	for _, id := range candidates[1:] {
+		if score[id] > score[guess] {
+			guess = id
+		}
+	}
+	return guess
+}
+
+// knownEvilOn counts the players on team the bot knows to be evil, itself
+// included.
+func (s *avalonHeuristic) knownEvilOn(view View, state avalon.PlayerState, team []string) int {
+	count := 0
+	for _, id := range team {
+		if (id == view.PlayerID && state.Team == avalon.TeamEvil) || contains(state.Knowledge, id) {
+			count++
+		}
+	}
+	return count
+}
+
+// suspicion estimates the chance that each player is evil from what a good
+// bot knows: its own role, what Merlin or Percival sees, and the history of
+// quests, proposals and votes.
+func (s *avalonHeuristic) suspicion(view View, state avalon.PlayerState, quests []avalon.QuestResult) map[string]float64 {
+	_, evilCount := avalon.TeamSizes(len(view.Players))
+
+	evil := make(map[string]bool)
+	good := map[string]bool{view.PlayerID: true}
+	var pair []string // Exactly one is evil: Percival's Merlin and Morgana
+
+	switch state.Role {
+	case avalon.RoleMerlin:
+		for _, id := range state.Knowledge {
+			evil[id] = true
+		}
+	case avalon.RolePercival:
+		if len(state.Knowledge) == 2 {
+			pair = state.Knowledge
+		} else {
+			for _, id := range state.Knowledge {
+				good[id] = true
+			}
+		}
+	}
+
+	var unknown []string
+	for _, id := range view.Players {
+		if !evil[id] && !good[id] {
+			unknown = append(unknown, id)
+		}
+	}
+	slots := evilCount - len(evil)
+
+	weights := make(map[string]float64)
+	total := 0.0
+	combinations(unknown, slots, func(chosen []string) {
+		world := make(map[string]bool, evilCount)
+		for id := range evil {
+			world[id] = true
+		}
+		for _, id := range chosen {
+			world[id] = true
+		}
+		if len(pair) == 2 && world[pair[0]] == world[pair[1]] {
+			return
+		}
+
+		weight := s.likelihood(world, quests)
+		total += weight
+		for id := range world {
+			weights[id] += weight
+		}
+	})
+
+	suspicion := make(map[string]float64, len(view.Players))
+	for id := range evil {
+		suspicion[id] = 1
+	}
+	if total == 0 {
+		// Nothing fits what the bot assumed; fall back to even odds
+		for _, id := range unknown {
+			suspicion[id] = float64(max(slots, 0)) / float64(len(unknown))
+		}
+		return suspicion
+	}
+	for _, id := range unknown {
+		suspicion[id] = weights[id] / total
+	}
+	return suspicion
+}
+
+// likelihood is how well a world, the set of evil players, explains the
+// quest results, proposals and votes so far.
+func (s *avalonHeuristic) likelihood(world map[string]bool, quests []avalon.QuestResult) float64 {
+	evilOn := func(team []string) int {
+		count := 0
+		for _, id := range team {
+			if world[id] {
+				count++
+			}
+		}
+		return count
+	}
+
+	likelihood := 1.0
+	for _, quest := range quests {
+		onTeam := evilOn(quest.TeamMembers)
+		if quest.FailCount > onTeam {
+			return 0
+		}
+		likelihood *= binomial(onTeam, quest.FailCount) *
+			math.Pow(evilFailChance, float64(quest.FailCount)) *
+			math.Pow(1-evilFailChance, float64(onTeam-quest.FailCount))
+	}
+
+	for _, p := range s.proposals {
+		tainted := evilOn(p.TeamMembers) > 0
+		if world[p.LeaderID] {
+			likelihood *= chance(tainted, evilProposesEvil)
+		} else {
+			likelihood *= chance(tainted, goodProposesEvil)
+		}
+
+		for voter, vote := range p.Votes {
+			approves := goodApprovesClean
+			switch {
+			case world[voter] && tainted:
+				approves = evilApprovesEvil
+			case world[voter]:
+				approves = evilApprovesClean
+			case tainted:
+				approves = goodApprovesEvil
+			}
+			likelihood *= chance(vote == avalon.VoteApprove, approves)
+		}
+	}
+	return likelihood
+}
+
+// chance is the probability of an outcome that happens with probability p
+// having happened or not.
+func chance(happened bool, p float64) float64 {
+	if happened {
+		return p
+	}
+	return 1 - p
+}
+
+// binomial returns n choose k.
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
+
+// combinations calls visit with every way of choosing k of players. visit
+// must not keep the slice.
+func combinations(players []string, k int, visit func(chosen []string)) {
+	if k < 0 || k > len(players) {
+		return
+	}
+
+	chosen := make([]string, 0, k)
+	var walk func(start int)
+	walk = func(start int) {
+		if len(chosen) == k {
+			visit(chosen)
+			return
+		}
+		for i := start; i <= len(players)-(k-len(chosen)); i++ {
+			chosen = append(chosen, players[i])
+			walk(i + 1)
+			chosen = chosen[:len(chosen)-1]
+		}
+	}
+	walk(0)
+}
+
+// contains reports whether players includes id.
+func contains(players []string, id string) bool {
+	for _, player := range players {
+		if player == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,256 @@
+package bot
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"sort"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/games/avalon"
+)
+
+// heuristicView seats the bot as p1 at a five player table.
+func heuristicView(state avalon.PlayerState, public avalon.PublicState) View {
+	return View{
+		GameView: core.GameView{Player: state, Public: public},
+		PlayerID: "p1",
+		Players:  []string{"p1", "p2", "p3", "p4", "p5"},
+	}
+}
+
+// observeVote shows the strategy a proposal and how it was voted.
+func observeVote(s Strategy, leaderID string, team []string, votes map[string]avalon.Vote) {
+	proposed, _ := core.NewPublicEvent("team_proposed", leaderID, avalon.TeamProposedPayload{
+		LeaderID:    leaderID,
+		TeamMembers: team,
+	})
+	result, _ := core.NewPublicEvent("team_vote_result", "system", avalon.TeamVoteResultPayload{Votes: votes})
+	s.Observe(proposed)
+	s.Observe(result)
+}
+
+// failedFirstQuest is a table where p2 and p3 failed the first quest.
+var failedFirstQuest = []avalon.QuestResult{{QuestNumber: 1, TeamMembers: []string{"p2", "p3"}, FailCount: 1}}
+
+func TestAvalonHeuristic_Suspicion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		state     avalon.PlayerState
+		quests    []avalon.QuestResult
+		suspected []string // More suspicious than the rest
+	}{
+		{
+			name:      "failed quest",
+			state:     avalon.PlayerState{Role: avalon.RoleLoyalServant, Team: avalon.TeamGood},
+			quests:    failedFirstQuest,
+			suspected: []string{"p2", "p3"},
+		},
+		{
+			name:      "merlin sees evil",
+			state:     avalon.PlayerState{Role: avalon.RoleMerlin, Team: avalon.TeamGood, Knowledge: []string{"p4", "p5"}},
+			quests:    failedFirstQuest,
+			suspected: []string{"p4", "p5"},
+		},
+		{
+			name:      "percival's pair",
+			state:     avalon.PlayerState{Role: avalon.RolePercival, Team: avalon.TeamGood, Knowledge: []string{"p2", "p5"}},
+			quests:    failedFirstQuest,
+			suspected: []string{"p2", "p3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &avalonHeuristic{rng: rand.New(rand.NewPCG(1, 2))}
+			view := heuristicView(tt.state, avalon.PublicState{})
+			suspicion := s.suspicion(view, tt.state, tt.quests)
+
+			total := 0.0
+			for _, odds := range suspicion {
+				total += odds
+			}
+			if total < 1.99 || total > 2.01 {
+				t.Errorf("expected suspicion to add up to the 2 evil seats, got %v", suspicion)
+			}
+			if suspicion["p1"] != 0 {
+				t.Errorf("expected the bot not to suspect itself, got %v", suspicion["p1"])
+			}
+
+			for _, suspect := range tt.suspected {
+				for _, other := range exclude(view.Others(), tt.suspected) {
+					if suspicion[suspect] <= suspicion[other] {
+						t.Errorf("expected %s to be more suspicious than %s, got %v", suspect, other, suspicion)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAvalonHeuristic_Decide(t *testing.T) {
+	t.Parallel()
+
+	good := avalon.PlayerState{Role: avalon.RoleLoyalServant, Team: avalon.TeamGood, QuestNumber: 2}
+	evil := avalon.PlayerState{Role: avalon.RoleAssassin, Team: avalon.TeamEvil, Knowledge: []string{"p2"}, QuestNumber: 2}
+
+	with := func(state avalon.PlayerState, change func(*avalon.PlayerState)) avalon.PlayerState {
+		change(&state)
+		return state
+	}
+	team := func(members ...string) avalon.PublicState {
+		return avalon.PublicState{QuestResults: failedFirstQuest, RequiredTeamSize: 3, ProposedTeam: members}
+	}
+
+	tests := []struct {
+		name        string
+		seat        string // Defaults to p1
+		state       avalon.PlayerState
+		public      avalon.PublicState
+		wantType    string
+		wantPayload map[string]interface{}
+	}{
+		{
+			name:        "good proposes the least suspicious",
+			state:       with(good, func(s *avalon.PlayerState) { s.CanProposeTeam = true }),
+			public:      team(),
+			wantType:    "propose_team",
+			wantPayload: map[string]interface{}{"team_members": []interface{}{"p1", "p4", "p5"}},
+		},
+		{
+			name:        "good rejects suspects",
+			state:       with(good, func(s *avalon.PlayerState) { s.CanVote = true }),
+			public:      team("p2", "p3", "p4"),
+			wantType:    "vote_team",
+			wantPayload: map[string]interface{}{"vote": "reject"},
+		},
+		{
+			name:        "good approves a clean team",
+			state:       with(good, func(s *avalon.PlayerState) { s.CanVote = true }),
+			public:      team("p1", "p4", "p5"),
+			wantType:    "vote_team",
+			wantPayload: map[string]interface{}{"vote": "approve"},
+		},
+		{
+			name:        "good approves the fifth proposal",
+			state:       with(good, func(s *avalon.PlayerState) { s.CanVote = true; s.RejectionCount = 4 }),
+			public:      team("p2", "p3", "p4"),
+			wantType:    "vote_team",
+			wantPayload: map[string]interface{}{"vote": "approve"},
+		},
+		{
+			name:        "evil approves evil",
+			state:       with(evil, func(s *avalon.PlayerState) { s.CanVote = true }),
+			public:      team("p2", "p3", "p4"),
+			wantType:    "vote_team",
+			wantPayload: map[string]interface{}{"vote": "approve"},
+		},
+		{
+			name:        "evil rejects a clean team",
+			state:       with(evil, func(s *avalon.PlayerState) { s.CanVote = true; s.RejectionCount = 4 }),
+			public:      team("p3", "p4", "p5"),
+			wantType:    "vote_team",
+			wantPayload: map[string]interface{}{"vote": "reject"},
+		},
+		{
+			name:        "evil fails alone",
+			state:       with(evil, func(s *avalon.PlayerState) { s.CanPlayQuestCard = true }),
+			public:      team("p1", "p3", "p4"),
+			wantType:    "play_quest_card",
+			wantPayload: map[string]interface{}{"card": "fail"},
+		},
+		{
+			name: "evil leaves the fail to a fellow",
+			seat: "p2",
+			state: with(evil, func(s *avalon.PlayerState) {
+				s.CanPlayQuestCard = true
+				s.Knowledge = []string{"p1"}
+			}),
+			public:      avalon.PublicState{ProposedTeam: []string{"p1", "p2", "p4"}},
+			wantType:    "play_quest_card",
+			wantPayload: map[string]interface{}{"card": "success"},
+		},
+		{
+			name: "evil fails the deciding quest",
+			state: with(evil, func(s *avalon.PlayerState) {
+				s.CanPlayQuestCard = true
+				s.Knowledge = []string{"p1"}
+				s.EvilQuestWins = 2
+			}),
+			public:      avalon.PublicState{ProposedTeam: []string{"p1", "p2", "p4"}},
+			wantType:    "play_quest_card",
+			wantPayload: map[string]interface{}{"card": "fail"},
+		},
+		{
+			name:        "good always succeeds",
+			state:       with(good, func(s *avalon.PlayerState) { s.CanPlayQuestCard = true }),
+			public:      team("p1", "p4", "p5"),
+			wantType:    "play_quest_card",
+			wantPayload: map[string]interface{}{"card": "success"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &avalonHeuristic{rng: rand.New(rand.NewPCG(1, 2))}
+			view := heuristicView(tt.state, tt.public)
+			if tt.seat != "" {
+				view.PlayerID = tt.seat
+			}
+
+			action, ok := s.Decide(view)
+			if !ok || action.Type != tt.wantType {
+				t.Fatalf("expected %s, got %+v", tt.wantType, action)
+			}
+			var payload map[string]interface{}
+			json.Unmarshal(action.Payload, &payload)
+			if members, ok := payload["team_members"].([]interface{}); ok {
+				sort.Slice(members, func(i, j int) bool { return members[i].(string) < members[j].(string) })
+			}
+			if got, want := mustJSON(payload), mustJSON(tt.wantPayload); got != want {
+				t.Errorf("expected payload %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestAvalonHeuristic_FindsMerlin(t *testing.T) {
+	t.Parallel()
+
+	s := &avalonHeuristic{rng: rand.New(rand.NewPCG(1, 2))}
+
+	// p4 rejects the team with evil on it and proposes a clean one
+	observeVote(s, "p3", []string{"p1", "p3"}, map[string]avalon.Vote{
+		"p1": avalon.VoteApprove, "p2": avalon.VoteApprove, "p3": avalon.VoteApprove,
+		"p4": avalon.VoteReject, "p5": avalon.VoteApprove,
+	})
+	observeVote(s, "p4", []string{"p4", "p5"}, map[string]avalon.Vote{
+		"p1": avalon.VoteReject, "p2": avalon.VoteReject, "p3": avalon.VoteReject,
+		"p4": avalon.VoteApprove, "p5": avalon.VoteApprove,
+	})
+
+	state := avalon.PlayerState{Role: avalon.RoleAssassin, Team: avalon.TeamEvil, Knowledge: []string{"p2"}, CanAssassinate: true}
+	action, ok := s.Decide(heuristicView(state, avalon.PublicState{}))
+	if !ok || action.Type != "assassinate" {
+		t.Fatalf("expected the assassin to act, got %+v", action)
+	}
+	var payload struct {
+		TargetID string `json:"target_id"`
+	}
+	json.Unmarshal(action.Payload, &payload)
+	if payload.TargetID != "p4" {
+		t.Errorf("expected the assassin to pick p4 as Merlin, got %s", payload.TargetID)
+	}
+}
+
+// mustJSON encodes v for comparison.
+func mustJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
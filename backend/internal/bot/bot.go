@@ -0,0 +1,311 @@
+// Package bot drives bot seats: players that an agent on the server plays
+// instead of a client. A bot sees exactly what a client in its seat would,
+// the events visible to it and its filtered player state, and acts through
+// the same action pipeline.
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// StrategyRandom picks uniformly among legal actions. Every game registers it.
+const StrategyRandom = "random"
+
+// StrategyHeuristic reasons about what the bot has seen to play to win.
+const StrategyHeuristic = "heuristic"
+
+// ErrUnknownStrategy is returned for strategies not registered for a game.
+var ErrUnknownStrategy = errors.New("unknown bot strategy")
+
+// Strategy decides what a bot does. Each bot gets a fresh Strategy per game,
+// and its methods are never called concurrently.
+type Strategy interface {
+	// Observe is called with each event the bot can see, in log order.
+	Observe(event core.GameEvent)
+
+	// Decide returns the bot's next action given what it can see now, or
+	// false to wait for the game to change.
+	Decide(view View) (core.Action, bool)
+}
+
+// View is what a bot can see of the game when deciding.
+type View struct {
+	core.GameView
+
+	PlayerID string   // The bot's own PlayerID
+	Players  []string // Everyone in the room, in join order
+}
+
+// Others returns the other players in the room.
+func (v View) Others() []string {
+	others := make([]string, 0, len(v.Players))
+	for _, id := range v.Players {
+		if id != v.PlayerID {
+			others = append(others, id)
+		}
+	}
+	return others
+}
+
+// StrategyFactory creates a strategy that draws its choices from rng.
+type StrategyFactory func(rng *rand.Rand) Strategy
+
+var strategies = make(map[string]map[string]StrategyFactory) // gameType → name → factory
+
+// Register makes a strategy available for a game type. It is meant to be
+// called from init functions.
+func Register(gameType, name string, factory StrategyFactory) {
+	if strategies[gameType] == nil {
+		strategies[gameType] = make(map[string]StrategyFactory)
+	}
+	strategies[gameType][name] = factory
+}
+
+// Strategies lists the strategies registered for a game type.
+func Strategies(gameType string) []string {
+	names := make([]string, 0, len(strategies[gameType]))
+	for name := range strategies[gameType] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasStrategy reports whether a strategy is registered for a game type.
+func HasStrategy(gameType, name string) bool {
+	_, exists := strategies[gameType][name]
+	return exists
+}
+
+// newStrategy creates a registered strategy.
+func newStrategy(gameType, name string, rng *rand.Rand) (Strategy, error) {
+	factory, exists := strategies[gameType][name]
+	if !exists {
+		return nil, fmt.Errorf("%w %q for %s", ErrUnknownStrategy, name, gameType)
+	}
+	return factory(rng), nil
+}
+
+// newAction builds an action with a JSON payload.
+func newAction(actionType string, payload interface{}) (core.Action, bool) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return core.Action{}, false
+	}
+	return core.Action{Type: actionType, Payload: data}, true
+}
+
+// Submitter runs a bot's action through the room and delivers the resulting
+// events, like the server does for actions from clients.
+type Submitter func(room *core.Room, playerID string, action core.Action) error
+
+// Bot drives one bot seat. It runs on its own goroutine only while it has
+// something to look at, so idle bots cost nothing.
+type Bot struct {
+	playerID string
+	room     *core.Room
+	settings core.BotSettings
+	submit   Submitter
+	rng      *rand.Rand
+	done     <-chan struct{} // Closed when the manager stops
+	wg       *sync.WaitGroup // The manager's running bots
+
+	mu      sync.Mutex
+	running bool // A goroutine is stepping the bot
+	pending bool // Notified while running, so look again
+	removed bool // No longer driven
+
+	// Only touched by the running goroutine
+	strategy Strategy
+	cursor   int    // Events consumed from the room's event log
+	lastID   string // ID of the last consumed event, to notice resets
+}
+
+// notify makes the bot look at the game, starting its goroutine if needed.
+func (b *Bot) notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.removed {
+		return
+	}
+	if b.running {
+		b.pending = true
+		return
+	}
+
+	select {
+	case <-b.done:
+		return
+	default:
+	}
+
+	b.running = true
+	b.wg.Add(1)
+	go b.run()
+}
+
+// remove stops the bot after its current step.
+func (b *Bot) remove() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removed = true
+}
+
+// run steps the bot until it has nothing left to do.
+func (b *Bot) run() {
+	defer b.wg.Done()
+
+	for {
+		b.mu.Lock()
+		b.pending = false
+		b.mu.Unlock()
+
+		acted := b.step()
+
+		b.mu.Lock()
+		if (!acted && !b.pending) || b.removed || b.stopped() {
+			b.running = false
+			b.mu.Unlock()
+			return
+		}
+		b.mu.Unlock()
+	}
+}
+
+// stopped reports whether the manager has stopped.
+func (b *Bot) stopped() bool {
+	select {
+	case <-b.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// step catches up on events, then asks the strategy for an action and
+// submits it after thinking. It reports whether an action was accepted.
+func (b *Bot) step() bool {
+	for _, event := range b.catchUp() {
+		if event.CanPlayerSee(b.playerID) {
+			b.strategy.Observe(event)
+		}
+	}
+
+	gameView := b.room.ViewGame(b.playerID)
+	if gameView == nil || gameView.Finished {
+		return false
+	}
+
+	action, ok := b.strategy.Decide(View{
+		GameView: *gameView,
+		PlayerID: b.playerID,
+		Players:  playerIDs(b.room),
+	})
+	if !ok {
+		return false
+	}
+
+	if !b.think() {
+		return false
+	}
+
+	if err := b.submit(b.room, b.playerID, action); err != nil {
+		// Usually the game moved on while the bot was thinking
+		slog.Warn("bot action rejected",
+			"roomCode", b.room.ID,
+			"playerID", b.playerID,
+			"action", action.Type,
+			"error", err,
+		)
+		return false
+	}
+	return true
+}
+
+// catchUp returns the events added to the room since the last step. When the
+// room was reset for a new game, the bot starts over with a fresh strategy.
+func (b *Bot) catchUp() []core.GameEvent {
+	if b.cursor > 0 {
+		events := b.room.GetEventsSince(b.cursor - 1)
+		if len(events) > 0 && events[0].ID == b.lastID {
+			b.advance(events[1:])
+			return events[1:]
+		}
+	}
+
+	if b.cursor > 0 || b.strategy == nil {
+		strategy, err := newStrategy(b.room.GameType, b.settings.Strategy, b.rng)
+		if err != nil {
+			// Checked when the bot was added
+			slog.Error("failed to create bot strategy", "playerID", b.playerID, "error", err)
+			strategy = idle{}
+		}
+		b.strategy = strategy
+		b.cursor = 0
+	}
+
+	events := b.room.GetEventsSince(0)
+	b.advance(events)
+	return events
+}
+
+// advance moves the cursor past events.
+func (b *Bot) advance(events []core.GameEvent) {
+	if len(events) > 0 {
+		b.cursor += len(events)
+		b.lastID = events[len(events)-1].ID
+	}
+}
+
+// think pauses for the bot's think time. It reports false if the manager
+// stopped meanwhile.
+func (b *Bot) think() bool {
+	delay := b.settings.ThinkTime(b.rng.Float64())
+	if delay <= 0 {
+		return !b.stopped()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-b.done:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// playerIDs lists a room's players in join order.
+func playerIDs(room *core.Room) []string {
+	players := room.GetPlayers()
+	sort.Slice(players, func(i, j int) bool {
+		if players[i].JoinedAt.Equal(players[j].JoinedAt) {
+			return players[i].ID < players[j].ID
+		}
+		return players[i].JoinedAt.Before(players[j].JoinedAt)
+	})
+
+	ids := make([]string, len(players))
+	for i, player := range players {
+		ids[i] = player.ID
+	}
+	return ids
+}
+
+// idle is the strategy of bots whose strategy is missing: they never act.
+type idle struct{}
+
+func (idle) Observe(core.GameEvent) {}
+
+func (idle) Decide(View) (core.Action, bool) { return core.Action{}, false }
@@ -0,0 +1,282 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/games/avalon"
+	"github.com/KonradHerman/roundtable/internal/games/werewolf"
+)
+
+// newTestManager returns a manager that submits straight to the room, as the
+// server does without the broadcasting.
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+
+	var m *Manager
+	m = NewManager(func(room *core.Room, playerID string, action core.Action) error {
+		if _, err := room.ProcessAction(playerID, action); err != nil {
+			return err
+		}
+		m.Notify(room)
+		return nil
+	})
+	t.Cleanup(m.Stop)
+	return m
+}
+
+// newBotRoom creates a lobby of seats players who are all bots, except for
+// a human host if withHost is set.
+func newBotRoom(gameType string, seats int, settings core.BotSettings, withHost bool) (*core.Room, *core.Player) {
+	host := core.NewBotPlayer("Bot 0", settings)
+	if withHost {
+		host = core.NewPlayer("Host")
+	}
+
+	room := core.NewRoom("BOTS01", gameType, host, 10)
+	for i := 1; i < seats; i++ {
+		room.AddPlayer(core.NewBotPlayer(fmt.Sprintf("Bot %d", i), settings))
+	}
+	return room, host
+}
+
+// waitFor polls until cond holds or fails the test after a few seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// hasEvent reports whether the room's event log contains an event type.
+func hasEvent(room *core.Room, eventType string) bool {
+	for _, event := range room.GetAllEvents() {
+		if event.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func TestStrategies(t *testing.T) {
+	t.Parallel()
+
+	for _, gameType := range []string{"avalon", "werewolf"} {
+		for _, name := range []string{StrategyRandom, StrategyHeuristic} {
+			if !HasStrategy(gameType, name) {
+				t.Errorf("expected %s to have the %s strategy, got %v", gameType, name, Strategies(gameType))
+			}
+		}
+	}
+	if HasStrategy("avalon", "psychic") || HasStrategy("chess", StrategyRandom) {
+		t.Error("expected unknown strategies and games to have no strategy")
+	}
+}
+
+func TestManager_PlaysAvalon(t *testing.T) {
+	t.Parallel()
+
+	m := newTestManager(t)
+	room, _ := newBotRoom("avalon", 5, core.BotSettings{Strategy: StrategyRandom}, false)
+
+	// Two games in a row: bots start over after a reset
+	for game := 1; game <= 2; game++ {
+		if err := room.StartGame(avalon.NewGame(), avalon.DefaultConfig(5)); err != nil {
+			t.Fatalf("StartGame() error = %v", err)
+		}
+		m.Notify(room)
+
+		waitFor(t, fmt.Sprintf("game %d to finish", game), func() bool {
+			return room.GetState().Status == core.RoomStatusFinished
+		})
+		if !hasEvent(room, core.EventGameFinished) {
+			t.Fatalf("game %d finished without a game_finished event", game)
+		}
+
+		if err := room.ResetGame(); err != nil {
+			t.Fatalf("ResetGame() error = %v", err)
+		}
+	}
+
+	if m.Count() != 5 {
+		t.Errorf("expected 5 bots, got %d", m.Count())
+	}
+}
+
+func TestManager_PlaysAvalonHeuristic(t *testing.T) {
+	t.Parallel()
+
+	m := newTestManager(t)
+	room, _ := newBotRoom("avalon", 7, core.BotSettings{Strategy: StrategyHeuristic}, false)
+
+	if err := room.StartGame(avalon.NewGame(), avalon.DefaultConfig(7)); err != nil {
+		t.Fatalf("StartGame() error = %v", err)
+	}
+	m.Notify(room)
+
+	waitFor(t, "the game to finish", func() bool {
+		return room.GetState().Status == core.RoomStatusFinished
+	})
+	if !hasEvent(room, core.EventGameFinished) {
+		t.Fatal("game finished without a game_finished event")
+	}
+}
+
+func TestManager_PlaysWerewolf(t *testing.T) {
+	t.Parallel()
+
+	for _, strategy := range []string{StrategyRandom, StrategyHeuristic} {
+		t.Run(strategy, func(t *testing.T) {
+			t.Parallel()
+
+			m := newTestManager(t)
+			settings := core.BotSettings{Strategy: strategy}
+			room, host := newBotRoom("werewolf", 5, settings, true)
+
+			config := &werewolf.Config{Roles: []werewolf.RoleType{
+				werewolf.RoleWerewolf, werewolf.RoleSeer, werewolf.RoleRobber, werewolf.RoleTroublemaker,
+				werewolf.RoleDrunk, werewolf.RoleVillager, werewolf.RoleVillager, werewolf.RoleMinion,
+			}}
+			if err := room.StartGame(werewolf.NewGame(), config); err != nil {
+				t.Fatalf("StartGame() error = %v", err)
+			}
+			m.Notify(room)
+
+			// The host acknowledges last; the bots must have done so already
+			waitFor(t, "bots to acknowledge", func() bool {
+				acknowledged := 0
+				for _, event := range room.GetAllEvents() {
+					if event.Type == "role_acknowledged" {
+						acknowledged++
+					}
+				}
+				return acknowledged == 4
+			})
+			if _, err := room.ProcessAction(host.ID, core.Action{Type: "acknowledge_role"}); err != nil {
+				t.Fatalf("acknowledge_role error = %v", err)
+			}
+			m.Notify(room)
+
+			// Every bot dealt an active night role acts before the host calls day
+			waitFor(t, "night actions", func() bool {
+				return nightActionsDone(room, host.ID)
+			})
+			if _, err := room.ProcessAction(host.ID, core.Action{Type: "advance_phase"}); err != nil {
+				t.Fatalf("advance_phase error = %v", err)
+			}
+			m.Notify(room)
+
+			// Bots vote, and the host's vote ends the game
+			waitFor(t, "bots to vote", func() bool {
+				view := room.ViewGame(host.ID)
+				return view.Public.(werewolf.PublicState).VotesSubmitted == 4
+			})
+			vote, _ := eliminationVote(playerIDs(room)[1])
+			if _, err := room.ProcessAction(host.ID, vote); err != nil {
+				t.Fatalf("vote error = %v", err)
+			}
+			if !hasEvent(room, core.EventGameFinished) {
+				t.Error("expected the game to finish once everyone voted")
+			}
+		})
+	}
+}
+
+// nightActionsDone reports whether every bot with a night action has one
+// confirmed in the event log, or lost its card to another role first.
+func nightActionsDone(room *core.Room, hostID string) bool {
+	inspection := room.InspectGame()
+	dealt := make(map[string]werewolf.RoleType)
+	werewolves := 0
+	confirmed := make(map[string]bool)
+
+	for _, event := range room.GetAllEvents() {
+		switch {
+		case event.Type == "role_assigned":
+			var payload werewolf.RoleAssignedPayload
+			json.Unmarshal(event.Payload, &payload)
+			dealt[event.Visibility.PlayerIDs[0]] = payload.Role
+			if payload.Role == werewolf.RoleWerewolf {
+				werewolves++
+			}
+		case isNightResult(event.Type):
+			confirmed[event.Visibility.PlayerIDs[0]] = true
+		}
+	}
+
+	for playerID, role := range dealt {
+		acts := role == werewolf.RoleSeer || role == werewolf.RoleRobber ||
+			role == werewolf.RoleTroublemaker || role == werewolf.RoleDrunk ||
+			(role == werewolf.RoleWerewolf && werewolves == 1)
+		holds := inspection.PlayerStates[playerID].(werewolf.PlayerState).YourRole == role
+		if playerID != hostID && acts && holds && !confirmed[playerID] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestManager_Stop(t *testing.T) {
+	t.Parallel()
+
+	m := newTestManager(t)
+	slow := core.BotSettings{Strategy: StrategyRandom, MinThinkMs: core.MaxBotThinkMs, MaxThinkMs: core.MaxBotThinkMs}
+	room, _ := newBotRoom("avalon", 5, slow, false)
+
+	if err := room.StartGame(avalon.NewGame(), avalon.DefaultConfig(5)); err != nil {
+		t.Fatalf("StartGame() error = %v", err)
+	}
+	eventsAtStart := room.GetEventLogLength()
+	m.Notify(room)
+
+	stopped := make(chan struct{})
+	go func() {
+		m.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Stop to interrupt thinking bots")
+	}
+	if room.GetEventLogLength() != eventsAtStart {
+		t.Error("expected stopped bots not to act")
+	}
+
+	// Stopped managers stay idle
+	m.Notify(room)
+	if room.GetEventLogLength() != eventsAtStart {
+		t.Error("expected no bots to run after Stop")
+	}
+}
+
+func TestManager_Remove(t *testing.T) {
+	t.Parallel()
+
+	m := newTestManager(t)
+	room, _ := newBotRoom("avalon", 5, core.BotSettings{Strategy: StrategyRandom}, true)
+	m.Notify(room)
+
+	if m.Count() != 4 {
+		t.Fatalf("expected 4 bots, got %d", m.Count())
+	}
+
+	m.Remove(playerIDs(room)[1])
+	if m.Count() != 3 {
+		t.Errorf("expected 3 bots after removing one, got %d", m.Count())
+	}
+
+	m.RemoveRoom(room.ID)
+	if m.Count() != 0 {
+		t.Errorf("expected no bots after removing the room, got %d", m.Count())
+	}
+}
@@ -0,0 +1,142 @@
+package bot
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// Manager drives the bot seats of every room.
+type Manager struct {
+	submit Submitter
+
+	mu   sync.Mutex
+	bots map[string]*Bot // PlayerID → bot
+	done chan struct{}   // Closed by Stop
+	wg   sync.WaitGroup  // Running bots
+}
+
+// NewManager creates a manager that submits bot actions with submit.
+func NewManager(submit Submitter) *Manager {
+	return &Manager{
+		submit: submit,
+		bots:   make(map[string]*Bot),
+		done:   make(chan struct{}),
+	}
+}
+
+// Notify wakes a room's bots to look at the game. Bot seats the manager does
+// not drive yet, such as those of restored rooms, are picked up here.
+// A nil manager ignores notifications.
+func (m *Manager) Notify(room *core.Room) {
+	if m == nil {
+		return
+	}
+
+	for _, player := range room.GetPlayers() {
+		if !player.IsBot() {
+			continue
+		}
+		m.mu.Lock()
+		if bot := m.attachLocked(room, player); bot != nil {
+			// Started under m.mu so Stop never misses a bot
+			bot.notify()
+		}
+		m.mu.Unlock()
+	}
+}
+
+// attachLocked returns the bot driving a seat, creating it if needed.
+// Caller must hold m.mu.
+func (m *Manager) attachLocked(room *core.Room, player *core.Player) *Bot {
+	if bot, exists := m.bots[player.ID]; exists {
+		if bot.room == room {
+			return bot
+		}
+		// The room was replaced, e.g. by an import
+		bot.remove()
+	}
+
+	select {
+	case <-m.done:
+		return nil
+	default:
+	}
+
+	bot := &Bot{
+		playerID: player.ID,
+		room:     room,
+		settings: *player.Bot,
+		submit:   m.submit,
+		rng:      rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+		done:     m.done,
+		wg:       &m.wg,
+	}
+	m.bots[player.ID] = bot
+
+	slog.Info("bot attached",
+		"roomCode", room.ID,
+		"playerID", player.ID,
+		"strategy", player.Bot.Strategy,
+	)
+	return bot
+}
+
+// Remove stops driving a bot seat, e.g. after the host removed it.
+func (m *Manager) Remove(playerID string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if bot, exists := m.bots[playerID]; exists {
+		bot.remove()
+		delete(m.bots, playerID)
+	}
+}
+
+// RemoveRoom stops driving every bot in a room that was closed or cleaned up.
+func (m *Manager) RemoveRoom(roomCode string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for playerID, bot := range m.bots {
+		if bot.room.ID == roomCode {
+			bot.remove()
+			delete(m.bots, playerID)
+		}
+	}
+}
+
+// Count returns the number of bots being driven.
+func (m *Manager) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.bots)
+}
+
+// Stop interrupts thinking bots and waits for running ones to finish their
+// current step. Bots stay idle from then on.
+func (m *Manager) Stop() {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	select {
+	case <-m.done:
+	default:
+		close(m.done)
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
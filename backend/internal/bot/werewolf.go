@@ -0,0 +1,128 @@
+package bot
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/games/werewolf"
+)
+
+// centerCards is the number of cards in the middle in One Night Werewolf.
+const centerCards = 3
+
+func init() {
+	Register("werewolf", StrategyRandom, func(rng *rand.Rand) Strategy {
+		return &werewolfRandom{rng: rng}
+	})
+}
+
+// werewolfRandom plays One Night Werewolf at random: its dealt role's night
+// action on random targets, then a vote for a random other player. The host
+// still moves the game from night to day.
+type werewolfRandom struct {
+	rng *rand.Rand
+
+	role      werewolf.RoleType // Dealt role, from role_assigned
+	lone      bool              // The only werewolf, from werewolf_wakeup
+	nightDone bool              // Night action confirmed
+}
+
+func (s *werewolfRandom) Observe(event core.GameEvent) {
+	switch event.Type {
+	case "role_assigned":
+		var payload werewolf.RoleAssignedPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			s.role = payload.Role
+		}
+	case "werewolf_wakeup":
+		var payload werewolf.WerewolfWakeupPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			s.lone = len(payload.OtherWerewolves) == 0
+		}
+	default:
+		if isNightResult(event.Type) {
+			s.nightDone = true
+		}
+	}
+}
+
+func (s *werewolfRandom) Decide(view View) (core.Action, bool) {
+	state, ok := view.Player.(werewolf.PlayerState)
+	if !ok {
+		return core.Action{}, false
+	}
+
+	switch werewolf.Phase(state.Phase) {
+	case werewolf.PhaseRoleReveal:
+		if !state.HasAcknowledged {
+			return newAction("acknowledge_role", struct{}{})
+		}
+
+	case werewolf.PhaseNight:
+		// The game checks the card a player holds now, so once another role
+		// has moved the bot's card it no longer gets to act
+		if !s.nightDone && state.YourRole == s.role {
+			return s.nightAction(view)
+		}
+
+	case werewolf.PhaseDay:
+		if !state.HasVoted && len(view.Others()) > 0 {
+			return eliminationVote(pick(s.rng, view.Others(), 1)[0])
+		}
+	}
+
+	return core.Action{}, false
+}
+
+// nightAction picks the dealt role's night action, if it has one.
+func (s *werewolfRandom) nightAction(view View) (core.Action, bool) {
+	others := pick(s.rng, view.Others(), 2)
+
+	switch s.role {
+	case werewolf.RoleWerewolf:
+		if s.lone {
+			return newAction("werewolf_view_center", werewolf.WerewolfViewCenterPayload{
+				CenterIndex: s.rng.IntN(centerCards),
+			})
+		}
+	case werewolf.RoleSeer:
+		if len(others) > 0 && s.rng.IntN(2) == 0 {
+			return newAction("seer_view_player", werewolf.SeerViewPayload{TargetID: others[0]})
+		}
+		return newAction("seer_view_center", werewolf.SeerViewCenterPayload{
+			CenterIndices: s.rng.Perm(centerCards)[:2],
+		})
+	case werewolf.RoleRobber:
+		if len(others) > 0 {
+			return newAction("robber_swap", werewolf.RobberSwapPayload{TargetID: others[0]})
+		}
+	case werewolf.RoleTroublemaker:
+		if len(others) == 2 {
+			return newAction("troublemaker_swap", werewolf.TroublemakerSwapPayload{
+				Player1ID: others[0],
+				Player2ID: others[1],
+			})
+		}
+	case werewolf.RoleDrunk:
+		return newAction("drunk_swap", werewolf.DrunkSwapPayload{CenterIndex: s.rng.IntN(centerCards)})
+	}
+
+	return core.Action{}, false
+}
+
+// eliminationVote votes to eliminate a player.
+func eliminationVote(targetID string) (core.Action, bool) {
+	return newAction("vote", werewolf.VotePayload{TargetID: targetID})
+}
+
+// isNightResult reports whether an event confirms the receiver's night action.
+func isNightResult(eventType string) bool {
+	switch eventType {
+	case "werewolf_view_center_result", "seer_result", "seer_center_result",
+		"robber_result", "troublemaker_confirmed", "drunk_confirmed":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,245 @@
+package bot
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/games/werewolf"
+)
+
+func init() {
+	Register("werewolf", StrategyHeuristic, func(rng *rand.Rand) Strategy {
+		return &werewolfHeuristic{
+			werewolfRandom: werewolfRandom{rng: rng},
+			cards:          make(map[string]sighting),
+			center:         make(map[int]werewolf.RoleType),
+		}
+	})
+}
+
+// sightingHolds is the chance that a card the bot saw at night is still with
+// the same player at dawn, since other players may have moved it since.
+const sightingHolds = 0.75
+
+// sighting is the last card the bot saw in front of a player.
+type sighting struct {
+	role  werewolf.RoleType
+	final bool // Seen after every swap, as the insomniac sees their own
+}
+
+// werewolfHeuristic plays One Night Werewolf from what it learns at night.
+// It keeps the last card it saw in front of each player and in the center,
+// moving them along with its own swaps, and treats unseen cards as drawn from
+// what is left of the deck. By day it votes for the player most likely to
+// hold a werewolf team card; if it believes its own card ended up on the
+// werewolf team, it deflects onto a likely villager instead. Night actions
+// are the random strategy's, except that a seer always looks at a player.
+type werewolfHeuristic struct {
+	werewolfRandom
+
+	deck   []werewolf.RoleType       // Every card in play, from game_started
+	cards  map[string]sighting       // Player → last card seen there
+	center map[int]werewolf.RoleType // Center index → last card seen there
+}
+
+func (s *werewolfHeuristic) Observe(event core.GameEvent) {
+	s.werewolfRandom.Observe(event)
+
+	switch event.Type {
+	case core.EventGameStarted:
+		var payload struct {
+			Config werewolf.Config `json:"config"`
+		}
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			s.deck = payload.Config.Roles
+		}
+
+	case "role_assigned":
+		var payload werewolf.RoleAssignedPayload
+		if json.Unmarshal(event.Payload, &payload) == nil && len(event.Visibility.PlayerIDs) > 0 {
+			s.cards[event.Visibility.PlayerIDs[0]] = sighting{role: payload.Role}
+		}
+
+	case "werewolf_wakeup":
+		var payload werewolf.WerewolfWakeupPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			for _, id := range payload.OtherWerewolves {
+				s.cards[id] = sighting{role: werewolf.RoleWerewolf}
+			}
+		}
+
+	case "mason_wakeup":
+		var payload werewolf.MasonWakeupPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			for _, id := range payload.OtherMasons {
+				s.cards[id] = sighting{role: werewolf.RoleMason}
+			}
+		}
+
+	case "seer_result":
+		var payload werewolf.SeerResultPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			s.cards[payload.TargetID] = sighting{role: payload.Role}
+		}
+
+	case "seer_center_result":
+		var payload werewolf.SeerViewCenterResultPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			for _, card := range payload.Cards {
+				s.center[card.Index] = card.Role
+			}
+		}
+
+	case "werewolf_view_center_result":
+		var payload werewolf.WerewolfViewCenterResultPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			s.center[payload.CenterIndex] = payload.Role
+		}
+
+	case "robber_result":
+		var payload werewolf.RobberResultPayload
+		if json.Unmarshal(event.Payload, &payload) == nil && len(event.Visibility.PlayerIDs) > 0 {
+			s.cards[payload.TargetID] = sighting{role: werewolf.RoleRobber}
+			s.cards[event.Visibility.PlayerIDs[0]] = sighting{role: payload.NewRole}
+		}
+
+	case "troublemaker_confirmed":
+		var payload werewolf.TroublemakerSwapPayload
+		if json.Unmarshal(event.Payload, &payload) == nil {
+			first, firstSeen := s.cards[payload.Player1ID]
+			second, secondSeen := s.cards[payload.Player2ID]
+			delete(s.cards, payload.Player1ID)
+			delete(s.cards, payload.Player2ID)
+			if firstSeen {
+				s.cards[payload.Player2ID] = first
+			}
+			if secondSeen {
+				s.cards[payload.Player1ID] = second
+			}
+		}
+
+	case "drunk_confirmed":
+		var payload werewolf.DrunkSwapPayload
+		if json.Unmarshal(event.Payload, &payload) == nil && len(event.Visibility.PlayerIDs) > 0 {
+			self := event.Visibility.PlayerIDs[0]
+			if role, seen := s.center[payload.CenterIndex]; seen {
+				s.cards[self] = sighting{role: role}
+			} else {
+				delete(s.cards, self)
+			}
+			s.center[payload.CenterIndex] = werewolf.RoleDrunk
+		}
+
+	case "insomniac_result":
+		var payload werewolf.InsomniacResultPayload
+		if json.Unmarshal(event.Payload, &payload) == nil && len(event.Visibility.PlayerIDs) > 0 {
+			s.cards[event.Visibility.PlayerIDs[0]] = sighting{role: payload.FinalRole, final: true}
+		}
+	}
+}
+
+func (s *werewolfHeuristic) Decide(view View) (core.Action, bool) {
+	state, ok := view.Player.(werewolf.PlayerState)
+	if !ok {
+		return core.Action{}, false
+	}
+
+	switch werewolf.Phase(state.Phase) {
+	case werewolf.PhaseNight:
+		// What a player holds says more about the vote than the center does
+		if s.role == werewolf.RoleSeer && !s.nightDone && state.YourRole == s.role && len(view.Others()) > 0 {
+			return newAction("seer_view_player", werewolf.SeerViewPayload{TargetID: pick(s.rng, view.Others(), 1)[0]})
+		}
+
+	case werewolf.PhaseDay:
+		if !state.HasVoted && len(view.Others()) > 0 {
+			return eliminationVote(s.vote(view))
+		}
+		return core.Action{}, false
+	}
+
+	return s.werewolfRandom.Decide(view)
+}
+
+// vote picks who to eliminate. Accusations break near ties, so bots on the
+// same side tend to pile onto the same player.
+func (s *werewolfHeuristic) vote(view View) string {
+	odds := s.werewolfOdds(view.Players)
+
+	accused := make(map[string]int)
+	if public, ok := view.Public.(werewolf.PublicState); ok {
+		for _, accusation := range public.Accusations {
+			accused[accusation.SuspectID] = len(accusation.AccusedBy)
+		}
+	}
+
+	score := make(map[string]float64, len(view.Players))
+	if odds[view.PlayerID] > 0.5 {
+		// Deflect: the likeliest villager, ideally one the table already
+		// suspects
+		for _, id := range view.Others() {
+			score[id] = odds[id] - 0.01*float64(accused[id])
+		}
+	} else {
+		for _, id := range view.Others() {
+			score[id] = -odds[id] - 0.01*float64(accused[id])
+		}
+	}
+	return ranked(s.rng, view.Others(), score)[0]
+}
+
+// werewolfOdds estimates the chance that each player holds a werewolf team
+// card at dawn. Cards the bot hasn't seen are equally likely to be any of
+// the werewolf team cards it hasn't accounted for.
+func (s *werewolfHeuristic) werewolfOdds(players []string) map[string]float64 {
+	unseenWolves := 0
+	for _, role := range s.deck {
+		if role.IsWerewolfTeam() {
+			unseenWolves++
+		}
+	}
+	unseenCards := len(s.deck)
+
+	for _, id := range players {
+		if card, seen := s.cards[id]; seen {
+			unseenCards--
+			if card.role.IsWerewolfTeam() {
+				unseenWolves--
+			}
+		}
+	}
+	for _, role := range s.center {
+		unseenCards--
+		if role.IsWerewolfTeam() {
+			unseenWolves--
+		}
+	}
+
+	unseen := 0.0
+	if unseenCards > 0 && unseenWolves > 0 {
+		unseen = float64(unseenWolves) / float64(unseenCards)
+	}
+
+	odds := make(map[string]float64, len(players))
+	for _, id := range players {
+		card, seen := s.cards[id]
+		switch {
+		case !seen:
+			odds[id] = unseen
+		case card.final:
+			odds[id] = werewolfCard(card.role)
+		default:
+			odds[id] = sightingHolds*werewolfCard(card.role) + (1-sightingHolds)*unseen
+		}
+	}
+	return odds
+}
+
+// werewolfCard is 1 for werewolf team cards and 0 for the rest.
+func werewolfCard(role werewolf.RoleType) float64 {
+	if role.IsWerewolfTeam() {
+		return 1
+	}
+	return 0
+}
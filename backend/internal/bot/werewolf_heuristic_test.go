@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/games/werewolf"
+)
+
+// nightResult is a private event for the bot, seated as p1.
+func nightResult(eventType string, payload interface{}) core.GameEvent {
+	event, _ := core.NewPrivateEvent(eventType, "system", payload, []string{"p1"})
+	return event
+}
+
+func TestWerewolfHeuristic_Vote(t *testing.T) {
+	t.Parallel()
+
+	players := []string{"p1", "p2", "p3", "p4", "p5"}
+	started, _ := core.NewPublicEvent(core.EventGameStarted, "system", core.GameStartedPayload{
+		GameType: "werewolf",
+		Config: &werewolf.Config{Roles: []werewolf.RoleType{
+			werewolf.RoleWerewolf, werewolf.RoleWerewolf, werewolf.RoleSeer, werewolf.RoleRobber,
+			werewolf.RoleTroublemaker, werewolf.RoleInsomniac, werewolf.RoleVillager, werewolf.RoleVillager,
+		}},
+		PlayerIDs: players,
+	})
+
+	tests := []struct {
+		name        string
+		role        werewolf.RoleType
+		events      []core.GameEvent
+		accusations []core.Accusation
+		want        string
+	}{
+		{
+			name:   "seer votes the werewolf it saw",
+			role:   werewolf.RoleSeer,
+			events: []core.GameEvent{nightResult("seer_result", werewolf.SeerResultPayload{TargetID: "p3", Role: werewolf.RoleWerewolf})},
+			want:   "p3",
+		},
+		{
+			name:   "robber who took a werewolf deflects onto its victim",
+			role:   werewolf.RoleRobber,
+			events: []core.GameEvent{nightResult("robber_result", werewolf.RobberResultPayload{TargetID: "p2", NewRole: werewolf.RoleWerewolf})},
+			want:   "p2",
+		},
+		{
+			name: "troublemaker follows the card it swapped",
+			role: werewolf.RoleTroublemaker,
+			events: []core.GameEvent{
+				nightResult("seer_result", werewolf.SeerResultPayload{TargetID: "p3", Role: werewolf.RoleWerewolf}),
+				nightResult("troublemaker_confirmed", werewolf.TroublemakerSwapPayload{Player1ID: "p3", Player2ID: "p4"}),
+			},
+			want: "p4",
+		},
+		{
+			name:        "werewolf spares its partner and follows the table",
+			role:        werewolf.RoleWerewolf,
+			events:      []core.GameEvent{nightResult("werewolf_wakeup", werewolf.WerewolfWakeupPayload{OtherWerewolves: []string{"p2"}})},
+			accusations: []core.Accusation{{SuspectID: "p2", AccusedBy: []string{"p3", "p4", "p5"}}, {SuspectID: "p4", AccusedBy: []string{"p3"}}},
+			want:        "p4",
+		},
+		{
+			name:        "insomniac who woke up a werewolf deflects",
+			role:        werewolf.RoleInsomniac,
+			events:      []core.GameEvent{nightResult("insomniac_result", werewolf.InsomniacResultPayload{FinalRole: werewolf.RoleWerewolf})},
+			accusations: []core.Accusation{{SuspectID: "p5", AccusedBy: []string{"p2"}}},
+			want:        "p5",
+		},
+		{
+			name:        "villager follows the table when it knows nothing",
+			role:        werewolf.RoleVillager,
+			accusations: []core.Accusation{{SuspectID: "p3", AccusedBy: []string{"p2"}}},
+			want:        "p3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := Strategy(&werewolfHeuristic{
+				werewolfRandom: werewolfRandom{rng: rand.New(rand.NewPCG(1, 2))},
+				cards:          make(map[string]sighting),
+				center:         make(map[int]werewolf.RoleType),
+			})
+			s.Observe(started)
+			s.Observe(nightResult("role_assigned", werewolf.RoleAssignedPayload{Role: tt.role}))
+			for _, event := range tt.events {
+				s.Observe(event)
+			}
+
+			view := View{
+				GameView: core.GameView{
+					Player: werewolf.PlayerState{Phase: string(werewolf.PhaseDay), YourRole: tt.role},
+					Public: werewolf.PublicState{Phase: string(werewolf.PhaseDay), Accusations: tt.accusations},
+				},
+				PlayerID: "p1",
+				Players:  players,
+			}
+
+			action, ok := s.Decide(view)
+			if !ok || action.Type != "vote" {
+				t.Fatalf("expected a vote, got %+v", action)
+			}
+			var vote werewolf.VotePayload
+			json.Unmarshal(action.Payload, &vote)
+			if vote.TargetID != tt.want {
+				t.Errorf("expected a vote for %s, got %s", tt.want, vote.TargetID)
+			}
+		})
+	}
+}
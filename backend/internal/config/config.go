@@ -0,0 +1,377 @@
+// Package config loads the server configuration.
+//
+// Settings come from, in increasing precedence: built-in defaults, an
+// optional YAML file (-config or CONFIG_FILE), environment variables and
+// command-line flags.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/KonradHerman/roundtable/internal/auth"
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/ratelimit"
+	"github.com/KonradHerman/roundtable/internal/server"
+	"github.com/KonradHerman/roundtable/internal/store"
+	"github.com/KonradHerman/roundtable/internal/util"
+)
+
+// Config is the complete server configuration.
+type Config struct {
+	Port int `yaml:"port"`
+
+	// AllowedOrigins are the browser origins allowed to call the API and
+	// open WebSockets. Empty allows localhost, for development.
+	AllowedOrigins []string `yaml:"allowedOrigins"`
+
+	// PublicURL is the frontend's base URL, used in join links and QR codes.
+	// Empty uses the host the request was made to.
+	PublicURL string `yaml:"publicURL"`
+
+	// TrustProxyHeaders makes client IPs come from X-Forwarded-For.
+	TrustProxyHeaders bool `yaml:"trustProxyHeaders"`
+
+	// AdminToken enables the admin API. Never printed.
+	AdminToken string `yaml:"adminToken"`
+
+	// SessionKey is the base64 key signing session tokens. Never printed.
+	// Empty uses a key stored in DataDir, or a random one per process.
+	SessionKey string `yaml:"sessionKey"`
+
+	// SessionTTL is how long session tokens are valid.
+	SessionTTL time.Duration `yaml:"sessionTTL"`
+
+	// InviteTTL is how long invite links are valid.
+	InviteTTL time.Duration `yaml:"inviteTTL"`
+
+	// DataDir persists rooms as snapshot files. Empty keeps rooms in memory.
+	DataDir string `yaml:"dataDir"`
+
+	// DefaultMaxPlayers is the room size when create requests omit it.
+	DefaultMaxPlayers int `yaml:"defaultMaxPlayers"`
+
+	CleanupInterval time.Duration `yaml:"cleanupInterval"` // How often stale rooms are removed
+	FlushInterval   time.Duration `yaml:"flushInterval"`   // How often rooms are written to DataDir
+	PhaseTick       time.Duration `yaml:"phaseTick"`       // How often game timers are checked
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"` // How long shutdown waits for clients and requests
+	ReconnectAfter  time.Duration `yaml:"reconnectAfter"`  // Reconnect hint sent to clients on shutdown
+
+	Rooms       store.Options            `yaml:"rooms"`
+	RoomCodes   util.CodeOptions         `yaml:"roomCodes"`
+	Connections server.ConnectionOptions `yaml:"connections"`
+	RateLimits  server.RateLimits        `yaml:"rateLimits"`
+
+	// Scoring holds session scoring rules per game type, e.g.
+	// scoring.werewolf.roleBonus.tanner. Other games score a point per win.
+	Scoring map[string]core.ScoringRules `yaml:"scoring"`
+}
+
+// Default returns the built-in configuration.
+func Default() Config {
+	options := server.DefaultOptions()
+
+	return Config{
+		Port:              8080,
+		DefaultMaxPlayers: options.DefaultMaxPlayers,
+		SessionTTL:        options.SessionTTL,
+		InviteTTL:         options.InviteTTL,
+		CleanupInterval:   1 * time.Hour,
+		FlushInterval:     1 * time.Minute,
+		PhaseTick:         1 * time.Second,
+		ShutdownTimeout:   10 * time.Second,
+		ReconnectAfter:    3 * time.Second,
+		Rooms:             store.DefaultOptions(),
+		RoomCodes:         options.RoomCodes,
+		Connections:       options.Connections,
+		RateLimits:        options.RateLimits,
+	}
+}
+
+// Load builds the configuration from a config file, the environment and
+// command-line arguments (without the program name), then validates it.
+// getenv is usually os.Getenv.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	flags := flag.NewFlagSet("server", flag.ContinueOnError)
+	configFile := flags.String("config", getenv("CONFIG_FILE"), "YAML config file (env CONFIG_FILE)")
+	port := flags.Int("port", 0, "listen port (env PORT)")
+	dataDir := flags.String("data-dir", "", "persist rooms in this directory (env DATA_DIR)")
+	allowedOrigins := flags.String("allowed-origins", "", "comma-separated allowed origins (env ALLOWED_ORIGINS)")
+	trustProxyHeaders := flags.Bool("trust-proxy-headers", false, "take client IPs from X-Forwarded-For (env TRUST_PROXY_HEADERS)")
+	defaultMaxPlayers := flags.Int("default-max-players", 0, "room size when not requested")
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	cfg := Default()
+
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := cfg.loadEnv(getenv); err != nil {
+		return Config{}, err
+	}
+
+	// Only flags given on the command line override
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Port = *port
+		case "data-dir":
+			cfg.DataDir = *dataDir
+		case "allowed-origins":
+			cfg.AllowedOrigins = splitList(*allowedOrigins)
+		case "trust-proxy-headers":
+			cfg.TrustProxyHeaders = *trustProxyHeaders
+		case "default-max-players":
+			cfg.DefaultMaxPlayers = *defaultMaxPlayers
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// loadFile overlays settings from a YAML file. Unknown keys are errors so
+// typos don't go unnoticed.
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// loadEnv overlays settings from environment variables.
+func (c *Config) loadEnv(getenv func(string) string) error {
+	if value := getenv("PORT"); value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid PORT %q", value)
+		}
+		c.Port = port
+	}
+
+	// ALLOWED_ORIGINS is a comma-separated list; ALLOWED_ORIGIN is the older
+	// single-origin form
+	if value := getenv("ALLOWED_ORIGINS"); value != "" {
+		c.AllowedOrigins = splitList(value)
+	} else if value := getenv("ALLOWED_ORIGIN"); value != "" {
+		c.AllowedOrigins = splitList(value)
+	}
+
+	if value := getenv("PUBLIC_URL"); value != "" {
+		c.PublicURL = value
+	}
+
+	if value := getenv("TRUST_PROXY_HEADERS"); value != "" {
+		trust, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid TRUST_PROXY_HEADERS %q", value)
+		}
+		c.TrustProxyHeaders = trust
+	}
+
+	if value := getenv("ADMIN_TOKEN"); value != "" {
+		c.AdminToken = value
+	}
+	if value := getenv("SESSION_KEY"); value != "" {
+		c.SessionKey = value
+	}
+	if value := getenv("DATA_DIR"); value != "" {
+		c.DataDir = value
+	}
+	return nil
+}
+
+// Validate reports every invalid setting.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Port > 0 && c.Port <= 65535, "port must be between 1 and 65535, got %d", c.Port)
+	check(c.DefaultMaxPlayers > 0, "defaultMaxPlayers must be positive, got %d", c.DefaultMaxPlayers)
+
+	for _, origin := range c.AllowedOrigins {
+		if origin == "" {
+			errs = append(errs, errors.New("allowedOrigins must not contain empty entries"))
+			break
+		}
+		if origin == "*" && len(c.AllowedOrigins) > 1 {
+			errs = append(errs, errors.New(`allowedOrigins: "*" must be the only entry`))
+			break
+		}
+	}
+	if _, err := server.NewOriginPolicy(c.AllowedOrigins); err != nil {
+		errs = append(errs, fmt.Errorf("allowedOrigins: %w", err))
+	}
+
+	if c.PublicURL != "" {
+		u, err := url.Parse(c.PublicURL)
+		check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.RawQuery == "" && u.Fragment == "",
+			"publicURL must be an http(s) URL without query, got %q", c.PublicURL)
+	}
+
+	if err := c.RoomCodes.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("roomCodes: %w", err))
+	}
+
+	if c.SessionKey != "" {
+		if _, err := auth.DecodeKey(c.SessionKey); err != nil {
+			errs = append(errs, fmt.Errorf("sessionKey: %w", err))
+		}
+	}
+
+	for name, d := range map[string]time.Duration{
+		"sessionTTL":              c.SessionTTL,
+		"inviteTTL":               c.InviteTTL,
+		"cleanupInterval":         c.CleanupInterval,
+		"flushInterval":           c.FlushInterval,
+		"phaseTick":               c.PhaseTick,
+		"shutdownTimeout":         c.ShutdownTimeout,
+		"rooms.finishedRoomTTL":   c.Rooms.FinishedRoomTTL,
+		"rooms.abandonedRoomTTL":  c.Rooms.AbandonedRoomTTL,
+		"connections.authTimeout": c.Connections.AuthTimeout,
+	} {
+		check(d > 0, "%s must be positive, got %s", name, d)
+	}
+	check(c.ReconnectAfter >= 0, "reconnectAfter must not be negative, got %s", c.ReconnectAfter)
+	check(c.Connections.SendBuffer > 0, "connections.sendBuffer must be positive, got %d", c.Connections.SendBuffer)
+
+	for name, limit := range map[string]ratelimit.Limit{
+		"rateLimits.createRoom": c.RateLimits.CreateRoom,
+		"rateLimits.joinRoom":   c.RateLimits.JoinRoom,
+		"rateLimits.connect":    c.RateLimits.Connect,
+		"rateLimits.reads":      c.RateLimits.Reads,
+		"rateLimits.signIn":     c.RateLimits.SignIn,
+		"rateLimits.actions":    c.RateLimits.Actions,
+		"rateLimits.chat":       c.RateLimits.Chat,
+		"rateLimits.reactions":  c.RateLimits.Reactions,
+		"rateLimits.writes":     c.RateLimits.Writes,
+		"rateLimits.admin":      c.RateLimits.Admin,
+	} {
+		check(limit.Rate >= 0 && limit.Burst >= 0, "%s must not be negative", name)
+		check(limit.Rate == 0 || limit.Burst > 0, "%s needs a positive burst when rate is set", name)
+	}
+
+	// Map iteration order is random; keep messages stable
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errors.Join(errs...)
+}
+
+// Addr returns the address to listen on.
+func (c Config) Addr() string {
+	return ":" + strconv.Itoa(c.Port)
+}
+
+// ServerOptions returns the options for server.NewServerWithOptions.
+func (c Config) ServerOptions() server.Options {
+	var sessionKey []byte
+	if c.SessionKey != "" {
+		// Checked by Validate
+		sessionKey, _ = auth.DecodeKey(c.SessionKey)
+	}
+
+	return server.Options{
+		RateLimits:        c.RateLimits,
+		Connections:       c.Connections,
+		AllowedOrigins:    c.AllowedOrigins,
+		PublicURL:         c.PublicURL,
+		RoomCodes:         c.RoomCodes,
+		DefaultMaxPlayers: c.DefaultMaxPlayers,
+		TrustProxyHeaders: c.TrustProxyHeaders,
+		AdminToken:        c.AdminToken,
+		SessionKey:        sessionKey,
+		SessionTTL:        c.SessionTTL,
+		InviteTTL:         c.InviteTTL,
+		Scoring:           c.Scoring,
+	}
+}
+
+// LogValue prints the configuration with secrets redacted.
+func (c Config) LogValue() slog.Value {
+	limit := func(l ratelimit.Limit) slog.Value {
+		return slog.GroupValue(slog.Float64("rate", l.Rate), slog.Int("burst", l.Burst))
+	}
+
+	return slog.GroupValue(
+		slog.Int("port", c.Port),
+		slog.Any("allowedOrigins", c.AllowedOrigins),
+		slog.String("publicURL", c.PublicURL),
+		slog.Bool("trustProxyHeaders", c.TrustProxyHeaders),
+		slog.Bool("adminAPI", c.AdminToken != ""),
+		slog.Bool("sessionKey", c.SessionKey != ""),
+		slog.String("sessionTTL", c.SessionTTL.String()),
+		slog.String("inviteTTL", c.InviteTTL.String()),
+		slog.String("dataDir", c.DataDir),
+		slog.Int("defaultMaxPlayers", c.DefaultMaxPlayers),
+		slog.String("cleanupInterval", c.CleanupInterval.String()),
+		slog.String("flushInterval", c.FlushInterval.String()),
+		slog.String("phaseTick", c.PhaseTick.String()),
+		slog.String("shutdownTimeout", c.ShutdownTimeout.String()),
+		slog.String("reconnectAfter", c.ReconnectAfter.String()),
+		slog.Group("rooms",
+			slog.String("finishedRoomTTL", c.Rooms.FinishedRoomTTL.String()),
+			slog.String("abandonedRoomTTL", c.Rooms.AbandonedRoomTTL.String()),
+		),
+		slog.Group("roomCodes",
+			slog.Int("length", c.RoomCodes.Length),
+			slog.String("alphabet", c.RoomCodes.Alphabet),
+			slog.String("cooldown", c.RoomCodes.Cooldown.String()),
+			slog.Bool("allowCustom", c.RoomCodes.AllowCustom),
+		),
+		slog.Group("connections",
+			slog.Int("sendBuffer", c.Connections.SendBuffer),
+			slog.String("authTimeout", c.Connections.AuthTimeout.String()),
+		),
+		slog.Group("rateLimits",
+			slog.Attr{Key: "createRoom", Value: limit(c.RateLimits.CreateRoom)},
+			slog.Attr{Key: "joinRoom", Value: limit(c.RateLimits.JoinRoom)},
+			slog.Attr{Key: "connect", Value: limit(c.RateLimits.Connect)},
+			slog.Attr{Key: "reads", Value: limit(c.RateLimits.Reads)},
+			slog.Attr{Key: "signIn", Value: limit(c.RateLimits.SignIn)},
+			slog.Attr{Key: "actions", Value: limit(c.RateLimits.Actions)},
+			slog.Attr{Key: "chat", Value: limit(c.RateLimits.Chat)},
+			slog.Attr{Key: "reactions", Value: limit(c.RateLimits.Reactions)},
+			slog.Attr{Key: "writes", Value: limit(c.RateLimits.Writes)},
+			slog.Attr{Key: "admin", Value: limit(c.RateLimits.Admin)},
+		),
+		slog.Any("scoring", c.Scoring),
+	)
+}
+
+// splitList splits a comma-separated list, trimming whitespace.
+func splitList(value string) []string {
+	items := strings.Split(value, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
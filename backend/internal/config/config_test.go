@@ -0,0 +1,274 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// env returns a getenv function backed by a map.
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+// writeFile writes a config file into a temporary directory.
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := Load(nil, env(nil))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := Default()
+	if cfg.Port != want.Port || cfg.DefaultMaxPlayers != want.DefaultMaxPlayers || cfg.PhaseTick != want.PhaseTick {
+		t.Errorf("expected defaults, got %+v", cfg)
+	}
+	if cfg.Connections.SendBuffer != 256 || cfg.Connections.AuthTimeout != 10*time.Second {
+		t.Errorf("unexpected connection defaults: %+v", cfg.Connections)
+	}
+	if cfg.Rooms.FinishedRoomTTL != time.Hour || cfg.Rooms.AbandonedRoomTTL != 24*time.Hour {
+		t.Errorf("unexpected room defaults: %+v", cfg.Rooms)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, `
+port: 9000
+dataDir: /var/lib/roundtable
+defaultMaxPlayers: 12
+phaseTick: 500ms
+rooms:
+  abandonedRoomTTL: 2h
+connections:
+  sendBuffer: 64
+rateLimits:
+  actions:
+    rate: 5
+    burst: 8
+scoring:
+  werewolf:
+    win: 2
+    roleBonus:
+      tanner: 3
+`)
+
+	tests := []struct {
+		name        string
+		args        []string
+		env         map[string]string
+		wantPort    int
+		wantDataDir string
+	}{
+		{
+			name:        "file",
+			args:        []string{"-config", path},
+			wantPort:    9000,
+			wantDataDir: "/var/lib/roundtable",
+		},
+		{
+			name:        "file from environment",
+			env:         map[string]string{"CONFIG_FILE": path},
+			wantPort:    9000,
+			wantDataDir: "/var/lib/roundtable",
+		},
+		{
+			name:        "environment overrides file",
+			args:        []string{"-config", path},
+			env:         map[string]string{"PORT": "9100", "DATA_DIR": "/data"},
+			wantPort:    9100,
+			wantDataDir: "/data",
+		},
+		{
+			name:        "flags override environment",
+			args:        []string{"-config", path, "-port", "9200"},
+			env:         map[string]string{"PORT": "9100", "DATA_DIR": "/data"},
+			wantPort:    9200,
+			wantDataDir: "/data",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := Load(tt.args, env(tt.env))
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			if cfg.Port != tt.wantPort {
+				t.Errorf("expected port %d, got %d", tt.wantPort, cfg.Port)
+			}
+			if cfg.DataDir != tt.wantDataDir {
+				t.Errorf("expected data dir %q, got %q", tt.wantDataDir, cfg.DataDir)
+			}
+
+			// File settings apply, unset nested fields keep their defaults
+			if cfg.DefaultMaxPlayers != 12 || cfg.PhaseTick != 500*time.Millisecond {
+				t.Errorf("file settings not applied: %+v", cfg)
+			}
+			if cfg.Rooms.AbandonedRoomTTL != 2*time.Hour || cfg.Rooms.FinishedRoomTTL != time.Hour {
+				t.Errorf("unexpected rooms: %+v", cfg.Rooms)
+			}
+			if cfg.Connections.SendBuffer != 64 || cfg.Connections.AuthTimeout != 10*time.Second {
+				t.Errorf("unexpected connections: %+v", cfg.Connections)
+			}
+			if cfg.RateLimits.Actions.Rate != 5 || cfg.RateLimits.Actions.Burst != 8 {
+				t.Errorf("unexpected action limit: %+v", cfg.RateLimits.Actions)
+			}
+			if cfg.RateLimits.CreateRoom != Default().RateLimits.CreateRoom {
+				t.Errorf("expected default create room limit, got %+v", cfg.RateLimits.CreateRoom)
+			}
+			if werewolf := cfg.Scoring["werewolf"]; werewolf.Win != 2 || werewolf.RoleBonus["tanner"] != 3 {
+				t.Errorf("unexpected scoring: %+v", cfg.Scoring)
+			}
+		})
+	}
+}
+
+func TestLoad_Origins(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want []string
+	}{
+		{name: "unset", want: nil},
+		{name: "single origin", env: map[string]string{"ALLOWED_ORIGIN": "https://example.com"}, want: []string{"https://example.com"}},
+		{name: "list", env: map[string]string{"ALLOWED_ORIGINS": "https://a.example, https://b.example"}, want: []string{"https://a.example", "https://b.example"}},
+		{name: "list wins over single", env: map[string]string{"ALLOWED_ORIGINS": "https://a.example", "ALLOWED_ORIGIN": "https://b.example"}, want: []string{"https://a.example"}},
+		{name: "flag", args: []string{"-allowed-origins", "https://c.example"}, env: map[string]string{"ALLOWED_ORIGIN": "https://b.example"}, want: []string{"https://c.example"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg, err := Load(tt.args, env(tt.env))
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if strings.Join(cfg.AllowedOrigins, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("expected origins %v, got %v", tt.want, cfg.AllowedOrigins)
+			}
+		})
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		file    string
+		wantErr string
+	}{
+		{name: "bad port", env: map[string]string{"PORT": "http"}, wantErr: "invalid PORT"},
+		{name: "port out of range", args: []string{"-port", "70000"}, wantErr: "port must be between"},
+		{name: "bad bool", env: map[string]string{"TRUST_PROXY_HEADERS": "maybe"}, wantErr: "invalid TRUST_PROXY_HEADERS"},
+		{name: "unknown flag", args: []string{"-nope"}, wantErr: "flag provided but not defined"},
+		{name: "unknown key", file: "prot: 8080\n", wantErr: "field prot not found"},
+		{name: "bad duration", file: "phaseTick: soon\n", wantErr: "invalid config file"},
+		{name: "zero duration", file: "phaseTick: 0s\n", wantErr: "phaseTick must be positive"},
+		{name: "zero send buffer", file: "connections:\n  sendBuffer: 0\n", wantErr: "connections.sendBuffer must be positive"},
+		{name: "rate without burst", file: "rateLimits:\n  actions:\n    rate: 1\n    burst: 0\n", wantErr: "rateLimits.actions needs a positive burst"},
+		{name: "wildcard with others", env: map[string]string{"ALLOWED_ORIGINS": "*,https://a.example"}, wantErr: `"*" must be the only entry`},
+		{name: "origin with path", env: map[string]string{"ALLOWED_ORIGINS": "https://a.example/app"}, wantErr: "must not contain a path"},
+		{name: "relative public url", env: map[string]string{"PUBLIC_URL": "/play"}, wantErr: "publicURL must be an http(s) URL"},
+		{name: "public url with query", env: map[string]string{"PUBLIC_URL": "https://a.example/?x=1"}, wantErr: "publicURL must be an http(s) URL"},
+		{name: "short room codes", file: "roomCodes:\n  length: 2\n", wantErr: "roomCodes: code length must be between"},
+		{name: "short session key", env: map[string]string{"SESSION_KEY": "c2hvcnQ="}, wantErr: "sessionKey: signing key must be at least 32 bytes"},
+		{name: "zero session ttl", file: "sessionTTL: 0s\n", wantErr: "sessionTTL must be positive"},
+		{name: "missing file", args: []string{"-config", "/nonexistent/config.yaml"}, wantErr: "failed to open config file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, tt.file)}, args...)
+			}
+
+			_, err := Load(args, env(tt.env))
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfig_LogValueRedactsSecrets(t *testing.T) {
+	t.Parallel()
+
+	cfg := Default()
+	cfg.AdminToken = "super-secret"
+	cfg.SessionKey = "c2Vzc2lvbi1rZXktc2Vzc2lvbi1rZXktc2Vzc2lvbi1rZXk="
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("loaded configuration", "config", cfg)
+
+	if strings.Contains(buf.String(), "super-secret") {
+		t.Errorf("admin token leaked into log: %s", buf.String())
+	}
+	if strings.Contains(buf.String(), cfg.SessionKey) {
+		t.Errorf("session key leaked into log: %s", buf.String())
+	}
+	for _, want := range []string{`"adminAPI":true`, `"sessionKey":true`, `"port":8080`, `"phaseTick":"1s"`, `"sendBuffer":256`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %s in log, got %s", want, buf.String())
+		}
+	}
+}
+
+func TestConfig_ServerOptions(t *testing.T) {
+	t.Parallel()
+
+	cfg := Default()
+	cfg.AllowedOrigins = []string{"https://example.com"}
+	cfg.AdminToken = "token"
+	cfg.PublicURL = "https://play.example.com"
+	cfg.DefaultMaxPlayers = 8
+	cfg.RoomCodes.AllowCustom = true
+	cfg.SessionKey = "c2Vzc2lvbi1rZXktc2Vzc2lvbi1rZXktc2Vzc2lvbi1rZXk="
+	cfg.SessionTTL = time.Hour
+	cfg.InviteTTL = 2 * time.Hour
+
+	options := cfg.ServerOptions()
+	if options.AdminToken != "token" || options.DefaultMaxPlayers != 8 || len(options.AllowedOrigins) != 1 || options.PublicURL != cfg.PublicURL {
+		t.Errorf("unexpected server options: %+v", options)
+	}
+	if options.Connections != cfg.Connections || options.RateLimits != cfg.RateLimits || options.RoomCodes != cfg.RoomCodes {
+		t.Errorf("connection or rate limit options not passed through: %+v", options)
+	}
+	if string(options.SessionKey) != "session-key-session-key-session-key" || options.SessionTTL != time.Hour || options.InviteTTL != 2*time.Hour {
+		t.Errorf("session options not passed through: %+v", options)
+	}
+}
@@ -0,0 +1,68 @@
+package core
+
+import "time"
+
+// Think time limits for bot seats, in milliseconds.
+const (
+	DefaultBotMinThinkMs = 1500
+	DefaultBotMaxThinkMs = 4000
+	MaxBotThinkMs        = 60000
+)
+
+// BotSettings configures a bot seat: a player driven by an agent on the
+// server instead of a client (see internal/bot).
+type BotSettings struct {
+	Strategy   string `json:"strategy"`   // Registered strategy name, e.g. "random"
+	MinThinkMs int    `json:"minThinkMs"` // Shortest pause before each action
+	MaxThinkMs int    `json:"maxThinkMs"` // Longest pause before each action
+}
+
+// ThinkTime returns how long to pause before an action, given a number in
+// [0, 1) to pick between the minimum and maximum.
+func (s BotSettings) ThinkTime(fraction float64) time.Duration {
+	ms := float64(s.MinThinkMs) + fraction*float64(s.MaxThinkMs-s.MinThinkMs)
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// NewBotPlayer creates a bot seat. Bots count as connected for as long as
+// they are in the room, but never keep a room alive on their own.
+func NewBotPlayer(displayName string, settings BotSettings) *Player {
+	player := NewPlayer(displayName)
+	player.Bot = &settings
+	return player
+}
+
+// IsBot reports whether the player is a bot seat.
+func (p *Player) IsBot() bool {
+	return p.Bot != nil
+}
+
+// GameView is the game as one player sees it: the public board and their own
+// filtered state.
+type GameView struct {
+	Phase    GamePhase   `json:"phase"`
+	Finished bool        `json:"finished"`
+	Player   PlayerState `json:"player"`
+	Public   PublicState `json:"public"`
+}
+
+// ViewGame returns the game as playerID sees it, or nil if no game is
+// running or the player is not in the room.
+func (r *Room) ViewGame(playerID string) *GameView {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.Game == nil || r.Status == RoomStatusWaiting {
+		return nil
+	}
+	if _, exists := r.Players[playerID]; !exists {
+		return nil
+	}
+
+	return &GameView{
+		Phase:    r.Game.GetPhase(),
+		Finished: r.Game.IsFinished(),
+		Player:   r.Game.GetPlayerState(playerID),
+		Public:   r.Game.GetPublicState(),
+	}
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+// seatGame shows each player their own ID as their private state.
+type seatGame struct {
+	stubGame
+}
+
+func (seatGame) GetPlayerState(playerID string) PlayerState { return "seat of " + playerID }
+func (seatGame) GetPublicState() PublicState                { return "board" }
+
+func TestBotSettings_ThinkTime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings BotSettings
+		fraction float64
+		want     time.Duration
+	}{
+		{"minimum", BotSettings{MinThinkMs: 1000, MaxThinkMs: 3000}, 0, time.Second},
+		{"halfway", BotSettings{MinThinkMs: 1000, MaxThinkMs: 3000}, 0.5, 2 * time.Second},
+		{"fixed", BotSettings{MinThinkMs: 500, MaxThinkMs: 500}, 0.9, 500 * time.Millisecond},
+		{"instant", BotSettings{}, 0.7, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.settings.ThinkTime(tt.fraction); got != tt.want {
+				t.Errorf("ThinkTime(%v) = %v, want %v", tt.fraction, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoom_ViewGame(t *testing.T) {
+	t.Parallel()
+
+	room := newChatRoom()
+	if room.ViewGame("bob") != nil {
+		t.Fatal("expected no view before the game starts")
+	}
+
+	if err := room.StartGame(seatGame{}, nil); err != nil {
+		t.Fatalf("StartGame() error = %v", err)
+	}
+
+	view := room.ViewGame("bob")
+	if view == nil {
+		t.Fatal("expected a view once the game started")
+	}
+	if view.Player != "seat of bob" || view.Public != "board" {
+		t.Errorf("unexpected view %+v", view)
+	}
+	if room.ViewGame("erin") != nil {
+		t.Error("expected no view for a player outside the room")
+	}
+}
+
+func TestRoom_BotSeats(t *testing.T) {
+	t.Parallel()
+
+	host := NewPlayer("Alice")
+	room := NewRoom("ABC123", "stub", host, 10)
+	bot := NewBotPlayer("Bot 1", BotSettings{Strategy: "random", MaxThinkMs: 100})
+	if err := room.AddPlayer(bot); err != nil {
+		t.Fatalf("AddPlayer() error = %v", err)
+	}
+
+	if !bot.IsBot() || host.IsBot() {
+		t.Fatal("expected only the bot seat to be a bot")
+	}
+
+	// Bots alone don't keep a room alive
+	host.Disconnect()
+	if room.IsAnyPlayerConnected() {
+		t.Error("expected a room with only bots connected to count as abandoned")
+	}
+	if _, _, anyConnected := room.GetCleanupInfo(); anyConnected {
+		t.Error("expected cleanup to ignore bots")
+	}
+
+	snapshot, err := room.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	restored, err := RestoreRoom(snapshot, nil)
+	if err != nil {
+		t.Fatalf("RestoreRoom() error = %v", err)
+	}
+
+	restoredBot, err := restored.GetPlayer(bot.ID)
+	if err != nil {
+		t.Fatalf("GetPlayer() error = %v", err)
+	}
+	if !restoredBot.IsBot() || *restoredBot.Bot != *bot.Bot || !restoredBot.IsConnected() {
+		t.Errorf("expected the bot seat to be restored, got %+v", restoredBot.Bot)
+	}
+	for _, player := range restored.GetState().Players {
+		if player.ID == bot.ID && player.Bot == nil {
+			t.Error("expected the room state to mark the bot seat")
+		}
+	}
+}
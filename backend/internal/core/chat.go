@@ -0,0 +1,217 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Chat channels every room has. Games can add team channels by implementing
+// ChatTeams.
+const (
+	ChatChannelAll     = "all"     // Everyone in the room
+	ChatChannelWhisper = "whisper" // One other player
+)
+
+// EventChatMessage is the type of chat message events. They are kept in the
+// room's chat log rather than its event log, so chat survives game resets.
+const EventChatMessage = "chat_message"
+
+// Limits on chat.
+const (
+	MaxChatMessageLen = 500 // Characters per message
+	MaxChatHistory    = 200 // Messages kept per room; older ones are dropped
+)
+
+var (
+	ErrChatEmpty       = errors.New("chat message is empty")
+	ErrChatTooLong     = fmt.Errorf("chat message is longer than %d characters", MaxChatMessageLen)
+	ErrMuted           = errors.New("the host has muted you")
+	ErrUnknownChannel  = errors.New("unknown chat channel")
+	ErrInvalidWhisper  = errors.New("whispers must be sent to another player in the room")
+	ErrCannotMuteHost  = errors.New("cannot mute the host")
+	ErrPlayerNotInRoom = errors.New("player not in room")
+)
+
+// ChatTeams is implemented by games that give teams a private chat channel
+// while the game runs, such as the evil team in Avalon's remote play variant.
+type ChatTeams interface {
+	// ChatChannels returns the members of each team channel by name.
+	ChatChannels() map[string][]string
+}
+
+// ChatMessage is a message a player sends to a channel.
+type ChatMessage struct {
+	Channel string `json:"channel"`
+	To      string `json:"to,omitempty"` // Recipient's PlayerID, for whispers
+	Text    string `json:"text"`
+}
+
+// ChatMessagePayload is the payload of a chat_message event.
+type ChatMessagePayload struct {
+	Channel    string `json:"channel"`
+	SenderName string `json:"senderName"`
+	To         string `json:"to,omitempty"`
+	Text       string `json:"text"`
+}
+
+// Chat posts a player's message and returns the chat_message event. Its
+// visibility is the channel's members at the time it was sent, so history
+// replayed on reconnect follows the same rules as live delivery.
+func (r *Room) Chat(senderID string, msg ChatMessage) (GameEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sender, exists := r.Players[senderID]
+	if !exists {
+		return GameEvent{}, ErrPlayerNotInRoom
+	}
+	if r.muted[senderID] {
+		return GameEvent{}, ErrMuted
+	}
+
+	text := strings.TrimSpace(msg.Text)
+	if text == "" {
+		return GameEvent{}, ErrChatEmpty
+	}
+	if utf8.RuneCountInString(text) > MaxChatMessageLen {
+		return GameEvent{}, ErrChatTooLong
+	}
+
+	payload := ChatMessagePayload{Channel: msg.Channel, SenderName: sender.DisplayName, Text: text}
+	var visibility EventVisibility
+	switch msg.Channel {
+	case ChatChannelAll:
+		visibility = EventVisibility{Public: true, SpectatorOK: true}
+	case ChatChannelWhisper:
+		if _, exists := r.Players[msg.To]; !exists || msg.To == senderID {
+			return GameEvent{}, ErrInvalidWhisper
+		}
+		payload.To = msg.To
+		visibility = EventVisibility{PlayerIDs: []string{senderID, msg.To}}
+	default:
+		// Team channels only exist for their members
+		members, ok := r.teamChannelsLocked()[msg.Channel]
+		if !ok || !containsString(members, senderID) {
+			return GameEvent{}, ErrUnknownChannel
+		}
+		visibility = EventVisibility{PlayerIDs: append([]string(nil), members...)}
+	}
+
+	event, err := NewEvent(EventChatMessage, senderID, payload, visibility)
+	if err != nil {
+		return GameEvent{}, err
+	}
+
+	r.chat = append(r.chat, event)
+	if len(r.chat) > MaxChatHistory {
+		r.chat = append([]GameEvent(nil), r.chat[len(r.chat)-MaxChatHistory:]...)
+	}
+	r.LastActivityAt = time.Now()
+
+	return event, nil
+}
+
+// ChatForPlayer returns the chat history a player can see, oldest first.
+func (r *Room) ChatForPlayer(playerID string) []GameEvent {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	visible := make([]GameEvent, 0, len(r.chat))
+	for _, event := range r.chat {
+		if event.CanPlayerSee(playerID) {
+			visible = append(visible, event)
+		}
+	}
+	return visible
+}
+
+// ChatChannels returns the channels a player can post to, apart from
+// whispers: all, then any team channels of the current game, sorted.
+func (r *Room) ChatChannels(playerID string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var teams []string
+	for channel, members := range r.teamChannelsLocked() {
+		if containsString(members, playerID) {
+			teams = append(teams, channel)
+		}
+	}
+	sort.Strings(teams)
+
+	return append([]string{ChatChannelAll}, teams...)
+}
+
+// teamChannelsLocked returns the current game's team channels, if it has any.
+// Caller must hold r.mu.
+func (r *Room) teamChannelsLocked() map[string][]string {
+	teams, ok := r.Game.(ChatTeams)
+	if !ok {
+		return nil
+	}
+
+	channels := make(map[string][]string)
+	for channel, members := range teams.ChatChannels() {
+		// Built-in channels cannot be redefined by games
+		if channel == ChatChannelAll || channel == ChatChannelWhisper {
+			continue
+		}
+		channels[channel] = members
+	}
+	return channels
+}
+
+// SetMuted mutes or unmutes a player's chat. Muting lasts until the host
+// unmutes the player, across games.
+func (r *Room) SetMuted(playerID string, muted bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.Players[playerID]; !exists {
+		return ErrPlayerNotInRoom
+	}
+	if playerID == r.HostID {
+		return ErrCannotMuteHost
+	}
+
+	if muted {
+		r.muted[playerID] = true
+	} else {
+		delete(r.muted, playerID)
+	}
+	r.LastActivityAt = time.Now()
+	return nil
+}
+
+// IsMuted reports whether the host has muted a player's chat.
+func (r *Room) IsMuted(playerID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.muted[playerID]
+}
+
+// mutedLocked returns the muted players' IDs, sorted.
+// Caller must hold r.mu.
+func (r *Room) mutedLocked() []string {
+	muted := make([]string, 0, len(r.muted))
+	for id := range r.muted {
+		muted = append(muted, id)
+	}
+	sort.Strings(muted)
+	return muted
+}
+
+// containsString reports whether ids contains id.
+func containsString(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// teamGame gives two of its players a team chat channel.
+type teamGame struct {
+	stubGame
+}
+
+func (teamGame) ChatChannels() map[string][]string {
+	return map[string][]string{"wolves": {"bob", "carol"}, ChatChannelAll: {"bob"}}
+}
+
+// newChatRoom creates a room with alice hosting bob, carol and dave.
+func newChatRoom() *Room {
+	room := NewRoom("ABC123", "stub", &Player{ID: "alice", DisplayName: "Alice"}, 10)
+	room.AddPlayer(&Player{ID: "bob", DisplayName: "Bob"})
+	room.AddPlayer(&Player{ID: "carol", DisplayName: "Carol"})
+	room.AddPlayer(&Player{ID: "dave", DisplayName: "Dave"})
+	return room
+}
+
+func TestRoom_Chat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		sender      string
+		msg         ChatMessage
+		wantErr     error
+		wantVisible []string
+	}{
+		{"all", "bob", ChatMessage{Channel: ChatChannelAll, Text: "hi"}, nil, []string{"alice", "bob", "carol", "dave"}},
+		{"whisper", "bob", ChatMessage{Channel: ChatChannelWhisper, To: "dave", Text: "psst"}, nil, []string{"bob", "dave"}},
+		{"team", "carol", ChatMessage{Channel: "wolves", Text: "dave is the seer"}, nil, []string{"bob", "carol"}},
+		{"team non-member", "dave", ChatMessage{Channel: "wolves", Text: "let me in"}, ErrUnknownChannel, nil},
+		{"unknown channel", "bob", ChatMessage{Channel: "villagers", Text: "hello?"}, ErrUnknownChannel, nil},
+		{"whisper to self", "bob", ChatMessage{Channel: ChatChannelWhisper, To: "bob", Text: "me"}, ErrInvalidWhisper, nil},
+		{"whisper to stranger", "bob", ChatMessage{Channel: ChatChannelWhisper, To: "erin", Text: "hey"}, ErrInvalidWhisper, nil},
+		{"empty", "bob", ChatMessage{Channel: ChatChannelAll, Text: "  \n "}, ErrChatEmpty, nil},
+		{"too long", "bob", ChatMessage{Channel: ChatChannelAll, Text: strings.Repeat("é", MaxChatMessageLen+1)}, ErrChatTooLong, nil},
+		{"sender not in room", "erin", ChatMessage{Channel: ChatChannelAll, Text: "hi"}, ErrPlayerNotInRoom, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			room := newChatRoom()
+			if err := room.StartGame(teamGame{}, nil); err != nil {
+				t.Fatalf("StartGame() error = %v", err)
+			}
+
+			event, err := room.Chat(tt.sender, tt.msg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Chat() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			var visible []string
+			for _, id := range []string{"alice", "bob", "carol", "dave"} {
+				if event.CanPlayerSee(id) {
+					visible = append(visible, id)
+				}
+				history := room.ChatForPlayer(id)
+				if seen := len(history) == 1; seen != event.CanPlayerSee(id) {
+					t.Errorf("%s: history has %d messages, visible = %v", id, len(history), event.CanPlayerSee(id))
+				}
+			}
+			if !reflect.DeepEqual(visible, tt.wantVisible) {
+				t.Errorf("visible to %v, want %v", visible, tt.wantVisible)
+			}
+
+			var payload ChatMessagePayload
+			if err := json.Unmarshal(event.Payload, &payload); err != nil {
+				t.Fatalf("failed to decode payload: %v", err)
+			}
+			if payload.Channel != tt.msg.Channel || payload.To != tt.msg.To || payload.SenderName == "" {
+				t.Errorf("unexpected payload %+v", payload)
+			}
+		})
+	}
+}
+
+func TestRoom_ChatChannels(t *testing.T) {
+	t.Parallel()
+
+	room := newChatRoom()
+	if got := room.ChatChannels("bob"); !reflect.DeepEqual(got, []string{ChatChannelAll}) {
+		t.Errorf("lobby channels = %v, want only all", got)
+	}
+
+	room.StartGame(teamGame{}, nil)
+	if got := room.ChatChannels("bob"); !reflect.DeepEqual(got, []string{ChatChannelAll, "wolves"}) {
+		t.Errorf("team member channels = %v", got)
+	}
+	if got := room.ChatChannels("dave"); !reflect.DeepEqual(got, []string{ChatChannelAll}) {
+		t.Errorf("non-member channels = %v", got)
+	}
+}
+
+func TestRoom_ChatHistory(t *testing.T) {
+	t.Parallel()
+
+	room := newChatRoom()
+	room.StartGame(teamGame{}, nil)
+	if _, err := room.Chat("bob", ChatMessage{Channel: "wolves", Text: "first"}); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+
+	// Chat survives resets, and team messages stay with the team
+	room.ResetGame()
+	if len(room.ChatForPlayer("carol")) != 1 || len(room.ChatForPlayer("dave")) != 0 {
+		t.Fatal("expected team chat to survive the reset for its members only")
+	}
+
+	for i := 0; i < MaxChatHistory+10; i++ {
+		if _, err := room.Chat("alice", ChatMessage{Channel: ChatChannelAll, Text: "spam"}); err != nil {
+			t.Fatalf("Chat() error = %v", err)
+		}
+	}
+	history := room.ChatForPlayer("carol")
+	if len(history) != MaxChatHistory {
+		t.Fatalf("expected history capped at %d, got %d", MaxChatHistory, len(history))
+	}
+
+	// Chat and mutes survive snapshots
+	room.SetMuted("dave", true)
+	snapshot, err := room.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	restored, err := RestoreRoom(snapshot, nil)
+	if err != nil {
+		t.Fatalf("RestoreRoom() error = %v", err)
+	}
+	if got := restored.ChatForPlayer("carol"); !reflect.DeepEqual(got, history) {
+		t.Error("restored chat differs")
+	}
+	if !restored.IsMuted("dave") {
+		t.Error("expected mute to be restored")
+	}
+}
+
+func TestRoom_SetMuted(t *testing.T) {
+	t.Parallel()
+
+	room := newChatRoom()
+	if err := room.SetMuted("alice", true); !errors.Is(err, ErrCannotMuteHost) {
+		t.Errorf("muting host: error = %v, want ErrCannotMuteHost", err)
+	}
+	if err := room.SetMuted("erin", true); !errors.Is(err, ErrPlayerNotInRoom) {
+		t.Errorf("muting stranger: error = %v, want ErrPlayerNotInRoom", err)
+	}
+
+	if err := room.SetMuted("bob", true); err != nil {
+		t.Fatalf("SetMuted() error = %v", err)
+	}
+	if _, err := room.Chat("bob", ChatMessage{Channel: ChatChannelAll, Text: "hi"}); !errors.Is(err, ErrMuted) {
+		t.Errorf("muted chat: error = %v, want ErrMuted", err)
+	}
+	if got := room.GetState().Muted; !reflect.DeepEqual(got, []string{"bob"}) {
+		t.Errorf("state muted = %v, want [bob]", got)
+	}
+
+	room.SetMuted("bob", false)
+	if _, err := room.Chat("bob", ChatMessage{Channel: ChatChannelAll, Text: "hi"}); err != nil {
+		t.Errorf("unmuted chat: error = %v", err)
+	}
+}
@@ -21,9 +21,9 @@ type GameEvent struct {
 
 // EventVisibility controls which clients receive an event.
 type EventVisibility struct {
-	Public      bool     // All players and spectators see it
-	PlayerIDs   []string // Specific players who see it (for private info)
-	SpectatorOK bool     // Spectators can see it
+	Public      bool     `json:"public"`      // All players and spectators see it
+	PlayerIDs   []string `json:"playerIds"`   // Specific players who see it (for private info)
+	SpectatorOK bool     `json:"spectatorOk"` // Spectators can see it
 }
 
 // NewEvent creates a new event with auto-generated ID and timestamp.
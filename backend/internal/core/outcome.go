@@ -0,0 +1,73 @@
+package core
+
+import "encoding/json"
+
+// PlayerOutcome is how one player fared in a finished game, for player
+// statistics.
+type PlayerOutcome struct {
+	PlayerID   string `json:"playerId"`
+	Role       string `json:"role,omitempty"`
+	Team       string `json:"team,omitempty"`
+	Won        bool   `json:"won"`
+	Eliminated bool   `json:"eliminated,omitempty"` // Voted out, assassinated, etc.
+}
+
+// OutcomeReporter is implemented by games that can describe each player's
+// outcome in more detail than their GameResults.
+type OutcomeReporter interface {
+	// PlayerOutcomes returns the outcome of every player in the finished
+	// game.
+	PlayerOutcomes() []PlayerOutcome
+}
+
+// OutcomesFromResults derives player outcomes from game results: winners
+// from Winners and roles from a "roles" map of player ID to role in
+// FinalState, if there is one.
+func OutcomesFromResults(results GameResults, playerIDs []string) []PlayerOutcome {
+	winners := make(map[string]bool, len(results.Winners))
+	for _, id := range results.Winners {
+		winners[id] = true
+	}
+
+	// Games store roles with their own string types
+	var roles map[string]string
+	if data, err := json.Marshal(results.FinalState["roles"]); err == nil {
+		json.Unmarshal(data, &roles)
+	}
+
+	outcomes := make([]PlayerOutcome, 0, len(playerIDs))
+	for _, id := range playerIDs {
+		outcomes = append(outcomes, PlayerOutcome{
+			PlayerID: id,
+			Role:     roles[id],
+			Won:      winners[id],
+		})
+	}
+	return outcomes
+}
+
+// GameOutcomes returns the outcome of every player in the room's game, or
+// nil if no game has been played. Call it once the game has finished.
+func (r *Room) GameOutcomes() []PlayerOutcome {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.gameOutcomesLocked()
+}
+
+// gameOutcomesLocked returns the outcome of every player in the room's game.
+// Caller must hold r.mu.
+func (r *Room) gameOutcomesLocked() []PlayerOutcome {
+	if r.Game == nil {
+		return nil
+	}
+	if reporter, ok := r.Game.(OutcomeReporter); ok {
+		return reporter.PlayerOutcomes()
+	}
+
+	playerIDs := make([]string, 0, len(r.Players))
+	for id := range r.Players {
+		playerIDs = append(playerIDs, id)
+	}
+	return OutcomesFromResults(r.Game.GetResults(), playerIDs)
+}
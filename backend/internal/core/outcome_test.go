@@ -0,0 +1,36 @@
+package core
+
+import "testing"
+
+func TestOutcomesFromResults(t *testing.T) {
+	t.Parallel()
+
+	type role string
+	results := GameResults{
+		Winners: []string{"p1"},
+		FinalState: map[string]interface{}{
+			"roles": map[string]role{"p1": "seer", "p2": "werewolf"},
+		},
+	}
+
+	outcomes := OutcomesFromResults(results, []string{"p1", "p2", "p3"})
+	want := []PlayerOutcome{
+		{PlayerID: "p1", Role: "seer", Won: true},
+		{PlayerID: "p2", Role: "werewolf"},
+		{PlayerID: "p3"},
+	}
+	if len(outcomes) != len(want) {
+		t.Fatalf("expected %d outcomes, got %d", len(want), len(outcomes))
+	}
+	for i := range want {
+		if outcomes[i] != want[i] {
+			t.Errorf("outcome %d = %+v, want %+v", i, outcomes[i], want[i])
+		}
+	}
+
+	// Results without roles still give winners
+	outcomes = OutcomesFromResults(GameResults{Winners: []string{"p2"}}, []string{"p1", "p2"})
+	if outcomes[0].Won || !outcomes[1].Won || outcomes[1].Role != "" {
+		t.Errorf("unexpected outcomes without roles: %+v", outcomes)
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxPasswordLength is the longest room password in bytes; bcrypt ignores
+// anything beyond it.
+const MaxPasswordLength = 72
+
+// ErrPasswordTooLong is returned for passwords over MaxPasswordLength.
+var ErrPasswordTooLong = errors.New("password must be at most 72 bytes")
+
+// SetPassword requires players to give password to join the room.
+// An empty password removes the requirement.
+func (r *Room) SetPassword(password string) error {
+	var hash []byte
+	if password != "" {
+		if len(password) > MaxPasswordLength {
+			return ErrPasswordTooLong
+		}
+
+		var err error
+		hash, err = bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.passwordHash = hash
+	return nil
+}
+
+// HasPassword reports whether joining the room requires a password.
+func (r *Room) HasPassword() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.passwordHash) > 0
+}
+
+// CheckPassword reports whether password lets a player join the room.
+// Rooms without a password accept any.
+func (r *Room) CheckPassword(password string) bool {
+	r.mu.RLock()
+	hash := r.passwordHash
+	r.mu.RUnlock()
+
+	if len(hash) == 0 {
+		return true
+	}
+	// bcrypt is slow on purpose; don't hold the room lock meanwhile
+	return bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil
+}
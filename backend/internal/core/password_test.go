@@ -0,0 +1,60 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRoom_Password(t *testing.T) {
+	t.Parallel()
+
+	host := &Player{ID: "host", DisplayName: "Host", SessionToken: "token-host"}
+	room := NewRoom("ABC123", "werewolf", host, 10)
+
+	if room.HasPassword() || !room.CheckPassword("anything") {
+		t.Fatal("expected new room to accept any password")
+	}
+
+	if err := room.SetPassword("hunter2"); err != nil {
+		t.Fatalf("SetPassword() error = %v", err)
+	}
+	if !room.HasPassword() || !room.GetState().HasPassword {
+		t.Error("expected room to require a password")
+	}
+	if !room.CheckPassword("hunter2") {
+		t.Error("expected correct password to be accepted")
+	}
+	for _, wrong := range []string{"", "hunter3", "HUNTER2"} {
+		if room.CheckPassword(wrong) {
+			t.Errorf("expected %q to be rejected", wrong)
+		}
+	}
+
+	// The password survives a snapshot, but the hash never reaches clients
+	snapshot, err := room.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if strings.Contains(snapshot.PasswordHash, "hunter2") {
+		t.Error("snapshot contains the plain password")
+	}
+	restored, err := RestoreRoom(snapshot, nil)
+	if err != nil {
+		t.Fatalf("RestoreRoom() error = %v", err)
+	}
+	if !restored.CheckPassword("hunter2") || restored.CheckPassword("hunter3") {
+		t.Error("expected restored room to keep the password")
+	}
+
+	if err := room.SetPassword(""); err != nil {
+		t.Fatalf("SetPassword() error = %v", err)
+	}
+	if room.HasPassword() {
+		t.Error("expected empty password to remove the requirement")
+	}
+
+	if err := room.SetPassword(strings.Repeat("x", MaxPasswordLength+1)); !errors.Is(err, ErrPasswordTooLong) {
+		t.Errorf("expected ErrPasswordTooLong, got %v", err)
+	}
+}
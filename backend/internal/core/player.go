@@ -1,6 +1,8 @@
 package core
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"sync"
 	"time"
 
@@ -18,6 +20,9 @@ type Player struct {
 	Connected    bool      `json:"connected"`    // Current connection status (protected by mu)
 	JoinedAt     time.Time `json:"joinedAt"`     // When they joined
 	LastSeenAt   time.Time `json:"lastSeenAt"`   // Last activity timestamp (protected by mu)
+	ProfileID    string    `json:"profileId,omitempty"`   // Persistent profile the player joined with, if any
+	AvatarColor  string    `json:"avatarColor,omitempty"` // From the profile
+	Bot          *BotSettings `json:"bot,omitempty"`       // Set for bot seats (see NewBotPlayer)
 }
 
 // NewPlayer creates a new player with generated ID and session token.
@@ -77,7 +82,12 @@ func (p *Player) IsStale(timeout time.Duration) bool {
 }
 
 // generateSessionToken creates a random token for player sessions.
-// In production, use a cryptographically secure token generator.
+// The server replaces it with a signed token (see internal/auth).
 func generateSessionToken() string {
-	return uuid.New().String()
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		// crypto/rand only fails if the OS entropy source is broken
+		panic("failed to generate session token: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(token)
 }
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Reaction kinds.
+const (
+	ReactionEmoji = "emoji" // An emoji, optionally aimed at a player
+	ReactionPoint = "point" // "I suspect X": pointing at a player
+)
+
+// ReactionEmojis are the emoji players can react with.
+var ReactionEmojis = []string{"👍", "👎", "😂", "😮", "🤔", "😡", "👀", "🤫"}
+
+// ErrInvalidReaction is returned for reactions that are malformed or aimed at
+// someone not in the room.
+var ErrInvalidReaction = errors.New("invalid reaction")
+
+// Reaction is an ephemeral gesture, such as an emoji or pointing at a
+// suspect. Reactions are broadcast as they happen but never added to the
+// event log, so they are not replayed on reconnect.
+type Reaction struct {
+	Kind     string    `json:"kind"`
+	PlayerID string    `json:"playerId"`           // Who reacted
+	TargetID string    `json:"targetId,omitempty"` // Player reacted to; required for pointing
+	Emoji    string    `json:"emoji,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// ReactionObserver is implemented by games that take table talk into
+// account, such as summarizing accusations in their public state.
+type ReactionObserver interface {
+	// ObserveReaction is called with every reaction while the game runs.
+	ObserveReaction(reaction Reaction)
+
+	// Accusations summarizes the accusations that currently count, most
+	// accused first, or nil outside the stretches where players accuse.
+	Accusations() []Accusation
+}
+
+// React validates a player's reaction and passes it to the game. The
+// returned reaction has the player and time filled in, ready to broadcast.
+// Muted players cannot react.
+func (r *Room) React(playerID string, reaction Reaction) (Reaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.Players[playerID]; !exists {
+		return Reaction{}, ErrPlayerNotInRoom
+	}
+	if r.muted[playerID] {
+		return Reaction{}, ErrMuted
+	}
+	if reaction.TargetID != "" {
+		if _, exists := r.Players[reaction.TargetID]; !exists {
+			return Reaction{}, fmt.Errorf("%w: target not in room", ErrInvalidReaction)
+		}
+	}
+
+	switch reaction.Kind {
+	case ReactionEmoji:
+		if !containsString(ReactionEmojis, reaction.Emoji) {
+			return Reaction{}, fmt.Errorf("%w: unsupported emoji", ErrInvalidReaction)
+		}
+	case ReactionPoint:
+		if reaction.TargetID == "" || reaction.TargetID == playerID {
+			return Reaction{}, fmt.Errorf("%w: point at another player", ErrInvalidReaction)
+		}
+		reaction.Emoji = ""
+	default:
+		return Reaction{}, fmt.Errorf("%w: unknown kind %q", ErrInvalidReaction, reaction.Kind)
+	}
+
+	reaction.PlayerID = playerID
+	reaction.At = time.Now()
+
+	if observer, ok := r.Game.(ReactionObserver); ok && r.Status == RoomStatusPlaying {
+		observer.ObserveReaction(reaction)
+	}
+
+	return reaction, nil
+}
+
+// Accusation summarizes who is pointing at a player.
+type Accusation struct {
+	SuspectID string   `json:"suspectId"`
+	AccusedBy []string `json:"accusedBy"`
+}
+
+// Accusations tracks who each player last pointed at during one stretch of a
+// game, such as a day or a team vote. The zero value is ready to use. It is
+// not safe for concurrent use.
+type Accusations struct {
+	stretch string
+	targets map[string]string // Accuser → suspect
+}
+
+// Point records an accusation made during stretch, forgetting those from
+// earlier stretches. Pointing at the same suspect again withdraws it.
+func (a *Accusations) Point(stretch, accuserID, suspectID string) {
+	if a.targets == nil || a.stretch != stretch {
+		a.stretch = stretch
+		a.targets = make(map[string]string)
+	}
+
+	if a.targets[accuserID] == suspectID {
+		delete(a.targets, accuserID)
+		return
+	}
+	a.targets[accuserID] = suspectID
+}
+
+// Summary returns the accusations made during stretch, most accused first.
+func (a *Accusations) Summary(stretch string) []Accusation {
+	if a.stretch != stretch || len(a.targets) == 0 {
+		return nil
+	}
+
+	bySuspect := make(map[string][]string)
+	for accuser, suspect := range a.targets {
+		bySuspect[suspect] = append(bySuspect[suspect], accuser)
+	}
+
+	summary := make([]Accusation, 0, len(bySuspect))
+	for suspect, accusers := range bySuspect {
+		sort.Strings(accusers)
+		summary = append(summary, Accusation{SuspectID: suspect, AccusedBy: accusers})
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if len(summary[i].AccusedBy) != len(summary[j].AccusedBy) {
+			return len(summary[i].AccusedBy) > len(summary[j].AccusedBy)
+		}
+		return summary[i].SuspectID < summary[j].SuspectID
+	})
+	return summary
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// watchingGame records the reactions it observes.
+type watchingGame struct {
+	stubGame
+	observed *[]Reaction
+}
+
+func (g watchingGame) ObserveReaction(reaction Reaction) {
+	*g.observed = append(*g.observed, reaction)
+}
+
+// Accusations counts every observed point as an accusation.
+func (g watchingGame) Accusations() []Accusation {
+	var accusations []Accusation
+	for _, reaction := range *g.observed {
+		if reaction.Kind == ReactionPoint {
+			accusations = append(accusations, Accusation{SuspectID: reaction.TargetID, AccusedBy: []string{reaction.PlayerID}})
+		}
+	}
+	return accusations
+}
+
+func TestRoom_React(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		sender   string
+		reaction Reaction
+		wantErr  error
+	}{
+		{"emoji", "bob", Reaction{Kind: ReactionEmoji, Emoji: "👀"}, nil},
+		{"emoji on a player", "bob", Reaction{Kind: ReactionEmoji, Emoji: "🤔", TargetID: "carol"}, nil},
+		{"point", "bob", Reaction{Kind: ReactionPoint, TargetID: "carol"}, nil},
+		{"unsupported emoji", "bob", Reaction{Kind: ReactionEmoji, Emoji: "🦄"}, ErrInvalidReaction},
+		{"point at nobody", "bob", Reaction{Kind: ReactionPoint}, ErrInvalidReaction},
+		{"point at self", "bob", Reaction{Kind: ReactionPoint, TargetID: "bob"}, ErrInvalidReaction},
+		{"target not in room", "bob", Reaction{Kind: ReactionPoint, TargetID: "erin"}, ErrInvalidReaction},
+		{"unknown kind", "bob", Reaction{Kind: "wave"}, ErrInvalidReaction},
+		{"sender not in room", "erin", Reaction{Kind: ReactionEmoji, Emoji: "👍"}, ErrPlayerNotInRoom},
+		{"muted", "dave", Reaction{Kind: ReactionEmoji, Emoji: "👍"}, ErrMuted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var observed []Reaction
+			room := newChatRoom()
+			room.SetMuted("dave", true)
+			if err := room.StartGame(watchingGame{observed: &observed}, nil); err != nil {
+				t.Fatalf("StartGame() error = %v", err)
+			}
+			logLength := room.GetEventLogLength()
+
+			got, err := room.React(tt.sender, tt.reaction)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("React() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(observed) != 0 {
+					t.Error("expected invalid reactions not to reach the game")
+				}
+				return
+			}
+
+			if got.PlayerID != tt.sender || got.At.IsZero() {
+				t.Errorf("expected sender and time to be set, got %+v", got)
+			}
+			if !reflect.DeepEqual(observed, []Reaction{got}) {
+				t.Errorf("game observed %+v, want %+v", observed, got)
+			}
+			if room.GetEventLogLength() != logLength {
+				t.Error("expected reactions to stay out of the event log")
+			}
+			if accused := len(room.GetState().Accusations) > 0; accused != (tt.reaction.Kind == ReactionPoint) {
+				t.Errorf("expected room state accusations only after pointing, got %+v", room.GetState().Accusations)
+			}
+		})
+	}
+}
+
+func TestAccusations(t *testing.T) {
+	t.Parallel()
+
+	var a Accusations
+	if got := a.Summary("day"); got != nil {
+		t.Fatalf("expected no accusations, got %+v", got)
+	}
+
+	a.Point("day", "alice", "bob")
+	a.Point("day", "carol", "bob")
+	a.Point("day", "bob", "alice")
+	a.Point("day", "dave", "carol")
+	a.Point("day", "dave", "carol") // Withdrawn
+
+	want := []Accusation{
+		{SuspectID: "bob", AccusedBy: []string{"alice", "carol"}},
+		{SuspectID: "alice", AccusedBy: []string{"bob"}},
+	}
+	if got := a.Summary("day"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Summary() = %+v, want %+v", got, want)
+	}
+	if got := a.Summary("night"); got != nil {
+		t.Errorf("expected no accusations for another stretch, got %+v", got)
+	}
+
+	// Changing minds replaces the accusation
+	a.Point("day", "carol", "alice")
+	if got := a.Summary("day"); len(got) != 2 || got[0].SuspectID != "alice" || len(got[0].AccusedBy) != 2 {
+		t.Errorf("expected alice to be the most accused, got %+v", got)
+	}
+
+	// A new stretch starts over
+	a.Point("vote 2", "alice", "dave")
+	want = []Accusation{{SuspectID: "dave", AccusedBy: []string{"alice"}}}
+	if got := a.Summary("vote 2"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Summary() = %+v, want %+v", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"crypto/sha256"
 	"errors"
 	"sync"
 	"time"
@@ -25,28 +26,86 @@ type Room struct {
 	Status     RoomStatus `json:"status"`     // Current status
 	GameType   string     `json:"gameType"`   // "werewolf", "avalon", etc.
 	MaxPlayers int        `json:"maxPlayers"` // Maximum allowed players
+	Public     bool       `json:"public"`     // Listed in the public room browser
+	Title      string     `json:"title"`      // Display title for public rooms
 
 	HostID  string             `json:"hostId"`  // PlayerID of the host
 	Players map[string]*Player `json:"players"` // PlayerID → Player
 
 	EventLog []GameEvent `json:"eventLog"` // Append-only event history
 	Game     Game        `json:"-"`        // Game-specific state machine
+
+	LastActivityAt time.Time `json:"lastActivityAt"` // Last room change (events, joins, resets)
+
+	scoreboard   Scoreboard                         // Points across games until the host ends the session
+	chat         []GameEvent                        // Recent chat messages, kept across games
+	muted        map[string]bool                    // PlayerIDs the host has muted in chat
+	observer     RoomObserver                       // Optional activity observer (metrics)
+	tokens       map[[sha256.Size]byte]sessionEntry // Accepted session tokens by hash
+	passwordHash []byte                             // bcrypt hash of the join password (nil = none)
+}
+
+// RoomObserver is notified of activity in a room, e.g. to record metrics.
+// Methods are called while the room lock is held, so implementations must be
+// fast and must not call back into the room.
+type RoomObserver interface {
+	// EventsAppended is called whenever events are added to the event log.
+	EventsAppended(gameType string, events []GameEvent)
+
+	// ActionProcessed is called after a player action has been handled,
+	// successfully or not.
+	ActionProcessed(gameType string, actionType string, duration time.Duration, err error)
+}
+
+// Publish lists the room in the public room browser under title.
+func (r *Room) Publish(title string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Public = true
+	r.Title = title
+}
+
+// SetObserver attaches an activity observer to the room.
+func (r *Room) SetObserver(observer RoomObserver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.observer = observer
+}
+
+// appendEventsLocked adds events to the log and notifies the observer.
+// Caller must hold r.mu.
+func (r *Room) appendEventsLocked(events ...GameEvent) {
+	r.EventLog = append(r.EventLog, events...)
+	r.LastActivityAt = time.Now()
+
+	if r.observer != nil && len(events) > 0 {
+		r.observer.EventsAppended(r.GameType, events)
+	}
 }
 
 // NewRoom creates a new room with a generated code.
 func NewRoom(roomCode string, gameType string, hostPlayer *Player, maxPlayers int) *Room {
-	return &Room{
-		ID:         roomCode,
-		CreatedAt:  time.Now(),
-		Status:     RoomStatusWaiting,
-		GameType:   gameType,
-		MaxPlayers: maxPlayers,
-		HostID:     hostPlayer.ID,
+	now := time.Now()
+	room := &Room{
+		ID:             roomCode,
+		CreatedAt:      now,
+		LastActivityAt: now,
+		Status:         RoomStatusWaiting,
+		GameType:       gameType,
+		MaxPlayers:     maxPlayers,
+		HostID:         hostPlayer.ID,
 		Players: map[string]*Player{
 			hostPlayer.ID: hostPlayer,
 		},
-		EventLog: make([]GameEvent, 0),
+		EventLog:   make([]GameEvent, 0),
+		scoreboard: newScoreboard(now),
+		chat:       make([]GameEvent, 0),
+		muted:      make(map[string]bool),
 	}
+	room.indexTokenLocked(hostPlayer)
+	return room
 }
 
 // AddPlayer adds a new player to the room.
@@ -66,11 +125,22 @@ func (r *Room) AddPlayer(player *Player) error {
 		return errors.New("player already in room")
 	}
 
+	// One seat per profile, so a profile is never rated against itself
+	if player.ProfileID != "" {
+		for _, seated := range r.Players {
+			if seated.ProfileID == player.ProfileID {
+				return errors.New("profile already has a seat in this room")
+			}
+		}
+	}
+
 	r.Players[player.ID] = player
+	r.indexTokenLocked(player)
+	r.LastActivityAt = time.Now()
 	return nil
 }
 
-// RemovePlayer removes a player from the room.
+// RemovePlayer removes a player from the room and revokes their session.
 func (r *Room) RemovePlayer(playerID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -80,6 +150,34 @@ func (r *Room) RemovePlayer(playerID string) error {
 	}
 
 	delete(r.Players, playerID)
+	r.revokeTokensLocked(playerID, "")
+	r.LastActivityAt = time.Now()
+	return nil
+}
+
+// ErrGameInProgress is returned for lobby operations attempted mid-game.
+var ErrGameInProgress = errors.New("game in progress")
+
+// KickPlayer removes a player from the lobby and revokes their session.
+// Players cannot be removed once a game has started, since the game holds
+// references to them.
+func (r *Room) KickPlayer(playerID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Status != RoomStatusWaiting {
+		return ErrGameInProgress
+	}
+	if playerID == r.HostID {
+		return errors.New("cannot remove the host")
+	}
+	if _, exists := r.Players[playerID]; !exists {
+		return errors.New("player not in room")
+	}
+
+	delete(r.Players, playerID)
+	r.revokeTokensLocked(playerID, "")
+	r.LastActivityAt = time.Now()
 	return nil
 }
 
@@ -96,20 +194,6 @@ func (r *Room) GetPlayer(playerID string) (*Player, error) {
 	return player, nil
 }
 
-// GetPlayerByToken finds a player by their session token.
-func (r *Room) GetPlayerByToken(token string) (*Player, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	for _, player := range r.Players {
-		if player.SessionToken == token {
-			return player, nil
-		}
-	}
-
-	return nil, errors.New("invalid session token")
-}
-
 // GetPlayers returns all players as a slice.
 func (r *Room) GetPlayers() []*Player {
 	r.mu.RLock()
@@ -136,7 +220,7 @@ func (r *Room) AppendEvent(event GameEvent) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.EventLog = append(r.EventLog, event)
+	r.appendEventsLocked(event)
 }
 
 // AppendEvents adds multiple events to the event log.
@@ -144,7 +228,7 @@ func (r *Room) AppendEvents(events []GameEvent) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.EventLog = append(r.EventLog, events...)
+	r.appendEventsLocked(events...)
 }
 
 // GetEventsForPlayer returns all events this player can see.
@@ -215,13 +299,16 @@ func (r *Room) StartGame(game Game, config GameConfig) error {
 
 	r.Game = game
 	r.Status = RoomStatusPlaying
-	r.EventLog = append(r.EventLog, events...)
+	r.scoreboard.Round++
+	r.scoreboard.Scored = false
+	r.appendEventsLocked(events...)
 
 	return nil
 }
 
 // ResetGame resets the room back to waiting status for a new game.
-// Keeps players but clears game state and event log.
+// Keeps players, chat and the session scoreboard but clears game state and
+// event log.
 func (r *Room) ResetGame() error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -234,15 +321,23 @@ func (r *Room) ResetGame() error {
 	r.Game = nil
 	r.EventLog = make([]GameEvent, 0)
 	r.Status = RoomStatusWaiting
+	r.LastActivityAt = time.Now()
 
 	return nil
 }
 
 // ProcessAction validates and processes a player action.
-func (r *Room) ProcessAction(playerID string, action Action) ([]GameEvent, error) {
+func (r *Room) ProcessAction(playerID string, action Action) (events []GameEvent, err error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if r.observer != nil {
+		start := time.Now()
+		defer func() {
+			r.observer.ActionProcessed(r.GameType, action.Type, time.Since(start), err)
+		}()
+	}
+
 	if r.Status != RoomStatusPlaying {
 		return nil, errors.New("no game in progress")
 	}
@@ -257,13 +352,13 @@ func (r *Room) ProcessAction(playerID string, action Action) ([]GameEvent, error
 	}
 
 	// Process action
-	events, err := r.Game.ProcessAction(playerID, action)
+	events, err = r.Game.ProcessAction(playerID, action)
 	if err != nil {
 		return nil, err
 	}
 
 	// Append events to log
-	r.EventLog = append(r.EventLog, events...)
+	r.appendEventsLocked(events...)
 
 	// Check if game finished
 	if r.Game.IsFinished() {
@@ -295,13 +390,71 @@ func (r *Room) GetEventsSince(startIndex int) []GameEvent {
 	return events
 }
 
-// IsAnyPlayerConnected safely checks if any player is connected.
+// GetAllEvents returns a copy of the full, unfiltered event log.
+// Only for operators: it includes every player's private events.
+func (r *Room) GetAllEvents() []GameEvent {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := make([]GameEvent, len(r.EventLog))
+	copy(events, r.EventLog)
+	return events
+}
+
+// LastActivity returns the most recent of the room's last change and its
+// players' last seen times.
+func (r *Room) LastActivity() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	last := r.LastActivityAt
+	for _, player := range r.Players {
+		if seen := player.GetLastSeenAt(); seen.After(last) {
+			last = seen
+		}
+	}
+	return last
+}
+
+// GameInspection is an unfiltered view of a room's game for operators.
+type GameInspection struct {
+	Phase        GamePhase              `json:"phase"`
+	Finished     bool                   `json:"finished"`
+	PublicState  PublicState            `json:"publicState"`
+	PlayerStates map[string]PlayerState `json:"playerStates"` // PlayerID → state
+}
+
+// InspectGame returns the game's public state and every player's private
+// state, or nil if no game has been started.
+func (r *Room) InspectGame() *GameInspection {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.Game == nil {
+		return nil
+	}
+
+	playerStates := make(map[string]PlayerState, len(r.Players))
+	for playerID := range r.Players {
+		playerStates[playerID] = r.Game.GetPlayerState(playerID)
+	}
+
+	return &GameInspection{
+		Phase:        r.Game.GetPhase(),
+		Finished:     r.Game.IsFinished(),
+		PublicState:  r.Game.GetPublicState(),
+		PlayerStates: playerStates,
+	}
+}
+
+// IsAnyPlayerConnected safely checks if any player other than a bot is
+// connected.
 func (r *Room) IsAnyPlayerConnected() bool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	for _, player := range r.Players {
-		if player.IsConnected() {
+		if !player.IsBot() && player.IsConnected() {
 			return true
 		}
 	}
@@ -309,14 +462,14 @@ func (r *Room) IsAnyPlayerConnected() bool {
 }
 
 // GetCleanupInfo returns information needed for cleanup decisions.
-// Returns: status, createdAt, anyConnected
+// Returns: status, createdAt, anyConnected (bots don't count)
 func (r *Room) GetCleanupInfo() (RoomStatus, time.Time, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	anyConnected := false
 	for _, player := range r.Players {
-		if player.IsConnected() {
+		if !player.IsBot() && player.IsConnected() {
 			anyConnected = true
 			break
 		}
@@ -327,12 +480,20 @@ func (r *Room) GetCleanupInfo() (RoomStatus, time.Time, bool) {
 
 // RoomState is a snapshot of room state for client consumption.
 type RoomState struct {
-	ID         string     `json:"id"`
-	Status     RoomStatus `json:"status"`
-	GameType   string     `json:"gameType"`
-	MaxPlayers int        `json:"maxPlayers"`
-	HostID     string     `json:"hostId"`
-	Players    []*Player  `json:"players"`
+	ID          string     `json:"id"`
+	Status      RoomStatus `json:"status"`
+	GameType    string     `json:"gameType"`
+	MaxPlayers  int        `json:"maxPlayers"`
+	HostID      string     `json:"hostId"`
+	Players     []*Player  `json:"players"`
+	HasPassword bool       `json:"hasPassword,omitempty"` // Joining requires a password or invite
+	Muted       []string   `json:"muted,omitempty"`       // PlayerIDs muted in chat
+	Public      bool       `json:"public,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+
+	// Accusations summarizes who players point at, while the game counts it
+	Accusations []Accusation `json:"accusations,omitempty"`
 }
 
 // GetState returns a snapshot of the room state.
@@ -350,16 +511,30 @@ func (r *Room) GetState() RoomState {
 			Connected:   player.IsConnected(),
 			JoinedAt:    player.JoinedAt,
 			LastSeenAt:  player.GetLastSeenAt(),
+			ProfileID:   player.ProfileID,
+			AvatarColor: player.AvatarColor,
+			Bot:         player.Bot,
 		}
 		players = append(players, playerCopy)
 	}
 
+	var accusations []Accusation
+	if observer, ok := r.Game.(ReactionObserver); ok && r.Status == RoomStatusPlaying {
+		accusations = observer.Accusations()
+	}
+
 	return RoomState{
-		ID:         r.ID,
-		Status:     r.Status,
-		GameType:   r.GameType,
-		MaxPlayers: r.MaxPlayers,
-		HostID:     r.HostID,
-		Players:    players,
+		ID:          r.ID,
+		Status:      r.Status,
+		GameType:    r.GameType,
+		MaxPlayers:  r.MaxPlayers,
+		HostID:      r.HostID,
+		Players:     players,
+		HasPassword: len(r.passwordHash) > 0,
+		Muted:       r.mutedLocked(),
+		Public:      r.Public,
+		Title:       r.Title,
+		CreatedAt:   r.CreatedAt,
+		Accusations: accusations,
 	}
 }
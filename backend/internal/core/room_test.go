@@ -104,6 +104,16 @@ func TestRoom_AddPlayer(t *testing.T) {
 			wantErr:     true,
 			errContains: "player already in room",
 		},
+		{
+			name: "fail when profile already has a seat",
+			setupRoom: func() *Room {
+				host := &Player{ID: "host", DisplayName: "Host", SessionToken: "token-host", ProfileID: "profile-1"}
+				return NewRoom("ABC123", "werewolf", host, 10)
+			},
+			playerToAdd: &Player{ID: "player1", DisplayName: "Player1", SessionToken: "token-1", ProfileID: "profile-1"},
+			wantErr:     true,
+			errContains: "profile already has a seat",
+		},
 	}
 
 	for _, tt := range tests {
@@ -363,3 +373,48 @@ func hasSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestRoom_LastActivity(t *testing.T) {
+	t.Parallel()
+
+	host := &Player{ID: "host-123", DisplayName: "Alice", LastSeenAt: time.Now().Add(-2 * time.Hour)}
+	room := NewRoom("ABC123", "werewolf", host, 10)
+	room.LastActivityAt = time.Now().Add(-time.Hour)
+
+	if got := room.LastActivity(); !got.Equal(room.LastActivityAt) {
+		t.Errorf("expected room activity time, got %v", got)
+	}
+
+	// A player being seen counts as activity
+	host.UpdateLastSeen()
+	if got := room.LastActivity(); time.Since(got) > time.Second {
+		t.Errorf("expected player activity to be recent, got %v", got)
+	}
+
+	// Appending events updates the room's own activity time
+	room.LastActivityAt = time.Now().Add(-time.Hour)
+	room.AppendEvent(GameEvent{ID: "e1", Type: EventPlayerJoined})
+	if time.Since(room.LastActivityAt) > time.Second {
+		t.Error("expected AppendEvent to update LastActivityAt")
+	}
+}
+
+func TestRoom_GetAllEvents(t *testing.T) {
+	t.Parallel()
+
+	room := NewRoom("ABC123", "werewolf", &Player{ID: "host-123"}, 10)
+	private, _ := NewPrivateEvent("role_assigned", "system", nil, []string{"host-123"})
+	room.AppendEvent(private)
+
+	events := room.GetAllEvents()
+	if len(events) != 1 || events[0].Visibility.Public {
+		t.Fatalf("expected the private event, got %+v", events)
+	}
+	if len(room.GetPublicEvents()) != 0 {
+		t.Error("private event should not be public")
+	}
+
+	if room.InspectGame() != nil {
+		t.Error("expected no game inspection before the game starts")
+	}
+}
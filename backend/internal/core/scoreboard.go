@@ -0,0 +1,250 @@
+package core
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// Session event types, appended to the room's event log.
+const (
+	EventRoundScored    = "round_scored"    // A finished game was added to the scoreboard
+	EventFinalStandings = "final_standings" // The host ended the session
+)
+
+// ErrNoRounds is returned when ending a session before any game finished.
+var ErrNoRounds = errors.New("no rounds have been scored")
+
+// ScoringRules award session points for a player's outcome in a game.
+type ScoringRules struct {
+	Win      int `json:"win" yaml:"win"`
+	Loss     int `json:"loss" yaml:"loss"`
+	Survived int `json:"survived" yaml:"survived"` // Bonus for not being eliminated
+
+	// RoleBonus adds points for winning with a role, e.g. a lone tanner.
+	RoleBonus map[string]int `json:"roleBonus,omitempty" yaml:"roleBonus"`
+}
+
+// DefaultScoringRules returns the rules used for game types without their
+// own: one point per win.
+func DefaultScoringRules() ScoringRules {
+	return ScoringRules{Win: 1}
+}
+
+// Points returns the points a player scores for an outcome.
+func (s ScoringRules) Points(outcome PlayerOutcome) int {
+	points := s.Loss
+	if outcome.Won {
+		points = s.Win + s.RoleBonus[outcome.Role]
+	}
+	if !outcome.Eliminated {
+		points += s.Survived
+	}
+	return points
+}
+
+// RoundScore is the scoring of one finished game in a session.
+type RoundScore struct {
+	Round      int            `json:"round"`
+	Points     map[string]int `json:"points"` // PlayerID → points this round
+	Winners    []string       `json:"winners"`
+	WinReason  string         `json:"winReason,omitempty"`
+	FinishedAt time.Time      `json:"finishedAt"`
+}
+
+// Standing is a player's place in a session.
+type Standing struct {
+	Rank        int    `json:"rank"` // Tied players share a rank
+	PlayerID    string `json:"playerId"`
+	DisplayName string `json:"displayName"`
+	Points      int    `json:"points"`
+	Wins        int    `json:"wins"`
+}
+
+// SessionSummary is the state of a room's session.
+type SessionSummary struct {
+	Round     int          `json:"round"` // Games started this session, including one in progress
+	Rounds    []RoundScore `json:"rounds"`
+	Standings []Standing   `json:"standings"`
+	StartedAt time.Time    `json:"startedAt"`
+}
+
+// RoundScoredPayload is the payload of a round_scored event.
+type RoundScoredPayload struct {
+	Round     RoundScore `json:"round"`
+	Standings []Standing `json:"standings"` // Including this round
+}
+
+// Scoreboard accumulates points across the games played in a room until the
+// host ends the session. It survives game resets.
+type Scoreboard struct {
+	Round     int               `json:"round"`
+	Rounds    []RoundScore      `json:"rounds"`
+	Names     map[string]string `json:"names"` // PlayerID → display name, kept for players who left
+	StartedAt time.Time         `json:"startedAt"`
+	Scored    bool              `json:"scored"` // The current game has been scored
+}
+
+// newScoreboard starts an empty session.
+func newScoreboard(now time.Time) Scoreboard {
+	return Scoreboard{
+		Rounds:    make([]RoundScore, 0),
+		Names:     make(map[string]string),
+		StartedAt: now,
+	}
+}
+
+// clone returns a deep copy.
+func (s Scoreboard) clone() Scoreboard {
+	c := s
+	c.Rounds = make([]RoundScore, len(s.Rounds))
+	for i, round := range s.Rounds {
+		points := make(map[string]int, len(round.Points))
+		for id, p := range round.Points {
+			points[id] = p
+		}
+		round.Points = points
+		round.Winners = append([]string(nil), round.Winners...)
+		c.Rounds[i] = round
+	}
+	c.Names = make(map[string]string, len(s.Names))
+	for id, name := range s.Names {
+		c.Names[id] = name
+	}
+	return c
+}
+
+// summary ranks the players by points, then wins, then name.
+func (s Scoreboard) summary() SessionSummary {
+	totals := make(map[string]*Standing)
+	winners := make(map[string]bool)
+	for _, round := range s.Rounds {
+		for id := range winners {
+			delete(winners, id)
+		}
+		for _, id := range round.Winners {
+			winners[id] = true
+		}
+
+		for id, points := range round.Points {
+			standing, ok := totals[id]
+			if !ok {
+				standing = &Standing{PlayerID: id, DisplayName: s.Names[id]}
+				totals[id] = standing
+			}
+			standing.Points += points
+			if winners[id] {
+				standing.Wins++
+			}
+		}
+	}
+
+	standings := make([]Standing, 0, len(totals))
+	for _, standing := range totals {
+		standings = append(standings, *standing)
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.DisplayName < b.DisplayName
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && standings[i].Points == standings[i-1].Points && standings[i].Wins == standings[i-1].Wins {
+			standings[i].Rank = standings[i-1].Rank
+		}
+	}
+
+	return SessionSummary{
+		Round:     s.Round,
+		Rounds:    s.clone().Rounds,
+		Standings: standings,
+		StartedAt: s.StartedAt,
+	}
+}
+
+// ScoreGame adds the room's game to the scoreboard with rules and appends a
+// round_scored event. Call it once the game has finished; it returns false if
+// there is no game or it was already scored.
+func (r *Room) ScoreGame(rules ScoringRules) (GameEvent, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Game == nil || r.scoreboard.Scored {
+		return GameEvent{}, false, nil
+	}
+
+	results := r.Game.GetResults()
+	round := RoundScore{
+		Round:      r.scoreboard.Round,
+		Points:     make(map[string]int),
+		Winners:    append([]string(nil), results.Winners...),
+		WinReason:  results.WinReason,
+		FinishedAt: time.Now(),
+	}
+	for _, outcome := range r.gameOutcomesLocked() {
+		round.Points[outcome.PlayerID] = rules.Points(outcome)
+		if player, ok := r.Players[outcome.PlayerID]; ok {
+			r.scoreboard.Names[outcome.PlayerID] = player.DisplayName
+		}
+	}
+
+	r.scoreboard.Rounds = append(r.scoreboard.Rounds, round)
+	r.scoreboard.Scored = true
+
+	event, err := NewPublicEvent(EventRoundScored, "system", RoundScoredPayload{
+		Round:     round,
+		Standings: r.scoreboard.summary().Standings,
+	})
+	if err != nil {
+		return GameEvent{}, false, err
+	}
+	r.appendEventsLocked(event)
+
+	return event, true, nil
+}
+
+// SessionSummary returns the room's scoreboard so far.
+func (r *Room) SessionSummary() SessionSummary {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.scoreboard.summary()
+}
+
+// EndSession closes the session with a final_standings event and starts a
+// new, empty one. The game in progress, if any, is left alone and counts
+// towards the new session.
+func (r *Room) EndSession() (SessionSummary, GameEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.scoreboard.Rounds) == 0 {
+		return SessionSummary{}, GameEvent{}, ErrNoRounds
+	}
+
+	summary := r.scoreboard.summary()
+	event, err := NewPublicEvent(EventFinalStandings, "system", summary)
+	if err != nil {
+		return SessionSummary{}, GameEvent{}, err
+	}
+	r.appendEventsLocked(event)
+
+	// An unscored game, such as one in progress, becomes the new session's
+	// first round; a scored one stays in the old session
+	next := newScoreboard(time.Now())
+	if r.Game != nil {
+		next.Scored = r.scoreboard.Scored
+		if !next.Scored {
+			next.Round = 1
+		}
+	}
+	r.scoreboard = next
+
+	return summary, event, nil
+}
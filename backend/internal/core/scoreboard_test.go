@@ -0,0 +1,168 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// winnerGame is a finished game won by one player.
+type winnerGame struct {
+	stubGame
+	winner string
+}
+
+func (g winnerGame) GetResults() GameResults {
+	return GameResults{Winners: []string{g.winner}, WinReason: "last one standing"}
+}
+
+func TestScoringRules_Points(t *testing.T) {
+	t.Parallel()
+
+	rules := ScoringRules{Win: 3, Loss: -1, Survived: 1, RoleBonus: map[string]int{"tanner": 2}}
+
+	tests := []struct {
+		name    string
+		outcome PlayerOutcome
+		want    int
+	}{
+		{"win", PlayerOutcome{Won: true}, 4},
+		{"win eliminated", PlayerOutcome{Won: true, Eliminated: true}, 3},
+		{"loss", PlayerOutcome{}, 0},
+		{"loss eliminated", PlayerOutcome{Eliminated: true}, -1},
+		{"role bonus on win", PlayerOutcome{Role: "tanner", Won: true, Eliminated: true}, 5},
+		{"no role bonus on loss", PlayerOutcome{Role: "tanner", Eliminated: true}, -1},
+	}
+
+	for _, tt := range tests {
+		if got := rules.Points(tt.outcome); got != tt.want {
+			t.Errorf("%s: Points() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// playRound starts a game won by winner, finishes and scores it, then resets
+// the room for the next round.
+func playRound(t *testing.T, room *Room, winner string) {
+	t.Helper()
+
+	if err := room.StartGame(winnerGame{winner: winner}, nil); err != nil {
+		t.Fatalf("StartGame() error = %v", err)
+	}
+	room.SetStatus(RoomStatusFinished)
+	if _, scored, err := room.ScoreGame(DefaultScoringRules()); err != nil || !scored {
+		t.Fatalf("ScoreGame() = %v, %v, want scored", scored, err)
+	}
+	if _, scored, _ := room.ScoreGame(DefaultScoringRules()); scored {
+		t.Fatal("expected a game to be scored only once")
+	}
+	if err := room.ResetGame(); err != nil {
+		t.Fatalf("ResetGame() error = %v", err)
+	}
+}
+
+func TestRoom_Session(t *testing.T) {
+	t.Parallel()
+
+	room := NewRoom("ABC123", "stub", &Player{ID: "alice", DisplayName: "Alice"}, 10)
+	room.AddPlayer(&Player{ID: "bob", DisplayName: "Bob"})
+	room.AddPlayer(&Player{ID: "carol", DisplayName: "Carol"})
+
+	if _, _, err := room.EndSession(); !errors.Is(err, ErrNoRounds) {
+		t.Fatalf("EndSession() error = %v, want ErrNoRounds", err)
+	}
+
+	playRound(t, room, "bob")
+	playRound(t, room, "alice")
+	playRound(t, room, "bob")
+
+	summary := room.SessionSummary()
+	if summary.Round != 3 || len(summary.Rounds) != 3 || summary.Rounds[2].Round != 3 {
+		t.Fatalf("expected 3 rounds scored, got %+v", summary)
+	}
+	want := []Standing{
+		{Rank: 1, PlayerID: "bob", DisplayName: "Bob", Points: 2, Wins: 2},
+		{Rank: 2, PlayerID: "alice", DisplayName: "Alice", Points: 1, Wins: 1},
+		{Rank: 3, PlayerID: "carol", DisplayName: "Carol", Points: 0, Wins: 0},
+	}
+	if len(summary.Standings) != len(want) {
+		t.Fatalf("expected %d standings, got %+v", len(want), summary.Standings)
+	}
+	for i, standing := range want {
+		if summary.Standings[i] != standing {
+			t.Errorf("standing %d = %+v, want %+v", i, summary.Standings[i], standing)
+		}
+	}
+
+	// A round in progress when the session ends counts towards the next one
+	room.StartGame(winnerGame{winner: "carol"}, nil)
+
+	final, event, err := room.EndSession()
+	if err != nil {
+		t.Fatalf("EndSession() error = %v", err)
+	}
+	if event.Type != EventFinalStandings || len(final.Standings) != 3 {
+		t.Errorf("unexpected final standings event %s: %+v", event.Type, final)
+	}
+	var payload SessionSummary
+	json.Unmarshal(event.Payload, &payload)
+	if payload.Standings[0].PlayerID != "bob" {
+		t.Errorf("expected Bob to win the session, got %+v", payload.Standings)
+	}
+
+	room.SetStatus(RoomStatusFinished)
+	room.ScoreGame(DefaultScoringRules())
+	next := room.SessionSummary()
+	if next.Round != 1 || len(next.Rounds) != 1 || next.Standings[0].PlayerID != "carol" {
+		t.Errorf("expected a new session with Carol's round, got %+v", next)
+	}
+}
+
+func TestRoom_SessionTies(t *testing.T) {
+	t.Parallel()
+
+	room := NewRoom("ABC123", "stub", &Player{ID: "alice", DisplayName: "Alice"}, 10)
+	room.AddPlayer(&Player{ID: "bob", DisplayName: "Bob"})
+	room.AddPlayer(&Player{ID: "carol", DisplayName: "Carol"})
+	playRound(t, room, "alice")
+	playRound(t, room, "bob")
+
+	standings := room.SessionSummary().Standings
+	if standings[0].Rank != 1 || standings[1].Rank != 1 || standings[2].Rank != 3 {
+		t.Errorf("expected Alice and Bob tied first, got %+v", standings)
+	}
+}
+
+func TestRoom_SessionSnapshot(t *testing.T) {
+	t.Parallel()
+
+	room := NewRoom("ABC123", "stub", &Player{ID: "alice", DisplayName: "Alice"}, 10)
+	playRound(t, room, "alice")
+
+	snapshot, err := room.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	data, _ := json.Marshal(snapshot)
+	var decoded RoomSnapshot
+	json.Unmarshal(data, &decoded)
+
+	restored, err := RestoreRoom(decoded, nil)
+	if err != nil {
+		t.Fatalf("RestoreRoom() error = %v", err)
+	}
+	if summary := restored.SessionSummary(); len(summary.Rounds) != 1 || summary.Standings[0].Points != 1 {
+		t.Errorf("expected the scoreboard to survive the snapshot, got %+v", summary)
+	}
+
+	// Snapshots from before sessions restore an empty scoreboard
+	decoded.Scoreboard = nil
+	restored, err = RestoreRoom(decoded, nil)
+	if err != nil {
+		t.Fatalf("RestoreRoom() error = %v", err)
+	}
+	restored.StartGame(winnerGame{winner: "alice"}, nil)
+	if summary := restored.SessionSummary(); summary.Round != 1 || len(summary.Rounds) != 0 {
+		t.Errorf("expected an empty scoreboard, got %+v", summary)
+	}
+}
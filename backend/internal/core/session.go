@@ -0,0 +1,80 @@
+package core
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+)
+
+// ErrInvalidSessionToken is returned for tokens that don't belong to any
+// player in the room, including rotated and revoked ones.
+var ErrInvalidSessionToken = errors.New("invalid session token")
+
+// sessionEntry is a session token accepted for a player.
+type sessionEntry struct {
+	playerID string
+	token    string
+}
+
+// tokenKey returns the index key of a token. Tokens are looked up by hash so
+// map lookups reveal nothing about stored tokens; a match is then confirmed
+// with a constant-time comparison.
+func tokenKey(token string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(token))
+}
+
+// indexTokenLocked accepts the player's current session token.
+// Caller must hold r.mu.
+func (r *Room) indexTokenLocked(player *Player) {
+	if r.tokens == nil {
+		r.tokens = make(map[[sha256.Size]byte]sessionEntry)
+	}
+	if player.SessionToken != "" {
+		r.tokens[tokenKey(player.SessionToken)] = sessionEntry{playerID: player.ID, token: player.SessionToken}
+	}
+}
+
+// revokeTokensLocked stops accepting every token of a player, except keep.
+// Caller must hold r.mu.
+func (r *Room) revokeTokensLocked(playerID string, keep string) {
+	for key, entry := range r.tokens {
+		if entry.playerID == playerID && entry.token != keep {
+			delete(r.tokens, key)
+		}
+	}
+}
+
+// GetPlayerByToken finds a player by their session token.
+func (r *Room) GetPlayerByToken(token string) (*Player, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, exists := r.tokens[tokenKey(token)]
+	if !exists || subtle.ConstantTimeCompare([]byte(entry.token), []byte(token)) != 1 {
+		return nil, ErrInvalidSessionToken
+	}
+
+	player, exists := r.Players[entry.playerID]
+	if !exists {
+		return nil, ErrInvalidSessionToken
+	}
+	return player, nil
+}
+
+// RotatePlayerToken makes newToken the player's session token. The token the
+// player presented stays valid too, in case the new one never reaches the
+// client; every older token is revoked.
+func (r *Room) RotatePlayerToken(playerID string, presented string, newToken string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	player, exists := r.Players[playerID]
+	if !exists {
+		return errors.New("player not in room")
+	}
+
+	r.revokeTokensLocked(playerID, presented)
+	player.SessionToken = newToken
+	r.indexTokenLocked(player)
+	return nil
+}
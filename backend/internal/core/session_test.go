@@ -0,0 +1,98 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRoom_RotatePlayerToken(t *testing.T) {
+	t.Parallel()
+
+	host := &Player{ID: "host", DisplayName: "Host", SessionToken: "token-host"}
+	room := NewRoom("ABC123", "werewolf", host, 10)
+	room.AddPlayer(&Player{ID: "player1", DisplayName: "Player1", SessionToken: "token-1"})
+
+	if err := room.RotatePlayerToken("player1", "token-1", "token-2"); err != nil {
+		t.Fatalf("RotatePlayerToken() error = %v", err)
+	}
+
+	// Both the presented and the new token work until the next rotation
+	for _, token := range []string{"token-1", "token-2"} {
+		if player, err := room.GetPlayerByToken(token); err != nil || player.ID != "player1" {
+			t.Errorf("expected %s to authenticate player1, got %v", token, err)
+		}
+	}
+
+	if err := room.RotatePlayerToken("player1", "token-2", "token-3"); err != nil {
+		t.Fatalf("RotatePlayerToken() error = %v", err)
+	}
+	if _, err := room.GetPlayerByToken("token-1"); !errors.Is(err, ErrInvalidSessionToken) {
+		t.Errorf("expected token-1 revoked, got %v", err)
+	}
+	if player, _ := room.GetPlayer("player1"); player.SessionToken != "token-3" {
+		t.Errorf("expected current token 'token-3', got '%s'", player.SessionToken)
+	}
+
+	// Other players are unaffected
+	if _, err := room.GetPlayerByToken("token-host"); err != nil {
+		t.Errorf("expected host token to stay valid, got %v", err)
+	}
+
+	if err := room.RotatePlayerToken("missing", "x", "y"); err == nil {
+		t.Error("expected error for unknown player")
+	}
+}
+
+func TestRoom_KickPlayer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		status   RoomStatus
+		playerID string
+		wantErr  error
+	}{
+		{name: "kick from lobby", status: RoomStatusWaiting, playerID: "player1"},
+		{name: "refuse during game", status: RoomStatusPlaying, playerID: "player1", wantErr: ErrGameInProgress},
+		{name: "refuse host", status: RoomStatusWaiting, playerID: "host"},
+		{name: "refuse unknown player", status: RoomStatusWaiting, playerID: "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			host := &Player{ID: "host", DisplayName: "Host", SessionToken: "token-host"}
+			room := NewRoom("ABC123", "werewolf", host, 10)
+			room.AddPlayer(&Player{ID: "player1", DisplayName: "Player1", SessionToken: "token-1"})
+			room.Status = tt.status
+
+			err := room.KickPlayer(tt.playerID)
+
+			if tt.playerID != "player1" {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if _, err := room.GetPlayerByToken("token-1"); err != nil {
+					t.Errorf("expected token kept when kick refused, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := room.GetPlayer("player1"); err == nil {
+				t.Error("expected player removed")
+			}
+			if _, err := room.GetPlayerByToken("token-1"); !errors.Is(err, ErrInvalidSessionToken) {
+				t.Errorf("expected token revoked, got %v", err)
+			}
+		})
+	}
+}
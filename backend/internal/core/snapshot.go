@@ -0,0 +1,226 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SnapshotVersion is the current RoomSnapshot format version.
+const SnapshotVersion = 1
+
+// ErrGameNotSnapshottable is returned when a room's game cannot be saved.
+var ErrGameNotSnapshottable = errors.New("game does not support snapshots")
+
+// Snapshotter is implemented by games whose state can be saved and restored,
+// so rooms survive export/import and server restarts.
+type Snapshotter interface {
+	// Snapshot serializes the game's full internal state.
+	Snapshot() (json.RawMessage, error)
+
+	// Restore loads state produced by Snapshot into a fresh game.
+	// players are the room's players, for games that keep references to them.
+	Restore(data json.RawMessage, players []*Player) error
+}
+
+// EventRecord is a game event including its visibility, which is never sent
+// to players. Used for snapshots and operator tooling.
+type EventRecord struct {
+	GameEvent
+	Visibility EventVisibility `json:"visibility"`
+}
+
+// NewEventRecord wraps an event with its visibility.
+func NewEventRecord(event GameEvent) EventRecord {
+	return EventRecord{GameEvent: event, Visibility: event.Visibility}
+}
+
+// Event returns the wrapped event with its visibility restored.
+func (e EventRecord) Event() GameEvent {
+	event := e.GameEvent
+	event.Visibility = e.Visibility
+	return event
+}
+
+// PlayerSnapshot is the saved form of a player, including the session token
+// so players can reconnect to a restored room.
+type PlayerSnapshot struct {
+	ID           string       `json:"id"`
+	SessionToken string       `json:"sessionToken"`
+	DisplayName  string       `json:"displayName"`
+	JoinedAt     time.Time    `json:"joinedAt"`
+	LastSeenAt   time.Time    `json:"lastSeenAt"`
+	ProfileID    string       `json:"profileId,omitempty"`
+	AvatarColor  string       `json:"avatarColor,omitempty"`
+	Bot          *BotSettings `json:"bot,omitempty"`
+}
+
+// RoomSnapshot is a serializable copy of a room and its game.
+// It contains session tokens and hidden game state, so treat it as secret.
+type RoomSnapshot struct {
+	Version        int              `json:"version"`
+	ID             string           `json:"id"`
+	CreatedAt      time.Time        `json:"createdAt"`
+	LastActivityAt time.Time        `json:"lastActivityAt"`
+	Status         RoomStatus       `json:"status"`
+	GameType       string           `json:"gameType"`
+	MaxPlayers     int              `json:"maxPlayers"`
+	Public         bool             `json:"public,omitempty"`
+	Title          string           `json:"title,omitempty"`
+	HostID         string           `json:"hostId"`
+	PasswordHash   string           `json:"passwordHash,omitempty"` // bcrypt hash of the join password
+	Players        []PlayerSnapshot `json:"players"`
+	EventLog       []EventRecord    `json:"eventLog"`
+	Game           json.RawMessage  `json:"game,omitempty"` // Game state, if a game was started
+	Scoreboard     *Scoreboard      `json:"scoreboard,omitempty"`
+	Chat           []EventRecord    `json:"chat,omitempty"`
+	Muted          []string         `json:"muted,omitempty"` // PlayerIDs muted in chat
+}
+
+// Snapshot captures the room's full state.
+func (r *Room) Snapshot() (RoomSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := RoomSnapshot{
+		Version:        SnapshotVersion,
+		ID:             r.ID,
+		CreatedAt:      r.CreatedAt,
+		LastActivityAt: r.LastActivityAt,
+		Status:         r.Status,
+		GameType:       r.GameType,
+		MaxPlayers:     r.MaxPlayers,
+		Public:         r.Public,
+		Title:          r.Title,
+		HostID:         r.HostID,
+		PasswordHash:   string(r.passwordHash),
+		Players:        make([]PlayerSnapshot, 0, len(r.Players)),
+		EventLog:       make([]EventRecord, len(r.EventLog)),
+		Chat:           make([]EventRecord, len(r.chat)),
+		Muted:          r.mutedLocked(),
+	}
+
+	scoreboard := r.scoreboard.clone()
+	snapshot.Scoreboard = &scoreboard
+
+	for _, player := range r.Players {
+		snapshot.Players = append(snapshot.Players, PlayerSnapshot{
+			ID:           player.ID,
+			SessionToken: player.SessionToken,
+			DisplayName:  player.DisplayName,
+			JoinedAt:     player.JoinedAt,
+			LastSeenAt:   player.GetLastSeenAt(),
+			ProfileID:    player.ProfileID,
+			AvatarColor:  player.AvatarColor,
+			Bot:          player.Bot,
+		})
+	}
+
+	for i, event := range r.EventLog {
+		snapshot.EventLog[i] = NewEventRecord(event)
+	}
+	for i, event := range r.chat {
+		snapshot.Chat[i] = NewEventRecord(event)
+	}
+
+	if r.Game != nil {
+		snapshotter, ok := r.Game.(Snapshotter)
+		if !ok {
+			return RoomSnapshot{}, fmt.Errorf("room %s: %w", r.ID, ErrGameNotSnapshottable)
+		}
+		data, err := snapshotter.Snapshot()
+		if err != nil {
+			return RoomSnapshot{}, fmt.Errorf("room %s: failed to snapshot game: %w", r.ID, err)
+		}
+		snapshot.Game = data
+	}
+
+	return snapshot, nil
+}
+
+// RestoreRoom rebuilds a room from a snapshot. newGame creates an empty game
+// of the snapshot's type to restore the game state into.
+// Restored players start disconnected until they reconnect.
+func RestoreRoom(snapshot RoomSnapshot, newGame func(gameType string) (Game, error)) (*Room, error) {
+	if snapshot.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snapshot.Version)
+	}
+	if snapshot.ID == "" {
+		return nil, errors.New("snapshot has no room code")
+	}
+
+	room := &Room{
+		ID:             snapshot.ID,
+		CreatedAt:      snapshot.CreatedAt,
+		LastActivityAt: snapshot.LastActivityAt,
+		Status:         snapshot.Status,
+		GameType:       snapshot.GameType,
+		MaxPlayers:     snapshot.MaxPlayers,
+		Public:         snapshot.Public,
+		Title:          snapshot.Title,
+		HostID:         snapshot.HostID,
+		Players:        make(map[string]*Player, len(snapshot.Players)),
+		EventLog:       make([]GameEvent, len(snapshot.EventLog)),
+		chat:           make([]GameEvent, len(snapshot.Chat)),
+		muted:          make(map[string]bool, len(snapshot.Muted)),
+	}
+	if snapshot.PasswordHash != "" {
+		room.passwordHash = []byte(snapshot.PasswordHash)
+	}
+
+	// Snapshots from before sessions start an empty one
+	room.scoreboard = newScoreboard(snapshot.CreatedAt)
+	if saved := snapshot.Scoreboard; saved != nil {
+		room.scoreboard = saved.clone()
+	}
+
+	players := make([]*Player, 0, len(snapshot.Players))
+	for _, saved := range snapshot.Players {
+		player := &Player{
+			ID:           saved.ID,
+			SessionToken: saved.SessionToken,
+			DisplayName:  saved.DisplayName,
+			Connected:    saved.Bot != nil, // Bots need no connection
+			JoinedAt:     saved.JoinedAt,
+			LastSeenAt:   saved.LastSeenAt,
+			ProfileID:    saved.ProfileID,
+			AvatarColor:  saved.AvatarColor,
+			Bot:          saved.Bot,
+		}
+		room.Players[player.ID] = player
+		room.indexTokenLocked(player)
+		players = append(players, player)
+	}
+
+	if _, exists := room.Players[room.HostID]; !exists {
+		return nil, fmt.Errorf("snapshot host %s is not a player", room.HostID)
+	}
+
+	for i, record := range snapshot.EventLog {
+		room.EventLog[i] = record.Event()
+	}
+	for i, record := range snapshot.Chat {
+		room.chat[i] = record.Event()
+	}
+	for _, id := range snapshot.Muted {
+		room.muted[id] = true
+	}
+
+	if len(snapshot.Game) > 0 {
+		game, err := newGame(snapshot.GameType)
+		if err != nil {
+			return nil, err
+		}
+		snapshotter, ok := game.(Snapshotter)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", snapshot.GameType, ErrGameNotSnapshottable)
+		}
+		if err := snapshotter.Restore(snapshot.Game, players); err != nil {
+			return nil, fmt.Errorf("failed to restore game: %w", err)
+		}
+		room.Game = game
+	}
+
+	return room, nil
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// stubGame is a minimal Game without snapshot support.
+type stubGame struct{}
+
+func (stubGame) Initialize(GameConfig, []*Player) ([]GameEvent, error) { return nil, nil }
+func (stubGame) ValidateAction(string, Action) error                   { return nil }
+func (stubGame) ProcessAction(string, Action) ([]GameEvent, error)     { return nil, nil }
+func (stubGame) GetPlayerState(string) PlayerState                     { return nil }
+func (stubGame) GetPublicState() PublicState                           { return nil }
+func (stubGame) GetPhase() GamePhase                                   { return GamePhase{} }
+func (stubGame) IsFinished() bool                                      { return false }
+func (stubGame) GetResults() GameResults                               { return GameResults{} }
+func (stubGame) CheckPhaseTimeout() ([]GameEvent, error)               { return nil, nil }
+
+// snapshotGame records the state it was restored with.
+type snapshotGame struct {
+	stubGame
+	state   string
+	players []*Player
+}
+
+func (g *snapshotGame) Snapshot() (json.RawMessage, error) {
+	return json.Marshal(g.state)
+}
+
+func (g *snapshotGame) Restore(data json.RawMessage, players []*Player) error {
+	g.players = players
+	return json.Unmarshal(data, &g.state)
+}
+
+func TestRoom_SnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	host := &Player{ID: "host-123", DisplayName: "Alice", SessionToken: "token-123"}
+	room := NewRoom("ABC123", "stub", host, 10)
+	room.AddPlayer(&Player{ID: "p2", DisplayName: "Bob", SessionToken: "token-456", ProfileID: "profile-1", AvatarColor: "#3b82f6"})
+	room.Publish("Friday night")
+	room.Status = RoomStatusPlaying
+	room.Game = &snapshotGame{state: "night"}
+
+	secret, _ := NewPrivateEvent("role_assigned", "system", map[string]string{"role": "seer"}, []string{"p2"})
+	room.AppendEvent(secret)
+
+	snapshot, err := room.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	// Round-trip through JSON as export/import and the file store do
+	data, _ := json.Marshal(snapshot)
+	var decoded RoomSnapshot
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+
+	restored, err := RestoreRoom(decoded, func(gameType string) (Game, error) {
+		return &snapshotGame{}, nil
+	})
+	if err != nil {
+		t.Fatalf("RestoreRoom() error = %v", err)
+	}
+
+	if restored.ID != "ABC123" || restored.HostID != "host-123" || restored.Status != RoomStatusPlaying ||
+		!restored.Public || restored.Title != "Friday night" {
+		t.Errorf("room fields not restored: %+v", restored.GetState())
+	}
+
+	player, err := restored.GetPlayerByToken("token-456")
+	if err != nil {
+		t.Fatal("expected session tokens to survive the snapshot")
+	}
+	if player.IsConnected() {
+		t.Error("restored players should start disconnected")
+	}
+	if player.ProfileID != "profile-1" || player.AvatarColor != "#3b82f6" {
+		t.Errorf("expected profile link to survive the snapshot, got %q %q", player.ProfileID, player.AvatarColor)
+	}
+
+	events := restored.GetEventsForPlayer("host-123")
+	if len(events) != 0 {
+		t.Errorf("private event visibility was lost: host sees %d events", len(events))
+	}
+	if len(restored.GetEventsForPlayer("p2")) != 1 {
+		t.Error("expected p2 to see their private event")
+	}
+
+	game := restored.Game.(*snapshotGame)
+	if game.state != "night" || len(game.players) != 2 {
+		t.Errorf("game not restored: state %q with %d players", game.state, len(game.players))
+	}
+}
+
+func TestRoom_SnapshotErrors(t *testing.T) {
+	t.Parallel()
+
+	room := NewRoom("ABC123", "stub", &Player{ID: "host-123"}, 10)
+	room.Game = stubGame{}
+	if _, err := room.Snapshot(); !errors.Is(err, ErrGameNotSnapshottable) {
+		t.Errorf("expected ErrGameNotSnapshottable, got %v", err)
+	}
+
+	newGame := func(string) (Game, error) { return &snapshotGame{}, nil }
+
+	tests := []struct {
+		name     string
+		snapshot RoomSnapshot
+	}{
+		{name: "wrong version", snapshot: RoomSnapshot{Version: 99, ID: "ABC123"}},
+		{name: "missing code", snapshot: RoomSnapshot{Version: SnapshotVersion}},
+		{name: "host not a player", snapshot: RoomSnapshot{Version: SnapshotVersion, ID: "ABC123", HostID: "ghost"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := RestoreRoom(tt.snapshot, newGame); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
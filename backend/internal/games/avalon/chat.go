@@ -0,0 +1,33 @@
+package avalon
+
+import (
+	"sort"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// ChatChannelEvil is the evil team's private chat channel in remote play.
+const ChatChannelEvil = "evil"
+
+// ChatChannels gives the evil team a private channel when playing remotely.
+// Oberon is left out, since the rest of evil doesn't know him.
+func (g *Game) ChatChannels() map[string][]string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if g.config == nil || !g.config.RemotePlay {
+		return nil
+	}
+
+	evil := make([]string, 0)
+	for playerID, role := range g.roles {
+		if isEvilRole(role) && role != RoleOberon {
+			evil = append(evil, playerID)
+		}
+	}
+	sort.Strings(evil)
+
+	return map[string][]string{ChatChannelEvil: evil}
+}
+
+var _ core.ChatTeams = (*Game)(nil)
@@ -0,0 +1,62 @@
+package avalon
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+func TestGame_ChatChannels(t *testing.T) {
+	t.Parallel()
+
+	players := []*core.Player{
+		{ID: "p1", DisplayName: "Player1"},
+		{ID: "p2", DisplayName: "Player2"},
+		{ID: "p3", DisplayName: "Player3"},
+		{ID: "p4", DisplayName: "Player4"},
+		{ID: "p5", DisplayName: "Player5"},
+		{ID: "p6", DisplayName: "Player6"},
+		{ID: "p7", DisplayName: "Player7"},
+	}
+	roles := []Role{RoleMerlin, RolePercival, RoleLoyalServant, RoleLoyalServant, RoleAssassin, RoleMorgana, RoleOberon}
+
+	tests := []struct {
+		name       string
+		remotePlay bool
+		wantEvil   bool
+	}{
+		{name: "remote play has an evil channel", remotePlay: true, wantEvil: true},
+		{name: "table play has no channels", remotePlay: false, wantEvil: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			g := NewGame().(*Game)
+			config := &Config{Roles: append([]Role(nil), roles...), RemotePlay: tt.remotePlay}
+			if _, err := g.Initialize(config, players); err != nil {
+				t.Fatalf("Initialize failed: %v", err)
+			}
+
+			members, ok := g.ChatChannels()[ChatChannelEvil]
+			if ok != tt.wantEvil {
+				t.Fatalf("expected evil channel %v, got %v", tt.wantEvil, ok)
+			}
+			if !tt.wantEvil {
+				return
+			}
+
+			var want []string
+			for _, player := range players {
+				if role := g.roles[player.ID]; role == RoleAssassin || role == RoleMorgana {
+					want = append(want, player.ID)
+				}
+			}
+			if !reflect.DeepEqual(members, want) {
+				t.Errorf("expected evil channel %v (without Oberon), got %v", want, members)
+			}
+		})
+	}
+}
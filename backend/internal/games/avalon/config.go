@@ -10,6 +10,10 @@ import (
 // Config represents the configuration for an Avalon game
 type Config struct {
 	Roles []Role `json:"roles"`
+
+	// RemotePlay is for groups not sharing a table: evil players who know
+	// each other get a private chat channel instead of the eyes-closed reveal
+	RemotePlay bool `json:"remotePlay,omitempty"`
 }
 
 // GameType returns "avalon"
@@ -87,6 +91,12 @@ func isEvilRole(role Role) bool {
 		role == RoleOberon || role == RoleMinionOfMordred
 }
 
+// TeamSizes returns how many good and evil players a valid game of
+// playerCount has
+func TeamSizes(playerCount int) (good int, evil int) {
+	return getExpectedTeamSizes(playerCount)
+}
+
 // getExpectedTeamSizes returns the correct team distribution for player count
 func getExpectedTeamSizes(playerCount int) (good int, evil int) {
 	switch playerCount {
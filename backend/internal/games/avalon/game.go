@@ -42,6 +42,9 @@ type Game struct {
 	// Acknowledgments
 	acknowledged map[string]bool // playerID -> acknowledged
 
+	// Who players point at during team votes (not snapshotted)
+	accusations core.Accusations
+
 	// Results
 	winningTeam Team
 	winReason   string
@@ -768,9 +771,51 @@ func (g *Game) GetPublicState() core.PublicState {
 		AcknowledgementsCount: g.countAcknowledgments(),
 		GoodQuestWins:         goodWins,
 		EvilQuestWins:         evilWins,
+		Accusations:           g.accusationsLocked(),
 	}
 }
 
+// ObserveReaction records players pointing at suspects while a team is
+// voted on. Each proposal starts over.
+func (g *Game) ObserveReaction(reaction core.Reaction) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if reaction.Kind != core.ReactionPoint || g.phase != PhaseTeamVoting {
+		return
+	}
+	if _, ok := g.roles[reaction.PlayerID]; !ok {
+		return
+	}
+	if _, ok := g.roles[reaction.TargetID]; !ok {
+		return
+	}
+	g.accusations.Point(g.voteStretch(), reaction.PlayerID, reaction.TargetID)
+}
+
+// Accusations summarizes who players point at while the current team is
+// voted on.
+func (g *Game) Accusations() []core.Accusation {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return g.accusationsLocked()
+}
+
+// accusationsLocked returns the accusations about the current proposal.
+// Caller must hold g.mu.
+func (g *Game) accusationsLocked() []core.Accusation {
+	if g.phase != PhaseTeamVoting {
+		return nil
+	}
+	return g.accusations.Summary(g.voteStretch())
+}
+
+// voteStretch identifies the current team proposal.
+func (g *Game) voteStretch() string {
+	return fmt.Sprintf("quest %d, proposal %d", g.questNumber, g.rejectionCount+1)
+}
+
 func (g *Game) countAcknowledgments() int {
 	count := 0
 	for _, ack := range g.acknowledged {
@@ -844,6 +889,25 @@ func (g *Game) GetResults() core.GameResults {
 	}
 }
 
+// PlayerOutcomes reports each player's role and team, and marks the
+// assassin's target as eliminated.
+func (g *Game) PlayerOutcomes() []core.PlayerOutcome {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	outcomes := make([]core.PlayerOutcome, 0, len(g.players))
+	for _, player := range g.players {
+		outcomes = append(outcomes, core.PlayerOutcome{
+			PlayerID:   player.ID,
+			Role:       string(g.roles[player.ID]),
+			Team:       string(g.teams[player.ID]),
+			Won:        g.winningTeam != "" && g.teams[player.ID] == g.winningTeam,
+			Eliminated: g.assassinTarget == player.ID,
+		})
+	}
+	return outcomes
+}
+
 func (g *Game) CheckPhaseTimeout() ([]core.GameEvent, error) {
 	// Avalon has no phase timers - all phases are player-driven
 	return nil, nil
@@ -1055,3 +1055,104 @@ func hasSubstring(s, substr string) bool {
 	return false
 }
 
+
+func TestGame_PlayerOutcomes(t *testing.T) {
+	t.Parallel()
+
+	game := NewGame()
+	config := &Config{
+		Roles: []Role{RoleMerlin, RoleAssassin, RoleLoyalServant, RoleLoyalServant, RoleMinionOfMordred},
+	}
+	players := []*core.Player{
+		{ID: "p1"}, {ID: "p2"}, {ID: "p3"}, {ID: "p4"}, {ID: "p5"},
+	}
+	if _, err := game.Initialize(config, players); err != nil {
+		t.Fatalf("failed to initialize: %v", err)
+	}
+
+	g := game.(*Game)
+	var assassinID, merlinID string
+	for pid, role := range g.roles {
+		switch role {
+		case RoleAssassin:
+			assassinID = pid
+		case RoleMerlin:
+			merlinID = pid
+		}
+	}
+
+	g.phase = PhaseAssassination
+	payload, _ := json.Marshal(map[string]interface{}{"target_id": merlinID})
+	if _, err := game.ProcessAction(assassinID, core.Action{Type: "assassinate", Payload: payload}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	outcomes := g.PlayerOutcomes()
+	if len(outcomes) != 5 {
+		t.Fatalf("expected 5 outcomes, got %d", len(outcomes))
+	}
+	for _, outcome := range outcomes {
+		role := g.roles[outcome.PlayerID]
+		if outcome.Role != string(role) || outcome.Team != string(g.teams[outcome.PlayerID]) {
+			t.Errorf("outcome %+v does not match role %s", outcome, role)
+		}
+		if outcome.Won != (g.teams[outcome.PlayerID] == TeamEvil) {
+			t.Errorf("expected only evil to win, got %+v", outcome)
+		}
+		if outcome.Eliminated != (outcome.PlayerID == merlinID) {
+			t.Errorf("expected only Merlin eliminated, got %+v", outcome)
+		}
+	}
+}
+
+func TestGame_ObserveReaction(t *testing.T) {
+	t.Parallel()
+
+	game := NewGame().(*Game)
+	config := &Config{
+		Roles: []Role{RoleMerlin, RoleAssassin, RoleLoyalServant, RoleLoyalServant, RoleMinionOfMordred},
+	}
+	players := []*core.Player{
+		{ID: "p1", DisplayName: "Player1"},
+		{ID: "p2", DisplayName: "Player2"},
+		{ID: "p3", DisplayName: "Player3"},
+		{ID: "p4", DisplayName: "Player4"},
+		{ID: "p5", DisplayName: "Player5"},
+	}
+	if _, err := game.Initialize(config, players); err != nil {
+		t.Fatalf("failed to initialize: %v", err)
+	}
+
+	point := func(from, to string) {
+		game.ObserveReaction(core.Reaction{Kind: core.ReactionPoint, PlayerID: from, TargetID: to})
+	}
+	accusations := func() []core.Accusation {
+		return game.GetPublicState().(PublicState).Accusations
+	}
+
+	game.phase = PhaseTeamVoting
+	point("p1", "p3")
+	point("p2", "p3")
+	point("p4", "nobody")
+	if got := accusations(); len(got) != 1 || got[0].SuspectID != "p3" || len(got[0].AccusedBy) != 2 {
+		t.Fatalf("expected p3 accused twice, got %+v", got)
+	}
+
+	// Pointing again withdraws the accusation
+	point("p2", "p3")
+	if got := accusations(); len(got) != 1 || len(got[0].AccusedBy) != 1 {
+		t.Fatalf("expected one accuser left, got %+v", got)
+	}
+
+	// A new proposal starts over
+	game.rejectionCount++
+	if got := accusations(); got != nil {
+		t.Errorf("expected no accusations for the new proposal, got %+v", got)
+	}
+
+	game.phase = PhaseQuestExec
+	point("p1", "p2")
+	if got := accusations(); got != nil {
+		t.Errorf("expected no accusations outside team voting, got %+v", got)
+	}
+}
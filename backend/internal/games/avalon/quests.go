@@ -49,3 +49,9 @@ func getFailsRequired(playerCount int, questNumber int) int {
 func requiresTwoFails(playerCount int, questNumber int) bool {
 	return getFailsRequired(playerCount, questNumber) == 2
 }
+
+// FailsRequired returns the number of fail cards that fail a quest, for
+// players reasoning about quests that haven't been played yet
+func FailsRequired(playerCount int, questNumber int) int {
+	return getFailsRequired(playerCount, questNumber)
+}
@@ -0,0 +1,121 @@
+package avalon
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// snapshot is the saved form of an Avalon game.
+type snapshot struct {
+	PlayerIDs      []string             `json:"playerIds"` // Seating order
+	Config         *Config              `json:"config"`
+	Phase          GamePhase            `json:"phase"`
+	Roles          map[string]Role      `json:"roles"`
+	Teams          map[string]Team      `json:"teams"`
+	Knowledge      map[string][]string  `json:"knowledge"`
+	QuestNumber    int                  `json:"questNumber"`
+	QuestResults   []QuestResult        `json:"questResults"`
+	CurrentLeader  string               `json:"currentLeader"`
+	LeaderIndex    int                  `json:"leaderIndex"`
+	RejectionCount int                  `json:"rejectionCount"`
+	ProposedTeam   []string             `json:"proposedTeam"`
+	TeamVotes      map[string]Vote      `json:"teamVotes"`
+	QuestCards     map[string]QuestCard `json:"questCards"`
+	AssassinTarget string               `json:"assassinTarget"`
+	Acknowledged   map[string]bool      `json:"acknowledged"`
+	WinningTeam    Team                 `json:"winningTeam"`
+	WinReason      string               `json:"winReason"`
+}
+
+// Snapshot implements core.Snapshotter.
+func (g *Game) Snapshot() (json.RawMessage, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	playerIDs := make([]string, len(g.players))
+	for i, player := range g.players {
+		playerIDs[i] = player.ID
+	}
+
+	return json.Marshal(snapshot{
+		PlayerIDs:      playerIDs,
+		Config:         g.config,
+		Phase:          g.phase,
+		Roles:          g.roles,
+		Teams:          g.teams,
+		Knowledge:      g.knowledge,
+		QuestNumber:    g.questNumber,
+		QuestResults:   g.questResults,
+		CurrentLeader:  g.currentLeader,
+		LeaderIndex:    g.leaderIndex,
+		RejectionCount: g.rejectionCount,
+		ProposedTeam:   g.proposedTeam,
+		TeamVotes:      g.teamVotes,
+		QuestCards:     g.questCards,
+		AssassinTarget: g.assassinTarget,
+		Acknowledged:   g.acknowledged,
+		WinningTeam:    g.winningTeam,
+		WinReason:      g.winReason,
+	})
+}
+
+// Restore implements core.Snapshotter.
+func (g *Game) Restore(data json.RawMessage, players []*core.Player) error {
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	byID := make(map[string]*core.Player, len(players))
+	for _, player := range players {
+		byID[player.ID] = player
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Seating order matters: leadership rotates through it
+	g.players = make([]*core.Player, len(s.PlayerIDs))
+	for i, playerID := range s.PlayerIDs {
+		player, exists := byID[playerID]
+		if !exists {
+			return fmt.Errorf("player %s is not in the room", playerID)
+		}
+		g.players[i] = player
+	}
+
+	g.config = s.Config
+	g.phase = s.Phase
+	g.roles = orEmpty(s.Roles)
+	g.teams = orEmpty(s.Teams)
+	g.knowledge = orEmpty(s.Knowledge)
+	g.questNumber = s.QuestNumber
+	g.questResults = s.QuestResults
+	if g.questResults == nil {
+		g.questResults = []QuestResult{}
+	}
+	g.currentLeader = s.CurrentLeader
+	g.leaderIndex = s.LeaderIndex
+	g.rejectionCount = s.RejectionCount
+	g.proposedTeam = s.ProposedTeam
+	g.teamVotes = orEmpty(s.TeamVotes)
+	g.questCards = orEmpty(s.QuestCards)
+	g.assassinTarget = s.AssassinTarget
+	g.acknowledged = orEmpty(s.Acknowledged)
+	g.winningTeam = s.WinningTeam
+	g.winReason = s.WinReason
+
+	return nil
+}
+
+// orEmpty returns m, or an empty map if m is nil.
+func orEmpty[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return make(map[K]V)
+	}
+	return m
+}
+
+var _ core.Snapshotter = (*Game)(nil)
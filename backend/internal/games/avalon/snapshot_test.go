@@ -0,0 +1,82 @@
+package avalon
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+func TestGame_SnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	game := NewGame()
+	config := &Config{
+		Roles: []Role{RoleMerlin, RoleAssassin, RoleLoyalServant, RoleLoyalServant, RoleMinionOfMordred},
+	}
+	players := []*core.Player{
+		{ID: "p1", DisplayName: "Player1"},
+		{ID: "p2", DisplayName: "Player2"},
+		{ID: "p3", DisplayName: "Player3"},
+		{ID: "p4", DisplayName: "Player4"},
+		{ID: "p5", DisplayName: "Player5"},
+	}
+
+	if _, err := game.Initialize(config, players); err != nil {
+		t.Fatalf("failed to initialize game: %v", err)
+	}
+	for _, player := range players {
+		if _, err := game.ProcessAction(player.ID, core.Action{Type: "acknowledge_role"}); err != nil {
+			t.Fatalf("failed to acknowledge: %v", err)
+		}
+	}
+
+	data, err := game.(*Game).Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	// Restore against fresh player objects, as after a restart
+	restoredPlayers := make([]*core.Player, len(players))
+	for i, player := range players {
+		restoredPlayers[i] = &core.Player{ID: player.ID, DisplayName: player.DisplayName}
+	}
+	restored := NewGame().(*Game)
+	if err := restored.Restore(data, restoredPlayers); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	if restored.phase != PhaseTeamBuilding {
+		t.Errorf("expected team_building phase, got %s", restored.phase)
+	}
+	if restored.players[0] != restoredPlayers[0] {
+		t.Error("expected restored game to reference the room's players")
+	}
+	for _, player := range players {
+		want, _ := json.Marshal(game.GetPlayerState(player.ID))
+		got, _ := json.Marshal(restored.GetPlayerState(player.ID))
+		if string(want) != string(got) {
+			t.Errorf("player %s state differs after restore:\nwant %s\ngot  %s", player.ID, want, got)
+		}
+	}
+	if !reflect.DeepEqual(game.GetPublicState(), restored.GetPublicState()) {
+		t.Error("public state differs after restore")
+	}
+
+	// The restored game keeps playing
+	leader := restored.currentLeader
+	payload, _ := json.Marshal(map[string][]string{"team_members": {"p1", "p2"}})
+	if _, err := restored.ProcessAction(leader, core.Action{Type: "propose_team", Payload: payload}); err != nil {
+		t.Errorf("restored game rejected team proposal: %v", err)
+	}
+}
+
+func TestGame_RestoreUnknownPlayer(t *testing.T) {
+	t.Parallel()
+
+	data, _ := json.Marshal(snapshot{PlayerIDs: []string{"ghost"}})
+	if err := NewGame().(*Game).Restore(data, nil); err == nil {
+		t.Error("expected error restoring a player that is not in the room")
+	}
+}
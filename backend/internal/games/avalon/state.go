@@ -1,5 +1,7 @@
 package avalon
 
+import "github.com/KonradHerman/roundtable/internal/core"
+
 // Role types
 type Role string
 
@@ -101,6 +103,7 @@ type PublicState struct {
 	AcknowledgementsCount int            `json:"acknowledgements_count"`
 	GoodQuestWins         int            `json:"good_quest_wins"`       // good team quest wins
 	EvilQuestWins         int            `json:"evil_quest_wins"`       // evil team quest wins
+	Accusations           []core.Accusation `json:"accusations,omitempty"` // who players point at, during team voting
 }
 
 // Event payloads
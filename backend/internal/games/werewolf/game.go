@@ -37,6 +37,7 @@ type Game struct {
 	phaseEndsAt          time.Time
 	timerActive          bool              // Whether day phase timer is active
 	nightActionsComplete map[RoleType]bool // Track which roles have acted
+	accusations          core.Accusations  // Who players point at during the day (not snapshotted)
 }
 
 // NewGame creates a new werewolf game instance.
@@ -152,6 +153,9 @@ func (g *Game) ValidateAction(playerID string, action core.Action) error {
 		if g.phase != PhaseDay {
 			return errors.New("can only advance to results from day phase")
 		}
+		if playerID != g.hostID {
+			return errors.New("only the host can advance to results")
+		}
 		return nil
 
 	case "toggle_timer":
@@ -318,6 +322,15 @@ func (g *Game) ProcessAction(playerID string, action core.Action) ([]core.GameEv
 		})
 		events = append(events, rolesEvent)
 
+		// The votes cast so far settle the game. A day ended before anyone
+		// voted only reveals the cards, so it is neither scored nor recorded
+		if len(g.votes) > 0 {
+			resultsEvent, _ := core.NewPublicEvent(core.EventGameFinished, "system", core.GameFinishedPayload{
+				Results: g.calculateResults(),
+			})
+			events = append(events, resultsEvent)
+		}
+
 	case "toggle_timer":
 		var timerPayload struct {
 			Enable   bool `json:"enable"`
@@ -586,9 +599,29 @@ func (g *Game) GetPublicState() core.PublicState {
 		VotesSubmitted:        len(g.votes),
 		AcknowledgementsCount: len(g.roleAcknowledgements),
 		TimerActive:           g.timerActive,
+		Accusations:           g.Accusations(),
 	}
 }
 
+// ObserveReaction records players pointing at suspects during the day.
+func (g *Game) ObserveReaction(reaction core.Reaction) {
+	if reaction.Kind != core.ReactionPoint || g.phase != PhaseDay {
+		return
+	}
+	if g.players[reaction.PlayerID] == nil || g.players[reaction.TargetID] == nil {
+		return
+	}
+	g.accusations.Point(string(PhaseDay), reaction.PlayerID, reaction.TargetID)
+}
+
+// Accusations summarizes who players point at, during the day.
+func (g *Game) Accusations() []core.Accusation {
+	if g.phase != PhaseDay {
+		return nil
+	}
+	return g.accusations.Summary(string(PhaseDay))
+}
+
 // GetPhase returns the current game phase.
 func (g *Game) GetPhase() core.GamePhase {
 	return core.GamePhase{
@@ -608,6 +641,53 @@ func (g *Game) GetResults() core.GameResults {
 	return g.calculateResults()
 }
 
+// Teams reported in player outcomes.
+const (
+	TeamVillage  = "village"
+	TeamWerewolf = "werewolf"
+	TeamTanner   = "tanner"
+)
+
+// Team returns the team a player with this role plays for.
+func (r RoleType) Team() string {
+	switch {
+	case r.IsWerewolfTeam():
+		return TeamWerewolf
+	case r == RoleTanner:
+		return TeamTanner
+	default:
+		return TeamVillage
+	}
+}
+
+// PlayerOutcomes reports each player's final role and whether they won or
+// were eliminated by the vote.
+func (g *Game) PlayerOutcomes() []core.PlayerOutcome {
+	results := g.calculateResults()
+
+	winners := make(map[string]bool, len(results.Winners))
+	for _, id := range results.Winners {
+		winners[id] = true
+	}
+	eliminatedIDs, _ := results.FinalState["eliminated"].([]string)
+	eliminated := make(map[string]bool, len(eliminatedIDs))
+	for _, id := range eliminatedIDs {
+		eliminated[id] = true
+	}
+
+	outcomes := make([]core.PlayerOutcome, 0, len(g.roleAssignments))
+	for playerID, role := range g.roleAssignments {
+		outcomes = append(outcomes, core.PlayerOutcome{
+			PlayerID:   playerID,
+			Role:       string(role),
+			Team:       role.Team(),
+			Won:        winners[playerID],
+			Eliminated: eliminated[playerID],
+		})
+	}
+	return outcomes
+}
+
 // calculateResults determines the winner based on votes.
 func (g *Game) calculateResults() core.GameResults {
 	// Count votes
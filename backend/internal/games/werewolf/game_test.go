@@ -496,3 +496,81 @@ func hasSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestGame_PlayerOutcomes(t *testing.T) {
+	t.Parallel()
+
+	g := NewGame().(*Game)
+	g.roleAssignments = map[string]RoleType{
+		"p1": RoleWerewolf,
+		"p2": RoleSeer,
+		"p3": RoleVillager,
+		"p4": RoleTanner,
+	}
+	g.votes = map[string]string{"p1": "p2", "p2": "p1", "p3": "p1", "p4": "p1"}
+	g.phase = PhaseResults
+
+	outcomes := make(map[string]core.PlayerOutcome)
+	for _, outcome := range g.PlayerOutcomes() {
+		outcomes[outcome.PlayerID] = outcome
+	}
+
+	want := map[string]core.PlayerOutcome{
+		"p1": {PlayerID: "p1", Role: "werewolf", Team: TeamWerewolf, Eliminated: true},
+		"p2": {PlayerID: "p2", Role: "seer", Team: TeamVillage, Won: true},
+		"p3": {PlayerID: "p3", Role: "villager", Team: TeamVillage, Won: true},
+		"p4": {PlayerID: "p4", Role: "tanner", Team: TeamTanner},
+	}
+	for id, w := range want {
+		if outcomes[id] != w {
+			t.Errorf("outcome of %s = %+v, want %+v", id, outcomes[id], w)
+		}
+	}
+}
+
+func TestGame_ObserveReaction(t *testing.T) {
+	t.Parallel()
+
+	game := NewGame().(*Game)
+	config := &Config{
+		Roles:         []RoleType{RoleWerewolf, RoleSeer, RoleVillager, RoleVillager, RoleVillager, RoleVillager},
+		NightDuration: 3 * time.Minute,
+		DayDuration:   5 * time.Minute,
+	}
+	players := []*core.Player{
+		{ID: "p1", DisplayName: "Player1"},
+		{ID: "p2", DisplayName: "Player2"},
+		{ID: "p3", DisplayName: "Player3"},
+	}
+	if _, err := game.Initialize(config, players); err != nil {
+		t.Fatalf("failed to initialize game: %v", err)
+	}
+
+	point := func(from, to string) {
+		game.ObserveReaction(core.Reaction{Kind: core.ReactionPoint, PlayerID: from, TargetID: to})
+	}
+
+	// Pointing at night doesn't count
+	game.phase = PhaseNight
+	point("p1", "p2")
+	game.phase = PhaseDay
+	if accusations := game.GetPublicState().(PublicState).Accusations; len(accusations) != 0 {
+		t.Fatalf("expected no accusations from the night, got %+v", accusations)
+	}
+
+	point("p1", "p2")
+	point("p3", "p2")
+	point("p2", "p1")
+	game.ObserveReaction(core.Reaction{Kind: core.ReactionEmoji, PlayerID: "p2", TargetID: "p3", Emoji: "👀"})
+
+	accusations := game.GetPublicState().(PublicState).Accusations
+	if len(accusations) != 2 || accusations[0].SuspectID != "p2" || len(accusations[0].AccusedBy) != 2 {
+		t.Fatalf("expected p2 to be the most accused, got %+v", accusations)
+	}
+
+	// Accusations are only shown during the day
+	game.phase = PhaseResults
+	if accusations := game.GetPublicState().(PublicState).Accusations; accusations != nil {
+		t.Errorf("expected no accusations after the day, got %+v", accusations)
+	}
+}
@@ -0,0 +1,97 @@
+package werewolf
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// snapshot is the saved form of a werewolf game.
+type snapshot struct {
+	Config               *Config             `json:"config"`
+	HostID               string              `json:"hostId"`
+	PlayerIDs            []string            `json:"playerIds"`
+	RoleAssignments      map[string]RoleType `json:"roleAssignments"`
+	OriginalRoles        map[string]RoleType `json:"originalRoles"`
+	CenterCards          []RoleType          `json:"centerCards"`
+	RoleAcknowledgements map[string]bool     `json:"roleAcknowledgements"`
+	Votes                map[string]string   `json:"votes"`
+	Phase                Phase               `json:"phase"`
+	PhaseStartedAt       time.Time           `json:"phaseStartedAt"`
+	PhaseEndsAt          time.Time           `json:"phaseEndsAt"`
+	TimerActive          bool                `json:"timerActive"`
+	NightActionsComplete map[RoleType]bool   `json:"nightActionsComplete"`
+}
+
+// Snapshot implements core.Snapshotter.
+func (g *Game) Snapshot() (json.RawMessage, error) {
+	playerIDs := make([]string, 0, len(g.players))
+	for playerID := range g.players {
+		playerIDs = append(playerIDs, playerID)
+	}
+
+	return json.Marshal(snapshot{
+		Config:               g.config,
+		HostID:               g.hostID,
+		PlayerIDs:            playerIDs,
+		RoleAssignments:      g.roleAssignments,
+		OriginalRoles:        g.originalRoles,
+		CenterCards:          g.centerCards,
+		RoleAcknowledgements: g.roleAcknowledgements,
+		Votes:                g.votes,
+		Phase:                g.phase,
+		PhaseStartedAt:       g.phaseStartedAt,
+		PhaseEndsAt:          g.phaseEndsAt,
+		TimerActive:          g.timerActive,
+		NightActionsComplete: g.nightActionsComplete,
+	})
+}
+
+// Restore implements core.Snapshotter.
+func (g *Game) Restore(data json.RawMessage, players []*core.Player) error {
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	byID := make(map[string]*core.Player, len(players))
+	for _, player := range players {
+		byID[player.ID] = player
+	}
+
+	g.players = make(map[string]*core.Player, len(s.PlayerIDs))
+	for _, playerID := range s.PlayerIDs {
+		player, exists := byID[playerID]
+		if !exists {
+			return fmt.Errorf("player %s is not in the room", playerID)
+		}
+		g.players[playerID] = player
+	}
+
+	g.config = s.Config
+	g.hostID = s.HostID
+	g.roleAssignments = orEmpty(s.RoleAssignments)
+	g.originalRoles = orEmpty(s.OriginalRoles)
+	g.centerCards = s.CenterCards
+	g.roleAcknowledgements = orEmpty(s.RoleAcknowledgements)
+	g.votes = orEmpty(s.Votes)
+	g.phase = s.Phase
+	g.phaseStartedAt = s.PhaseStartedAt
+	g.phaseEndsAt = s.PhaseEndsAt
+	g.timerActive = s.TimerActive
+	g.nightActionsComplete = orEmpty(s.NightActionsComplete)
+
+	return nil
+}
+
+// orEmpty returns m, or an empty map if m is nil.
+func orEmpty[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return make(map[K]V)
+	}
+	return m
+}
+
+var _ core.Snapshotter = (*Game)(nil)
@@ -0,0 +1,83 @@
+package werewolf
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+func TestGame_SnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	game := NewGame().(*Game)
+	players := []*core.Player{
+		{ID: "p1", DisplayName: "Alice"},
+		{ID: "p2", DisplayName: "Bob"},
+		{ID: "p3", DisplayName: "Carol"},
+	}
+	config := &Config{Roles: []RoleType{
+		RoleWerewolf, RoleWerewolf, RoleSeer, RoleRobber, RoleVillager, RoleVillager,
+	}}
+
+	if _, err := game.Initialize(config, players); err != nil {
+		t.Fatalf("failed to initialize game: %v", err)
+	}
+	game.SetHost("p1")
+	if _, err := game.ProcessAction("p1", core.Action{Type: "acknowledge_role"}); err != nil {
+		t.Fatalf("failed to acknowledge: %v", err)
+	}
+
+	data, err := game.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	restoredPlayers := []*core.Player{
+		{ID: "p1", DisplayName: "Alice"},
+		{ID: "p2", DisplayName: "Bob"},
+		{ID: "p3", DisplayName: "Carol"},
+	}
+	restored := NewGame().(*Game)
+	if err := restored.Restore(data, restoredPlayers); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	if restored.hostID != "p1" {
+		t.Errorf("expected host p1, got %q", restored.hostID)
+	}
+	if restored.players["p2"] != restoredPlayers[1] {
+		t.Error("expected restored game to reference the room's players")
+	}
+	if !reflect.DeepEqual(game.centerCards, restored.centerCards) {
+		t.Errorf("center cards differ: %v vs %v", game.centerCards, restored.centerCards)
+	}
+	for _, player := range players {
+		want, _ := json.Marshal(game.GetPlayerState(player.ID))
+		got, _ := json.Marshal(restored.GetPlayerState(player.ID))
+		if string(want) != string(got) {
+			t.Errorf("player %s state differs after restore:\nwant %s\ngot  %s", player.ID, want, got)
+		}
+	}
+
+	// The restored game keeps playing: the remaining acknowledgements
+	// still move it out of role reveal
+	for _, playerID := range []string{"p2", "p3"} {
+		if _, err := restored.ProcessAction(playerID, core.Action{Type: "acknowledge_role"}); err != nil {
+			t.Fatalf("restored game rejected acknowledgement: %v", err)
+		}
+	}
+	if restored.phase == PhaseRoleReveal {
+		t.Error("expected restored game to leave role reveal")
+	}
+}
+
+func TestGame_RestoreUnknownPlayer(t *testing.T) {
+	t.Parallel()
+
+	data, _ := json.Marshal(snapshot{PlayerIDs: []string{"ghost"}})
+	if err := NewGame().(*Game).Restore(data, nil); err == nil {
+		t.Error("expected error restoring a player that is not in the room")
+	}
+}
@@ -1,6 +1,10 @@
 package werewolf
 
-import "time"
+import (
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
 
 // PlayerState is the werewolf-specific state for a single player.
 type PlayerState struct {
@@ -20,6 +24,9 @@ type PublicState struct {
 	VotesSubmitted        int       `json:"votesSubmitted"`
 	AcknowledgementsCount int       `json:"acknowledgementsCount"`
 	TimerActive           bool      `json:"timerActive"`
+
+	// Accusations summarizes who players point at, during the day
+	Accusations []core.Accusation `json:"accusations,omitempty"`
 }
 
 // Event payloads
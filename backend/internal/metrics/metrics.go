@@ -0,0 +1,127 @@
+// Package metrics records server activity for Prometheus.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+const namespace = "roundtable"
+
+// rejectedAction is the action_type label used for failed actions.
+// Action types come from clients, so failures are not labeled with the
+// requested type to keep label cardinality bounded.
+const rejectedAction = "-"
+
+// Metrics holds the server's Prometheus collectors.
+// A nil *Metrics is valid and records nothing.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	eventsAppended    *prometheus.CounterVec
+	actionDuration    *prometheus.HistogramVec
+	broadcastsDropped *prometheus.CounterVec
+	roomsCleanedUp    *prometheus.CounterVec
+}
+
+// New creates a metrics set with its own registry, including the standard
+// Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		eventsAppended: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_appended_total",
+			Help:      "Events appended to room event logs.",
+		}, []string{"game_type", "event_type"}),
+		actionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "action_duration_seconds",
+			Help:      "Time spent processing player actions.",
+			Buckets:   []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1},
+		}, []string{"game_type", "action_type", "result"}),
+		broadcastsDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "broadcasts_dropped_total",
+			Help:      "Messages dropped because a connection's send buffer was full.",
+		}, []string{"message_type"}),
+		roomsCleanedUp: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rooms_cleaned_up_total",
+			Help:      "Rooms removed by stale room cleanup.",
+		}, []string{"reason"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.eventsAppended,
+		m.actionDuration,
+		m.broadcastsDropped,
+		m.roomsCleanedUp,
+	)
+
+	return m
+}
+
+// Register adds collectors to the registry, e.g. for values computed at
+// scrape time.
+func (m *Metrics) Register(collector prometheus.Collector) error {
+	if m == nil {
+		return nil
+	}
+	return m.registry.Register(collector)
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// EventsAppended implements core.RoomObserver.
+func (m *Metrics) EventsAppended(gameType string, events []core.GameEvent) {
+	if m == nil {
+		return
+	}
+	for _, event := range events {
+		m.eventsAppended.WithLabelValues(gameType, event.Type).Inc()
+	}
+}
+
+// ActionProcessed implements core.RoomObserver.
+func (m *Metrics) ActionProcessed(gameType string, actionType string, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	result := "ok"
+	if err != nil {
+		result = "error"
+		actionType = rejectedAction
+	}
+	m.actionDuration.WithLabelValues(gameType, actionType, result).Observe(duration.Seconds())
+}
+
+// BroadcastDropped records a message that could not be queued for a client.
+func (m *Metrics) BroadcastDropped(messageType string) {
+	if m == nil {
+		return
+	}
+	m.broadcastsDropped.WithLabelValues(messageType).Inc()
+}
+
+// RoomCleanedUp records a room removed by stale room cleanup.
+func (m *Metrics) RoomCleanedUp(room *core.Room, reason string) {
+	if m == nil {
+		return
+	}
+	m.roomsCleanedUp.WithLabelValues(reason).Inc()
+}
+
+var _ core.RoomObserver = (*Metrics)(nil)
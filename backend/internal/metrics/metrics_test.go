@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+func TestMetrics_EventsAppended(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	host := core.NewPlayer("Host")
+	room := core.NewRoom("MET123", "werewolf", host, 10)
+	room.SetObserver(m)
+
+	joined, _ := core.NewPublicEvent(core.EventPlayerJoined, "system", nil)
+	room.AppendEvent(joined)
+	room.AppendEvents([]core.GameEvent{joined, joined})
+
+	if got := testutil.ToFloat64(m.eventsAppended.WithLabelValues("werewolf", core.EventPlayerJoined)); got != 3 {
+		t.Errorf("expected 3 events, got %v", got)
+	}
+}
+
+func TestMetrics_ActionProcessed(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	m.ActionProcessed("avalon", "vote", 2*time.Millisecond, nil)
+	m.ActionProcessed("avalon", "vote", time.Millisecond, nil)
+	m.ActionProcessed("avalon", "made_up_by_client", time.Millisecond, errors.New("unknown action"))
+
+	// One series for successful votes, one shared series for rejected
+	// actions: client-supplied types must not create new series.
+	if got := testutil.CollectAndCount(m.actionDuration); got != 2 {
+		t.Errorf("expected 2 histogram series, got %d", got)
+	}
+}
+
+func TestMetrics_RoomActionLatency(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	room := core.NewRoom("MET456", "werewolf", core.NewPlayer("Host"), 10)
+	room.SetObserver(m)
+
+	// No game in progress: the action fails but is still observed
+	if _, err := room.ProcessAction("p1", core.Action{Type: "vote"}); err == nil {
+		t.Fatal("expected error without a game")
+	}
+
+	if got := testutil.CollectAndCount(m.actionDuration); got != 1 {
+		t.Errorf("expected 1 histogram series, got %d", got)
+	}
+}
+
+func TestMetrics_Counters(t *testing.T) {
+	t.Parallel()
+
+	m := New()
+	m.BroadcastDropped("event")
+	m.BroadcastDropped("event")
+	m.RoomCleanedUp(nil, "finished")
+
+	if got := testutil.ToFloat64(m.broadcastsDropped.WithLabelValues("event")); got != 2 {
+		t.Errorf("expected 2 dropped broadcasts, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.roomsCleanedUp.WithLabelValues("finished")); got != 1 {
+		t.Errorf("expected 1 cleaned up room, got %v", got)
+	}
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	t.Parallel()
+
+	var m *Metrics
+	m.EventsAppended("werewolf", nil)
+	m.ActionProcessed("werewolf", "vote", time.Millisecond, nil)
+	m.BroadcastDropped("event")
+	m.RoomCleanedUp(nil, "finished")
+	if err := m.Register(nil); err != nil {
+		t.Errorf("expected nil metrics to ignore Register, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+// Package profile provides optional, lightweight player profiles that carry a
+// display name, avatar color and preferences between rooms.
+//
+// Profiles have no email or password. A device proves it owns a profile with
+// a random device key issued when the profile is created. Profiles with a
+// username and PIN can also be signed into from other devices, each of which
+// gets its own key.
+package profile
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Limits on profile contents.
+const (
+	MaxPreferences     = 20  // Preference keys per profile
+	MaxPreferenceKey   = 32  // Bytes per preference key
+	MaxPreferenceValue = 256 // Bytes per preference value
+	MaxMemberships     = 100 // Room memberships kept per profile, oldest dropped
+	MaxDeviceKeys      = 10  // Devices per profile, oldest signed out
+)
+
+var (
+	ErrNotFound           = errors.New("profile not found")
+	ErrInvalidKey         = errors.New("invalid profile key")
+	ErrInvalidLogin       = errors.New("incorrect username or PIN")
+	ErrLocked             = errors.New("too many failed sign-ins, try again later")
+	ErrUsernameTaken      = errors.New("username is taken")
+	ErrInvalidUsername    = errors.New("username must be 3 to 20 lowercase letters, digits or underscores")
+	ErrInvalidPIN         = errors.New("PIN must be 4 to 8 digits")
+	ErrInvalidAvatarColor = errors.New("avatar color must be a hex color like #3b82f6")
+	ErrInvalidPreferences = errors.New("too many or too long preferences")
+)
+
+var (
+	validUsernameRegex = regexp.MustCompile(`^[a-z0-9_]{3,20}$`)
+	validPINRegex      = regexp.MustCompile(`^[0-9]{4,8}$`)
+	validColorRegex    = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// AvatarColors are the colors given to profiles that don't choose one.
+var AvatarColors = []string{
+	"#ef4444", "#f97316", "#eab308", "#22c55e",
+	"#14b8a6", "#3b82f6", "#8b5cf6", "#ec4899",
+}
+
+// Profile is a player's identity across rooms.
+type Profile struct {
+	ID          string            `json:"id"`
+	Username    string            `json:"username,omitempty"` // Set for profiles with a PIN
+	DisplayName string            `json:"displayName"`
+	AvatarColor string            `json:"avatarColor"`
+	Preferences map[string]string `json:"preferences,omitempty"`
+	Memberships []Membership      `json:"memberships,omitempty"` // Oldest first
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// Membership links a room's player to the profile.
+type Membership struct {
+	RoomCode string    `json:"roomCode"`
+	PlayerID string    `json:"playerId"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+// New describes a profile to create. Username and PIN are optional but go
+// together.
+type New struct {
+	DisplayName string
+	AvatarColor string
+	Username    string
+	PIN         string
+}
+
+// Changes describes an update to a profile. Nil fields are left unchanged;
+// non-nil Preferences replace all preferences.
+type Changes struct {
+	DisplayName *string           `json:"displayName,omitempty"`
+	AvatarColor *string           `json:"avatarColor,omitempty"`
+	Preferences map[string]string `json:"preferences,omitempty"`
+}
+
+// clone returns a deep copy of the profile.
+func (p Profile) clone() Profile {
+	if p.Preferences != nil {
+		preferences := make(map[string]string, len(p.Preferences))
+		for key, value := range p.Preferences {
+			preferences[key] = value
+		}
+		p.Preferences = preferences
+	}
+	p.Memberships = append([]Membership(nil), p.Memberships...)
+	return p
+}
+
+// NormalizeUsername converts a username as typed to its canonical form.
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// validateUsername checks a normalized username and PIN pair.
+func validateUsername(username, pin string) error {
+	if !validUsernameRegex.MatchString(username) {
+		return ErrInvalidUsername
+	}
+	if !validPINRegex.MatchString(pin) {
+		return ErrInvalidPIN
+	}
+	return nil
+}
+
+// validateAvatarColor checks a "#rrggbb" color.
+func validateAvatarColor(color string) error {
+	if !validColorRegex.MatchString(color) {
+		return ErrInvalidAvatarColor
+	}
+	return nil
+}
+
+// validatePreferences checks preference counts and sizes.
+func validatePreferences(preferences map[string]string) error {
+	if len(preferences) > MaxPreferences {
+		return fmt.Errorf("%w: at most %d", ErrInvalidPreferences, MaxPreferences)
+	}
+	for key, value := range preferences {
+		if key == "" || len(key) > MaxPreferenceKey || len(value) > MaxPreferenceValue {
+			return fmt.Errorf("%w: %q", ErrInvalidPreferences, key)
+		}
+	}
+	return nil
+}
+
+// randomColor picks one of AvatarColors.
+func randomColor() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(AvatarColors))))
+	if err != nil {
+		return "", err
+	}
+	return AvatarColors[n.Int64()], nil
+}
+
+// newDeviceKey creates a random device key.
+func newDeviceKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate profile key: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(key), nil
+}
+
+// hashDeviceKey returns the form device keys are stored in. Keys are random,
+// so a fast hash is enough.
+func hashDeviceKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,414 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/KonradHerman/roundtable/internal/util"
+)
+
+// Sign-in lockout: after MaxLoginAttempts sign-ins to a username from one
+// client without success, sign-in from that client is refused for
+// LockoutDuration, so someone guessing from elsewhere doesn't lock the owner
+// out. PINs are short, so MaxUsernameLoginAttempts from all clients together
+// lock the username everywhere, keeping many clients from guessing it.
+const (
+	MaxLoginAttempts         = 5
+	MaxUsernameLoginAttempts = 20
+	LockoutDuration          = 15 * time.Minute
+)
+
+// pinCost is the bcrypt cost of PIN hashes, lowered in tests.
+var pinCost = bcrypt.DefaultCost
+
+var (
+	dummyPINOnce sync.Once
+	dummyPINHash []byte
+)
+
+// dummyHash returns a hash that is compared against for unknown usernames,
+// so a sign-in takes as long whether or not the username exists.
+func dummyHash() []byte {
+	dummyPINOnce.Do(func() {
+		dummyPINHash, _ = bcrypt.GenerateFromPassword([]byte("00000000"), pinCost)
+	})
+	return dummyPINHash
+}
+
+// record is a stored profile with its credentials.
+type record struct {
+	Profile
+	PINHash   []byte   `json:"pinHash,omitempty"`
+	KeyHashes []string `json:"keyHashes"` // Oldest first
+}
+
+// loginFailures tracks sign-ins to a username that haven't succeeded.
+type loginFailures struct {
+	count       int
+	lastAttempt time.Time
+	lockedUntil time.Time
+}
+
+// failureKey identifies a username signed into from a client. An empty
+// client counts sign-ins from every client.
+type failureKey struct {
+	username string
+	client   string
+}
+
+// Store holds profiles in memory, optionally persisted to a JSON file that
+// is rewritten on every change. It is safe for concurrent use.
+type Store struct {
+	mu        sync.Mutex
+	profiles  map[string]*record // Profile ID → record
+	keys      map[string]string  // Device key hash → profile ID
+	usernames map[string]string  // Username → profile ID
+	failures  map[failureKey]*loginFailures
+
+	path string           // Empty for an in-memory store
+	now  func() time.Time // Replaced in tests
+}
+
+// NewStore creates an in-memory profile store.
+func NewStore() *Store {
+	return &Store{
+		profiles:  make(map[string]*record),
+		keys:      make(map[string]string),
+		usernames: make(map[string]string),
+		failures:  make(map[failureKey]*loginFailures),
+		now:       time.Now,
+	}
+}
+
+// OpenStore creates a profile store persisted to path, loading the profiles
+// saved there if the file exists.
+func OpenStore(path string) (*Store, error) {
+	s := NewStore()
+	s.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("profiles %s: %w", path, err)
+	}
+	for _, rec := range records {
+		s.index(rec)
+	}
+	return s, nil
+}
+
+// Create creates a profile and returns it with the device key for the
+// device that created it. Callers validate the display name.
+func (s *Store) Create(n New) (Profile, string, error) {
+	if n.AvatarColor == "" {
+		color, err := randomColor()
+		if err != nil {
+			return Profile{}, "", err
+		}
+		n.AvatarColor = color
+	}
+	if err := validateAvatarColor(n.AvatarColor); err != nil {
+		return Profile{}, "", err
+	}
+
+	var pinHash []byte
+	username := NormalizeUsername(n.Username)
+	if username != "" || n.PIN != "" {
+		if err := validateUsername(username, n.PIN); err != nil {
+			return Profile{}, "", err
+		}
+
+		var err error
+		pinHash, err = bcrypt.GenerateFromPassword([]byte(n.PIN), pinCost)
+		if err != nil {
+			return Profile{}, "", err
+		}
+	}
+
+	key, err := newDeviceKey()
+	if err != nil {
+		return Profile{}, "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, taken := s.usernames[username]; username != "" && taken {
+		return Profile{}, "", ErrUsernameTaken
+	}
+
+	now := s.now()
+	rec := &record{
+		Profile: Profile{
+			ID:          uuid.New().String(),
+			Username:    username,
+			DisplayName: n.DisplayName,
+			AvatarColor: n.AvatarColor,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		PINHash:   pinHash,
+		KeyHashes: []string{hashDeviceKey(key)},
+	}
+	s.index(rec)
+
+	return rec.clone(), key, s.save()
+}
+
+// Authenticate returns the profile a device key belongs to.
+func (s *Store) Authenticate(key string) (Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.keys[hashDeviceKey(key)]
+	if !ok || key == "" {
+		return Profile{}, ErrInvalidKey
+	}
+	return s.profiles[id].clone(), nil
+}
+
+// Login signs a new device into the profile with the username and PIN, and
+// returns the profile with the new device's key. The client, such as an IP
+// address, is what failed PINs lock out first; too many from all clients
+// lock the username out everywhere. When a profile already has
+// MaxDeviceKeys devices, the oldest is signed out.
+func (s *Store) Login(username, pin, client string) (Profile, string, error) {
+	username = NormalizeUsername(username)
+	attempt := failureKey{username: username, client: client}
+
+	s.mu.Lock()
+	hash := dummyHash()
+	id, exists := s.usernames[username]
+	if exists {
+		// Count the attempt before comparing, so parallel guesses can't all
+		// get past the lockout check
+		if !s.countAttempt(attempt) {
+			s.mu.Unlock()
+			return Profile{}, "", ErrLocked
+		}
+		hash = s.profiles[id].PINHash
+	}
+	s.mu.Unlock()
+
+	// bcrypt is slow on purpose; don't hold the lock meanwhile
+	matched := bcrypt.CompareHashAndPassword(hash, []byte(pin)) == nil
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, stillExists := s.profiles[id]
+	if !exists || !stillExists {
+		return Profile{}, "", ErrInvalidLogin
+	}
+	if !matched {
+		return Profile{}, "", ErrInvalidLogin
+	}
+	delete(s.failures, attempt)
+
+	key, err := newDeviceKey()
+	if err != nil {
+		return Profile{}, "", err
+	}
+	rec.KeyHashes = append(rec.KeyHashes, hashDeviceKey(key))
+	s.keys[hashDeviceKey(key)] = rec.ID
+	for len(rec.KeyHashes) > MaxDeviceKeys {
+		delete(s.keys, rec.KeyHashes[0])
+		rec.KeyHashes = rec.KeyHashes[1:]
+	}
+
+	return rec.clone(), key, s.save()
+}
+
+// SignOut invalidates a device key.
+func (s *Store) SignOut(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash := hashDeviceKey(key)
+	id, ok := s.keys[hash]
+	if !ok {
+		return ErrInvalidKey
+	}
+	delete(s.keys, hash)
+
+	rec := s.profiles[id]
+	for i, keyHash := range rec.KeyHashes {
+		if keyHash == hash {
+			rec.KeyHashes = append(rec.KeyHashes[:i:i], rec.KeyHashes[i+1:]...)
+			break
+		}
+	}
+	return s.save()
+}
+
+// Get returns a profile by ID.
+func (s *Store) Get(id string) (Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.profiles[id]
+	if !ok {
+		return Profile{}, ErrNotFound
+	}
+	return rec.clone(), nil
+}
+
+// Update applies changes to a profile. Callers validate the display name.
+func (s *Store) Update(id string, changes Changes) (Profile, error) {
+	if changes.AvatarColor != nil {
+		if err := validateAvatarColor(*changes.AvatarColor); err != nil {
+			return Profile{}, err
+		}
+	}
+	if err := validatePreferences(changes.Preferences); err != nil {
+		return Profile{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.profiles[id]
+	if !ok {
+		return Profile{}, ErrNotFound
+	}
+
+	if changes.DisplayName != nil {
+		rec.DisplayName = *changes.DisplayName
+	}
+	if changes.AvatarColor != nil {
+		rec.AvatarColor = *changes.AvatarColor
+	}
+	if changes.Preferences != nil {
+		rec.Preferences = make(map[string]string, len(changes.Preferences))
+		for key, value := range changes.Preferences {
+			rec.Preferences[key] = value
+		}
+	}
+	rec.UpdatedAt = s.now()
+
+	return rec.clone(), s.save()
+}
+
+// Link records that the profile joined a room as the given player.
+func (s *Store) Link(id, roomCode, playerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.profiles[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	rec.Memberships = append(rec.Memberships, Membership{
+		RoomCode: roomCode,
+		PlayerID: playerID,
+		JoinedAt: s.now(),
+	})
+	if len(rec.Memberships) > MaxMemberships {
+		rec.Memberships = rec.Memberships[len(rec.Memberships)-MaxMemberships:]
+	}
+	return s.save()
+}
+
+// Delete removes a profile and signs out all its devices.
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.profiles[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	for _, hash := range rec.KeyHashes {
+		delete(s.keys, hash)
+	}
+	if rec.Username != "" {
+		delete(s.usernames, rec.Username)
+	}
+	delete(s.profiles, id)
+	return s.save()
+}
+
+// index adds a record to the lookup maps. The caller holds s.mu.
+func (s *Store) index(rec *record) {
+	s.profiles[rec.ID] = rec
+	for _, hash := range rec.KeyHashes {
+		s.keys[hash] = rec.ID
+	}
+	if rec.Username != "" {
+		s.usernames[rec.Username] = rec.ID
+	}
+}
+
+// recordFailure counts a failed sign-in to an existing username, locking it
+// for the client after MaxLoginAttempts. Failures older than LockoutDuration,
+// countAttempt counts a sign-in to the username from the client and from
+// every client, and reports whether it may go ahead. The caller holds s.mu.
+func (s *Store) countAttempt(attempt failureKey) bool {
+	now := s.now()
+	for key, failures := range s.failures {
+		// A lockout ends LockoutDuration after the attempt that started it
+		if now.Sub(failures.lastAttempt) >= LockoutDuration {
+			delete(s.failures, key)
+		}
+	}
+
+	limits := map[failureKey]int{
+		attempt:                      MaxLoginAttempts,
+		{username: attempt.username}: MaxUsernameLoginAttempts,
+	}
+	for key := range limits {
+		if failures := s.failures[key]; failures != nil && now.Before(failures.lockedUntil) {
+			return false
+		}
+	}
+
+	for key, limit := range limits {
+		failures := s.failures[key]
+		if failures == nil {
+			failures = &loginFailures{}
+			s.failures[key] = failures
+		}
+
+		failures.count++
+		failures.lastAttempt = now
+		if failures.count >= limit {
+			failures.count = 0
+			failures.lockedUntil = now.Add(LockoutDuration)
+		}
+	}
+	return true
+}
+
+// save writes every profile to the store's file atomically. Changes stay in
+// memory even if writing fails. The caller holds s.mu.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	records := make([]*record, 0, len(s.profiles))
+	for _, rec := range s.profiles {
+		records = append(records, rec)
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(s.path, data)
+}
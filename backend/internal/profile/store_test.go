@@ -0,0 +1,315 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func init() {
+	// Keep PIN hashing fast in tests
+	pinCost = bcrypt.MinCost
+}
+
+func TestStore_CreateAndAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	created, key, err := s.Create(New{DisplayName: "Alice"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if validateAvatarColor(created.AvatarColor) != nil {
+		t.Errorf("expected a default avatar color, got %q", created.AvatarColor)
+	}
+
+	got, err := s.Authenticate(key)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if got.ID != created.ID || got.DisplayName != "Alice" {
+		t.Errorf("expected profile %s, got %+v", created.ID, got)
+	}
+
+	if _, err := s.Authenticate("not-a-key"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+	if _, err := s.Authenticate(""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey for empty key, got %v", err)
+	}
+}
+
+func TestStore_CreateValidation(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	if _, _, err := s.Create(New{DisplayName: "Taken", Username: "alice", PIN: "1234"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		new     New
+		wantErr error
+	}{
+		{name: "bad color", new: New{DisplayName: "A", AvatarColor: "red"}, wantErr: ErrInvalidAvatarColor},
+		{name: "username without PIN", new: New{DisplayName: "A", Username: "bob"}, wantErr: ErrInvalidPIN},
+		{name: "PIN without username", new: New{DisplayName: "A", PIN: "1234"}, wantErr: ErrInvalidUsername},
+		{name: "short PIN", new: New{DisplayName: "A", Username: "bob", PIN: "123"}, wantErr: ErrInvalidPIN},
+		{name: "letters in PIN", new: New{DisplayName: "A", Username: "bob", PIN: "12ab"}, wantErr: ErrInvalidPIN},
+		{name: "bad username", new: New{DisplayName: "A", Username: "b!", PIN: "1234"}, wantErr: ErrInvalidUsername},
+		{name: "taken username", new: New{DisplayName: "A", Username: " ALICE ", PIN: "9999"}, wantErr: ErrUsernameTaken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, _, err := s.Create(tt.new); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStore_Login(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	created, firstKey, _ := s.Create(New{DisplayName: "Alice", Username: "alice", PIN: "2468"})
+
+	got, key, err := s.Login("Alice", "2468", "203.0.113.1")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if got.ID != created.ID || key == firstKey {
+		t.Errorf("expected a new key for profile %s, got %+v", created.ID, got)
+	}
+	for _, k := range []string{firstKey, key} {
+		if _, err := s.Authenticate(k); err != nil {
+			t.Errorf("expected both devices signed in: %v", err)
+		}
+	}
+
+	if _, _, err := s.Login("alice", "0000", "203.0.113.1"); !errors.Is(err, ErrInvalidLogin) {
+		t.Errorf("expected ErrInvalidLogin for wrong PIN, got %v", err)
+	}
+	if _, _, err := s.Login("nobody", "2468", "203.0.113.1"); !errors.Is(err, ErrInvalidLogin) {
+		t.Errorf("expected ErrInvalidLogin for unknown username, got %v", err)
+	}
+}
+
+func TestStore_LoginLockout(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	start := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return start }
+	s.Create(New{DisplayName: "Alice", Username: "alice", PIN: "2468"})
+
+	for i := 0; i < MaxLoginAttempts; i++ {
+		s.Login("alice", "0000", "203.0.113.1")
+	}
+	if _, _, err := s.Login("alice", "2468", "203.0.113.1"); !errors.Is(err, ErrLocked) {
+		t.Errorf("expected ErrLocked with the right PIN during lockout, got %v", err)
+	}
+
+	// Guessing from another client doesn't lock the owner out
+	if _, _, err := s.Login("alice", "2468", "198.51.100.7"); err != nil {
+		t.Errorf("expected sign-in from another client during lockout, got %v", err)
+	}
+
+	s.now = func() time.Time { return start.Add(LockoutDuration) }
+	if _, _, err := s.Login("alice", "2468", "203.0.113.1"); err != nil {
+		t.Errorf("expected sign-in after lockout, got %v", err)
+	}
+}
+
+func TestStore_LoginFailuresForgotten(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	start := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return start }
+	s.Create(New{DisplayName: "Alice", Username: "alice", PIN: "2468"})
+
+	// Unknown usernames are never tracked
+	for i := 0; i < 10; i++ {
+		s.Login(fmt.Sprintf("nobody%d", i), "0000", "203.0.113.1")
+	}
+	if len(s.failures) != 0 {
+		t.Fatalf("expected no failures tracked for unknown usernames, got %d", len(s.failures))
+	}
+
+	s.Login("alice", "0000", "203.0.113.1")
+	s.now = func() time.Time { return start.Add(LockoutDuration) }
+	s.Login("alice", "0000", "198.51.100.7")
+	// The new client's count and the username's count from every client
+	if len(s.failures) != 2 {
+		t.Errorf("expected the stale failure evicted, got %d tracked", len(s.failures))
+	}
+}
+
+func TestStore_LoginUsernameLockout(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	s.Create(New{DisplayName: "Alice", Username: "alice", PIN: "2468"})
+
+	// Guesses spread over many clients still lock the username
+	for i := 0; i < MaxUsernameLoginAttempts; i++ {
+		s.Login("alice", "0000", fmt.Sprintf("203.0.113.%d", i))
+	}
+	if _, _, err := s.Login("alice", "2468", "198.51.100.7"); !errors.Is(err, ErrLocked) {
+		t.Errorf("expected ErrLocked from a new client, got %v", err)
+	}
+}
+
+func TestStore_LoginCountsParallelAttempts(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	s.Create(New{DisplayName: "Alice", Username: "alice", PIN: "2468"})
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		compared int
+	)
+	for i := 0; i < 3*MaxLoginAttempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := s.Login("alice", "0000", "203.0.113.1"); errors.Is(err, ErrInvalidLogin) {
+				mu.Lock()
+				compared++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if compared > MaxLoginAttempts {
+		t.Errorf("expected at most %d PINs compared, got %d", MaxLoginAttempts, compared)
+	}
+}
+
+func TestStore_DeviceKeyLimit(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	_, firstKey, _ := s.Create(New{DisplayName: "Alice", Username: "alice", PIN: "2468"})
+
+	for i := 0; i < MaxDeviceKeys; i++ {
+		if _, _, err := s.Login("alice", "2468", "203.0.113.1"); err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+	}
+	if _, err := s.Authenticate(firstKey); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected oldest device signed out, got %v", err)
+	}
+}
+
+func TestStore_UpdateAndLink(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	created, _, _ := s.Create(New{DisplayName: "Alice"})
+
+	name, color := "Alicia", "#123abc"
+	updated, err := s.Update(created.ID, Changes{
+		DisplayName: &name,
+		AvatarColor: &color,
+		Preferences: map[string]string{"theme": "dark"},
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.DisplayName != name || updated.AvatarColor != color || updated.Preferences["theme"] != "dark" {
+		t.Errorf("unexpected profile after update: %+v", updated)
+	}
+
+	// Returned profiles are copies
+	updated.Preferences["theme"] = "light"
+	if got, _ := s.Get(created.ID); got.Preferences["theme"] != "dark" {
+		t.Error("expected stored preferences unchanged by callers")
+	}
+
+	tooMany := make(map[string]string)
+	for i := 0; i <= MaxPreferences; i++ {
+		tooMany[string(rune('a'+i))] = "x"
+	}
+	if _, err := s.Update(created.ID, Changes{Preferences: tooMany}); !errors.Is(err, ErrInvalidPreferences) {
+		t.Errorf("expected ErrInvalidPreferences, got %v", err)
+	}
+
+	for i := 0; i < MaxMemberships+5; i++ {
+		if err := s.Link(created.ID, "ROOM01", "player"); err != nil {
+			t.Fatalf("Link() error = %v", err)
+		}
+	}
+	if got, _ := s.Get(created.ID); len(got.Memberships) != MaxMemberships {
+		t.Errorf("expected %d memberships, got %d", MaxMemberships, len(got.Memberships))
+	}
+
+	if err := s.Link("missing", "ROOM01", "player"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStore_SignOutAndDelete(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	created, key, _ := s.Create(New{DisplayName: "Alice", Username: "alice", PIN: "2468"})
+	_, otherKey, _ := s.Login("alice", "2468", "203.0.113.1")
+
+	if err := s.SignOut(key); err != nil {
+		t.Fatalf("SignOut() error = %v", err)
+	}
+	if _, err := s.Authenticate(key); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected signed out key rejected, got %v", err)
+	}
+
+	if err := s.Delete(created.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Authenticate(otherKey); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected all devices signed out, got %v", err)
+	}
+	if _, _, err := s.Create(New{DisplayName: "New", Username: "alice", PIN: "1357"}); err != nil {
+		t.Errorf("expected username free after delete, got %v", err)
+	}
+}
+
+func TestOpenStore_Persists(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	s, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	created, key, _ := s.Create(New{DisplayName: "Alice", Username: "alice", PIN: "2468"})
+	s.Link(created.ID, "ROOM01", "player-1")
+
+	reopened, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	got, err := reopened.Authenticate(key)
+	if err != nil {
+		t.Fatalf("expected device key to survive reopening: %v", err)
+	}
+	if len(got.Memberships) != 1 || got.Memberships[0].PlayerID != "player-1" {
+		t.Errorf("expected membership to persist, got %+v", got.Memberships)
+	}
+	if _, _, err := reopened.Login("alice", "2468", "203.0.113.1"); err != nil {
+		t.Errorf("expected PIN to persist: %v", err)
+	}
+}
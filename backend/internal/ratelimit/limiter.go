@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limit configures a token bucket.
+// Rate is the sustained number of requests allowed per second;
+// Burst is the bucket size (requests allowed at once after idling).
+// A zero Rate disables limiting.
+type Limit struct {
+	Rate  float64 `json:"rate" yaml:"rate"`
+	Burst int     `json:"burst" yaml:"burst"`
+}
+
+// PerMinute returns a Limit allowing n requests per minute with the given burst.
+func PerMinute(n int, burst int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: burst}
+}
+
+// Enabled reports whether the limit restricts anything.
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// idleTimeout is how long an unused bucket is kept before being pruned.
+// A bucket idle this long has refilled anyway, so dropping it is lossless
+// for any reasonable limit.
+const idleTimeout = 10 * time.Minute
+
+// pruneInterval is the minimum time between prune passes.
+const pruneInterval = time.Minute
+
+// Limiter is a set of token buckets keyed by client identity
+// (IP address, session, ...). It is safe for concurrent use.
+type Limiter struct {
+	name  string
+	limit Limit
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+
+	allowed   atomic.Uint64
+	throttled atomic.Uint64
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// New creates a keyed limiter. The name identifies it in stats and logs.
+func New(name string, limit Limit) *Limiter {
+	return &Limiter{
+		name:      name,
+		limit:     limit,
+		buckets:   make(map[string]*bucket),
+		lastPrune: time.Now(),
+	}
+}
+
+// Name returns the limiter's name.
+func (l *Limiter) Name() string {
+	return l.name
+}
+
+// Allow consumes a token for key. When the bucket is empty it returns false
+// and how long the caller should wait before retrying.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if !l.limit.Enabled() {
+		l.allowed.Add(1)
+		return true, 0
+	}
+
+	now := time.Now()
+
+	l.mu.Lock()
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(l.limit.Rate), l.limit.Burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+	l.pruneLocked(now)
+	l.mu.Unlock()
+
+	reservation := b.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		l.throttled.Add(1)
+		return false, delay
+	}
+
+	l.allowed.Add(1)
+	return true, 0
+}
+
+// pruneLocked drops idle buckets. Caller must hold l.mu.
+func (l *Limiter) pruneLocked(now time.Time) {
+	if now.Sub(l.lastPrune) < pruneInterval {
+		return
+	}
+	l.lastPrune = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idleTimeout {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Stats is a snapshot of a limiter's counters.
+type Stats struct {
+	Name      string `json:"name"`
+	Allowed   uint64 `json:"allowed"`
+	Throttled uint64 `json:"throttled"`
+	Keys      int    `json:"keys"`
+}
+
+// Stats returns the limiter's counters.
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	keys := len(l.buckets)
+	l.mu.Unlock()
+
+	return Stats{
+		Name:      l.name,
+		Allowed:   l.allowed.Load(),
+		Throttled: l.throttled.Load(),
+		Keys:      keys,
+	}
+}
@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Burst(t *testing.T) {
+	t.Parallel()
+
+	limiter := New("test", Limit{Rate: 1, Burst: 3})
+
+	for i := 0; i < 3; i++ {
+		if allowed, _ := limiter.Allow("client"); !allowed {
+			t.Fatalf("request %d should be allowed within burst", i+1)
+		}
+	}
+
+	allowed, retryAfter := limiter.Allow("client")
+	if allowed {
+		t.Fatal("request beyond burst should be throttled")
+	}
+	if retryAfter <= 0 || retryAfter > time.Second {
+		t.Errorf("expected retry after within (0, 1s], got %v", retryAfter)
+	}
+}
+
+func TestLimiter_KeysAreIndependent(t *testing.T) {
+	t.Parallel()
+
+	limiter := New("test", Limit{Rate: 1, Burst: 1})
+
+	if allowed, _ := limiter.Allow("a"); !allowed {
+		t.Fatal("first request for a should be allowed")
+	}
+	if allowed, _ := limiter.Allow("a"); allowed {
+		t.Fatal("second request for a should be throttled")
+	}
+	if allowed, _ := limiter.Allow("b"); !allowed {
+		t.Fatal("b should have its own bucket")
+	}
+}
+
+func TestLimiter_Refill(t *testing.T) {
+	t.Parallel()
+
+	limiter := New("test", Limit{Rate: 50, Burst: 1})
+
+	limiter.Allow("client")
+	if allowed, _ := limiter.Allow("client"); allowed {
+		t.Fatal("bucket should be empty")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if allowed, _ := limiter.Allow("client"); !allowed {
+		t.Error("bucket should have refilled")
+	}
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	t.Parallel()
+
+	limiter := New("test", Limit{})
+
+	for i := 0; i < 1000; i++ {
+		if allowed, _ := limiter.Allow("client"); !allowed {
+			t.Fatal("disabled limiter should allow everything")
+		}
+	}
+}
+
+func TestLimiter_Stats(t *testing.T) {
+	t.Parallel()
+
+	limiter := New("joins", Limit{Rate: 1, Burst: 2})
+	for i := 0; i < 5; i++ {
+		limiter.Allow("client")
+	}
+	limiter.Allow("other")
+
+	stats := limiter.Stats()
+	if stats.Name != "joins" {
+		t.Errorf("expected name joins, got %s", stats.Name)
+	}
+	if stats.Allowed != 3 {
+		t.Errorf("expected 3 allowed, got %d", stats.Allowed)
+	}
+	if stats.Throttled != 3 {
+		t.Errorf("expected 3 throttled, got %d", stats.Throttled)
+	}
+	if stats.Keys != 2 {
+		t.Errorf("expected 2 keys, got %d", stats.Keys)
+	}
+}
+
+func TestLimiter_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	limiter := New("test", Limit{Rate: 0.001, Burst: 10})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowedCount := 0
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if allowed, _ := limiter.Allow("client"); allowed {
+				mu.Lock()
+				allowedCount++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowedCount != 10 {
+		t.Errorf("expected exactly 10 allowed requests, got %d", allowedCount)
+	}
+}
+
+func TestPerMinute(t *testing.T) {
+	t.Parallel()
+
+	limit := PerMinute(30, 5)
+	if limit.Rate != 0.5 {
+		t.Errorf("expected rate 0.5/s, got %v", limit.Rate)
+	}
+	if limit.Burst != 5 {
+		t.Errorf("expected burst 5, got %d", limit.Burst)
+	}
+}
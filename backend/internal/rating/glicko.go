@@ -0,0 +1,182 @@
+// Package rating implements Glicko-2 skill ratings, adapted to team games in
+// which every player either wins or loses with their side.
+//
+// Each game is its own rating period. A player is rated as if they had played
+// one match against a composite opponent: the average of the players who
+// finished on the other side. This is the composite opponent update described
+// by Weng and Lin, and works for uneven sides such as a lone tanner.
+package rating
+
+import "math"
+
+// Defaults for unrated players, from Glickman's Glicko-2 paper.
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+)
+
+// tau constrains how quickly volatility changes. Glickman suggests 0.3–1.2;
+// lower suits games with more luck.
+const tau = 0.5
+
+// scale converts between the Glicko and Glicko-2 scales.
+const scale = 173.7178
+
+// convergence is the precision of the volatility iteration.
+const convergence = 0.000001
+
+// Rating is a player's estimated skill.
+type Rating struct {
+	Rating     float64 `json:"rating"`
+	Deviation  float64 `json:"deviation"` // Uncertainty: the true skill is within ±2 deviations with 95% confidence
+	Volatility float64 `json:"volatility"`
+	Games      int     `json:"games"`
+}
+
+// New returns the rating of a player who has not played yet.
+func New() Rating {
+	return Rating{
+		Rating:     DefaultRating,
+		Deviation:  DefaultDeviation,
+		Volatility: DefaultVolatility,
+	}
+}
+
+// Conservative returns a skill estimate the player very likely exceeds,
+// for ranking: new players with few games rank below proven ones.
+func (r Rating) Conservative() float64 {
+	return r.Rating - 2*r.Deviation
+}
+
+// Participant is a player in a finished game.
+type Participant struct {
+	Rating Rating
+	Won    bool
+}
+
+// UpdateGame returns every participant's new rating after a game, in the
+// same order. Ratings are unchanged if nobody won or nobody lost.
+func UpdateGame(participants []Participant) []Rating {
+	var winners, losers []Rating
+	for _, p := range participants {
+		if p.Won {
+			winners = append(winners, p.Rating)
+		} else {
+			losers = append(losers, p.Rating)
+		}
+	}
+
+	updated := make([]Rating, len(participants))
+	for i, p := range participants {
+		if len(winners) == 0 || len(losers) == 0 {
+			updated[i] = p.Rating
+			continue
+		}
+
+		if p.Won {
+			updated[i] = update(p.Rating, []match{{opponent: composite(losers), score: 1}})
+		} else {
+			updated[i] = update(p.Rating, []match{{opponent: composite(winners), score: 0}})
+		}
+	}
+	return updated
+}
+
+// composite averages the ratings of a side into a single opponent. The
+// deviation is the root mean square, so uncertain players keep the opponent
+// uncertain.
+func composite(ratings []Rating) Rating {
+	var sum, sumSquares float64
+	for _, r := range ratings {
+		sum += r.Rating
+		sumSquares += r.Deviation * r.Deviation
+	}
+	n := float64(len(ratings))
+	return Rating{
+		Rating:    sum / n,
+		Deviation: math.Sqrt(sumSquares / n),
+	}
+}
+
+// match is a result against one opponent, scored 1 for a win and 0 for a
+// loss.
+type match struct {
+	opponent Rating
+	score    float64
+}
+
+// update applies one Glicko-2 rating period with the given matches.
+func update(r Rating, matches []match) Rating {
+	mu := (r.Rating - DefaultRating) / scale
+	phi := r.Deviation / scale
+	sigma := r.Volatility
+	if sigma <= 0 {
+		sigma = DefaultVolatility
+	}
+
+	var vInverse, improvement float64
+	for _, m := range matches {
+		muJ := (m.opponent.Rating - DefaultRating) / scale
+		gJ := g(m.opponent.Deviation / scale)
+		e := 1 / (1 + math.Exp(-gJ*(mu-muJ)))
+		vInverse += gJ * gJ * e * (1 - e)
+		improvement += gJ * (m.score - e)
+	}
+	v := 1 / vInverse
+	delta := v * improvement
+
+	sigma = newVolatility(phi, sigma, v, delta)
+
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*improvement
+
+	return Rating{
+		Rating:     newMu*scale + DefaultRating,
+		Deviation:  math.Min(newPhi*scale, DefaultDeviation),
+		Volatility: sigma,
+		Games:      r.Games + 1,
+	}
+}
+
+// g reduces the impact of a match against an uncertain opponent.
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// newVolatility finds the new volatility with the Illinois algorithm, step 5
+// of the Glicko-2 paper.
+func newVolatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(tau*tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
@@ -0,0 +1,135 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUpdate_GlickmanExample(t *testing.T) {
+	t.Parallel()
+
+	// The worked example from Glickman's "Example of the Glicko-2 system"
+	player := Rating{Rating: 1500, Deviation: 200, Volatility: 0.06}
+	got := update(player, []match{
+		{opponent: Rating{Rating: 1400, Deviation: 30}, score: 1},
+		{opponent: Rating{Rating: 1550, Deviation: 100}, score: 0},
+		{opponent: Rating{Rating: 1700, Deviation: 300}, score: 0},
+	})
+
+	tests := []struct {
+		name      string
+		got, want float64
+		tolerance float64
+	}{
+		{"rating", got.Rating, 1464.06, 0.01},
+		{"deviation", got.Deviation, 151.52, 0.01},
+		{"volatility", got.Volatility, 0.05999, 0.00001},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > tt.tolerance {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestUpdateGame(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		participants []Participant
+		check        func(t *testing.T, before []Participant, after []Rating)
+	}{
+		{
+			name: "winners gain and losers lose",
+			participants: []Participant{
+				{Rating: New(), Won: true},
+				{Rating: New(), Won: true},
+				{Rating: New()},
+				{Rating: New()},
+			},
+			check: func(t *testing.T, before []Participant, after []Rating) {
+				for i, p := range before {
+					if p.Won && after[i].Rating <= DefaultRating {
+						t.Errorf("winner %d rating %v, want above %v", i, after[i].Rating, DefaultRating)
+					}
+					if !p.Won && after[i].Rating >= DefaultRating {
+						t.Errorf("loser %d rating %v, want below %v", i, after[i].Rating, DefaultRating)
+					}
+					if after[i].Deviation >= DefaultDeviation || after[i].Games != 1 {
+						t.Errorf("player %d = %+v, want less uncertain after a game", i, after[i])
+					}
+				}
+			},
+		},
+		{
+			name: "upsets move ratings further",
+			participants: []Participant{
+				{Rating: Rating{Rating: 1300, Deviation: 80, Volatility: DefaultVolatility}, Won: true},
+				{Rating: Rating{Rating: 1700, Deviation: 80, Volatility: DefaultVolatility}},
+				{Rating: Rating{Rating: 1700, Deviation: 80, Volatility: DefaultVolatility}, Won: true},
+				{Rating: Rating{Rating: 1300, Deviation: 80, Volatility: DefaultVolatility}},
+			},
+			check: func(t *testing.T, before []Participant, after []Rating) {
+				upset := after[0].Rating - 1300
+				expected := after[2].Rating - 1700
+				if upset <= expected {
+					t.Errorf("upset gain %v, want more than expected win gain %v", upset, expected)
+				}
+			},
+		},
+		{
+			name: "lone winner against a table",
+			participants: []Participant{
+				{Rating: New(), Won: true},
+				{Rating: New()},
+				{Rating: New()},
+				{Rating: New()},
+				{Rating: New()},
+			},
+			check: func(t *testing.T, before []Participant, after []Rating) {
+				for i := 2; i < len(after); i++ {
+					if after[i] != after[1] {
+						t.Errorf("loser %d = %+v, want the same as loser 1 %+v", i, after[i], after[1])
+					}
+				}
+			},
+		},
+		{
+			name: "unchanged without a loser",
+			participants: []Participant{
+				{Rating: New(), Won: true},
+				{Rating: New(), Won: true},
+			},
+			check: func(t *testing.T, before []Participant, after []Rating) {
+				for i, p := range before {
+					if after[i] != p.Rating {
+						t.Errorf("player %d = %+v, want unchanged", i, after[i])
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			after := UpdateGame(tt.participants)
+			if len(after) != len(tt.participants) {
+				t.Fatalf("got %d ratings, want %d", len(after), len(tt.participants))
+			}
+			tt.check(t, tt.participants, after)
+		})
+	}
+}
+
+func TestRating_Conservative(t *testing.T) {
+	t.Parallel()
+
+	proven := Rating{Rating: 1600, Deviation: 50}
+	lucky := Rating{Rating: 1750, Deviation: 200}
+	if proven.Conservative() <= lucky.Conservative() {
+		t.Errorf("expected a proven rating to rank above an uncertain higher one")
+	}
+}
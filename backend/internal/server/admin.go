@@ -0,0 +1,354 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// AdminOnly wraps a handler so it requires the admin bearer token.
+// When no admin token is configured the admin API does not exist.
+func (s *Server) AdminOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.options.AdminToken == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(s.options.AdminToken)) != 1 {
+			slog.Warn("rejected admin request", "path", r.URL.Path, "ip", clientIP(r, s.options.TrustProxyHeaders))
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// AdminRoomSummary describes a room in the admin room list.
+type AdminRoomSummary struct {
+	RoomCode         string          `json:"roomCode"`
+	Status           core.RoomStatus `json:"status"`
+	GameType         string          `json:"gameType"`
+	Players          int             `json:"players"`
+	ConnectedPlayers int             `json:"connectedPlayers"`
+	MaxPlayers       int             `json:"maxPlayers"`
+	Events           int             `json:"events"`
+	CreatedAt        time.Time       `json:"createdAt"`
+	LastActivityAt   time.Time       `json:"lastActivityAt"`
+}
+
+// AdminRoomDetail is the full, unfiltered view of a room.
+type AdminRoomDetail struct {
+	AdminRoomSummary
+	HostID string               `json:"hostId"`
+	Room   core.RoomState       `json:"room"`
+	Events []core.EventRecord   `json:"eventLog"`
+	Game   *core.GameInspection `json:"game"`
+}
+
+// AdminNoticeRequest is the payload for broadcasting a notice.
+type AdminNoticeRequest struct {
+	Level    string `json:"level,omitempty"` // Defaults to "info"
+	Message  string `json:"message"`
+	RoomCode string `json:"roomCode,omitempty"` // Empty = every room
+}
+
+// AdminNoticeResponse reports how many connections the notice was sent to.
+type AdminNoticeResponse struct {
+	Recipients int `json:"recipients"`
+}
+
+// summarizeRoom builds the admin summary of a room.
+func summarizeRoom(room *core.Room) AdminRoomSummary {
+	state := room.GetState()
+
+	connected := 0
+	for _, player := range state.Players {
+		if player.Connected {
+			connected++
+		}
+	}
+
+	status, createdAt, _ := room.GetCleanupInfo()
+
+	return AdminRoomSummary{
+		RoomCode:         room.ID,
+		Status:           status,
+		GameType:         room.GameType,
+		Players:          len(state.Players),
+		ConnectedPlayers: connected,
+		MaxPlayers:       state.MaxPlayers,
+		Events:           room.GetEventLogLength(),
+		CreatedAt:        createdAt,
+		LastActivityAt:   room.LastActivity(),
+	}
+}
+
+// HandleAdminListRooms lists every room, most recently active first.
+func (s *Server) HandleAdminListRooms(w http.ResponseWriter, r *http.Request) {
+	rooms, err := s.store.ListRooms()
+	if err != nil {
+		slog.Error("failed to list rooms", "error", err)
+		http.Error(w, "Failed to list rooms", http.StatusInternalServerError)
+		return
+	}
+
+	summaries := make([]AdminRoomSummary, 0, len(rooms))
+	for _, room := range rooms {
+		summaries = append(summaries, summarizeRoom(room))
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].LastActivityAt.After(summaries[j].LastActivityAt)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summaries)
+}
+
+// HandleAdminGetRoom returns a room's unfiltered event log and game state.
+func (s *Server) HandleAdminGetRoom(w http.ResponseWriter, r *http.Request) {
+	room, err := s.store.GetRoom(r.PathValue("code"))
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	events := room.GetAllEvents()
+	records := make([]core.EventRecord, len(events))
+	for i, event := range events {
+		records[i] = core.NewEventRecord(event)
+	}
+
+	state := room.GetState()
+	detail := AdminRoomDetail{
+		AdminRoomSummary: summarizeRoom(room),
+		HostID:           state.HostID,
+		Room:             state,
+		Events:           records,
+		Game:             room.InspectGame(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(detail)
+}
+
+// HandleAdminResetRoom forces a room back to the lobby.
+func (s *Server) HandleAdminResetRoom(w http.ResponseWriter, r *http.Request) {
+	roomCode := r.PathValue("code")
+
+	room, err := s.store.GetRoom(roomCode)
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	if err := room.ResetGame(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	notice, _ := NewNoticeMessage(NoticeLevelWarning, "The game was reset by an administrator")
+	s.connMgr.BroadcastMessage(roomCode, notice)
+	s.connMgr.BroadcastRoomState(roomCode)
+	s.connMgr.RefreshChat(room)
+
+	slog.Info("admin reset room", "roomCode", roomCode)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "reset"})
+}
+
+// HandleAdminCloseRoom disconnects everyone in a room and deletes it.
+func (s *Server) HandleAdminCloseRoom(w http.ResponseWriter, r *http.Request) {
+	roomCode := r.PathValue("code")
+
+	if err := s.store.DeleteRoom(roomCode); err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+	s.codes.Release(roomCode)
+	s.bots.RemoveRoom(roomCode)
+
+	notice, _ := NewNoticeMessage(NoticeLevelWarning, "This room was closed by an administrator")
+	s.connMgr.CloseRoom(roomCode, notice, "room closed")
+
+	slog.Info("admin closed room", "roomCode", roomCode)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "closed"})
+}
+
+// HandleAdminNotice broadcasts a maintenance notice to connected clients.
+func (s *Server) HandleAdminNotice(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 64*1024)
+
+	var req AdminNoticeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Request too large or malformed", http.StatusBadRequest)
+		return
+	}
+
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		http.Error(w, "Message required", http.StatusBadRequest)
+		return
+	}
+
+	switch req.Level {
+	case "":
+		req.Level = NoticeLevelInfo
+	case NoticeLevelInfo, NoticeLevelWarning:
+	default:
+		http.Error(w, "Invalid notice level", http.StatusBadRequest)
+		return
+	}
+
+	if req.RoomCode != "" {
+		if _, err := s.store.GetRoom(req.RoomCode); err != nil {
+			http.Error(w, "Room not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	notice, err := NewNoticeMessage(req.Level, req.Message)
+	if err != nil {
+		http.Error(w, "Failed to build notice", http.StatusInternalServerError)
+		return
+	}
+	recipients := s.connMgr.BroadcastMessage(req.RoomCode, notice)
+
+	slog.Info("admin broadcast notice", "roomCode", req.RoomCode, "level", req.Level, "recipients", recipients)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(AdminNoticeResponse{Recipients: recipients})
+}
+
+// AdminEventsResponse is a page of a room's unfiltered event log.
+type AdminEventsResponse struct {
+	Events []core.EventRecord `json:"events"`
+	Next   int                `json:"next"` // Pass as ?since= to get only newer events
+}
+
+// HandleAdminRoomEvents returns a room's unfiltered events starting at the
+// ?since= index, for tailing a room by polling. If the log is shorter than
+// since (the room was reset), events are returned from the start.
+func (s *Server) HandleAdminRoomEvents(w http.ResponseWriter, r *http.Request) {
+	room, err := s.store.GetRoom(r.PathValue("code"))
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	since := 0
+	if value := r.URL.Query().Get("since"); value != "" {
+		since, err = strconv.Atoi(value)
+		if err != nil || since < 0 {
+			http.Error(w, "Invalid since", http.StatusBadRequest)
+			return
+		}
+	}
+
+	events := room.GetAllEvents()
+	if since > len(events) {
+		since = 0
+	}
+
+	resp := AdminEventsResponse{
+		Events: make([]core.EventRecord, 0, len(events)-since),
+		Next:   len(events),
+	}
+	for _, event := range events[since:] {
+		resp.Events = append(resp.Events, core.NewEventRecord(event))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleAdminExportRoom returns a room snapshot that can be imported later.
+// The snapshot contains session tokens, which are signed with the session key:
+// players can only reconnect after importing on a server with the same key.
+func (s *Server) HandleAdminExportRoom(w http.ResponseWriter, r *http.Request) {
+	room, err := s.store.GetRoom(r.PathValue("code"))
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	snapshot, err := room.Snapshot()
+	if err != nil {
+		slog.Error("failed to export room", "roomCode", room.ID, "error", err)
+		http.Error(w, "Failed to export room", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+room.ID+`.json"`)
+	json.NewEncoder(w).Encode(snapshot)
+}
+
+// HandleAdminImportRoom restores a room from a snapshot. An existing room with
+// the same code is only replaced when ?replace=true is given; its clients are
+// disconnected so they reconnect into the imported room. Like rooms restored
+// at startup, the imported room's bots resume playing.
+func (s *Server) HandleAdminImportRoom(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 8*1024*1024)
+
+	var snapshot core.RoomSnapshot
+	if err := json.NewDecoder(r.Body).Decode(&snapshot); err != nil {
+		http.Error(w, "Request too large or malformed", http.StatusBadRequest)
+		return
+	}
+
+	if !s.gameRegistry.IsRegistered(snapshot.GameType) {
+		http.Error(w, "Unknown game type", http.StatusBadRequest)
+		return
+	}
+
+	room, err := core.RestoreRoom(snapshot, s.gameRegistry.CreateGame)
+	if err != nil {
+		http.Error(w, "Invalid snapshot: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	room.SetObserver(s.metrics)
+
+	// The code must be one players can join with here
+	if err := s.codes.Take(room.ID); err != nil {
+		http.Error(w, "Invalid snapshot: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.store.GetRoom(room.ID); err == nil {
+		if r.URL.Query().Get("replace") != "true" {
+			http.Error(w, "Room already exists", http.StatusConflict)
+			return
+		}
+
+		notice, _ := NewNoticeMessage(NoticeLevelWarning, "This room is being restored, please reconnect")
+		s.connMgr.CloseRoom(room.ID, notice, "room replaced")
+		s.store.DeleteRoom(room.ID)
+		s.bots.RemoveRoom(room.ID)
+	}
+
+	if err := s.store.CreateRoom(room); err != nil {
+		slog.Error("failed to import room", "roomCode", room.ID, "error", err)
+		http.Error(w, "Failed to import room", http.StatusInternalServerError)
+		return
+	}
+	s.bots.Notify(room)
+
+	slog.Info("admin imported room", "roomCode", room.ID, "gameType", room.GameType, "events", len(snapshot.EventLog))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summarizeRoom(room))
+}
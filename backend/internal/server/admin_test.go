@@ -0,0 +1,452 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+const testAdminToken = "test-admin-token"
+
+// newAdminServer creates a server with the admin API enabled.
+// testSessionKey is shared by admin test servers, as by replicas of one
+// deployment, so session tokens stay valid across export and import.
+var testSessionKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newAdminServer() *Server {
+	options := DefaultOptions()
+	options.AdminToken = testAdminToken
+	options.SessionKey = testSessionKey
+	return NewServerWithOptions(store.NewMemoryStore(), options)
+}
+
+// adminRequest builds a request carrying the admin bearer token.
+func adminRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	return req
+}
+
+func TestAdminOnly(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		adminToken     string
+		authorization  string
+		wantStatusCode int
+	}{
+		{name: "disabled without admin token", adminToken: "", authorization: "Bearer ", wantStatusCode: http.StatusNotFound},
+		{name: "missing authorization", adminToken: testAdminToken, wantStatusCode: http.StatusUnauthorized},
+		{name: "wrong token", adminToken: testAdminToken, authorization: "Bearer nope", wantStatusCode: http.StatusUnauthorized},
+		{name: "wrong scheme", adminToken: testAdminToken, authorization: "Basic " + testAdminToken, wantStatusCode: http.StatusUnauthorized},
+		{name: "valid token", adminToken: testAdminToken, authorization: "Bearer " + testAdminToken, wantStatusCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.AdminToken = tt.adminToken
+			server := NewServerWithOptions(store.NewMemoryStore(), options)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/rooms", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			server.AdminOnly(server.HandleAdminListRooms)(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+}
+
+func TestHandleAdminListRooms(t *testing.T) {
+	t.Parallel()
+
+	server := newAdminServer()
+	room, _ := setupWerewolfGame(t, server)
+
+	idle := core.NewRoom("IDLE01", "avalon", core.NewPlayer("Idle"), 10)
+	idle.LastActivityAt = time.Now().Add(-time.Hour)
+	idle.Players[idle.HostID].LastSeenAt = time.Now().Add(-time.Hour)
+	server.store.CreateRoom(idle)
+
+	rec := httptest.NewRecorder()
+	server.HandleAdminListRooms(rec, adminRequest(http.MethodGet, "/api/admin/rooms", ""))
+
+	var summaries []AdminRoomSummary
+	if err := json.NewDecoder(rec.Body).Decode(&summaries); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(summaries) != 2 {
+		t.Fatalf("expected 2 rooms, got %d", len(summaries))
+	}
+	if summaries[0].RoomCode != room.ID {
+		t.Errorf("expected most recently active room first, got %s", summaries[0].RoomCode)
+	}
+	if summaries[0].Status != core.RoomStatusPlaying || summaries[0].GameType != "werewolf" || summaries[0].Players != 3 {
+		t.Errorf("unexpected summary: %+v", summaries[0])
+	}
+	if summaries[0].Events == 0 {
+		t.Error("expected event count for started game")
+	}
+}
+
+func TestHandleAdminGetRoom(t *testing.T) {
+	t.Parallel()
+
+	server := newAdminServer()
+	room, _ := setupWerewolfGame(t, server)
+
+	req := adminRequest(http.MethodGet, "/api/admin/rooms/"+room.ID, "")
+	req.SetPathValue("code", room.ID)
+	rec := httptest.NewRecorder()
+	server.HandleAdminGetRoom(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var detail struct {
+		EventLog []struct {
+			Type       string               `json:"type"`
+			Visibility core.EventVisibility `json:"visibility"`
+		} `json:"eventLog"`
+		Game struct {
+			Phase        core.GamePhase             `json:"phase"`
+			PlayerStates map[string]json.RawMessage `json:"playerStates"`
+		} `json:"game"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&detail); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	private := 0
+	for _, event := range detail.EventLog {
+		if !event.Visibility.Public {
+			private++
+		}
+	}
+	if private == 0 {
+		t.Error("expected private events (role assignments) in the unfiltered log")
+	}
+	if detail.Game.Phase.Name == "" {
+		t.Error("expected game phase")
+	}
+	if len(detail.Game.PlayerStates) != 3 {
+		t.Errorf("expected state for 3 players, got %d", len(detail.Game.PlayerStates))
+	}
+
+	req = adminRequest(http.MethodGet, "/api/admin/rooms/NOPE00", "")
+	req.SetPathValue("code", "NOPE00")
+	rec = httptest.NewRecorder()
+	server.HandleAdminGetRoom(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown room, got %d", rec.Code)
+	}
+}
+
+func TestHandleAdminResetRoom(t *testing.T) {
+	t.Parallel()
+
+	server := newAdminServer()
+	room, _ := setupWerewolfGame(t, server)
+
+	reset := func() int {
+		req := adminRequest(http.MethodPost, "/api/admin/rooms/"+room.ID+"/reset", "")
+		req.SetPathValue("code", room.ID)
+		rec := httptest.NewRecorder()
+		server.HandleAdminResetRoom(rec, req)
+		return rec.Code
+	}
+
+	if code := reset(); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if room.GetState().Status != core.RoomStatusWaiting {
+		t.Error("expected room back in the lobby")
+	}
+	if code := reset(); code != http.StatusBadRequest {
+		t.Errorf("expected 400 when no game is running, got %d", code)
+	}
+}
+
+func TestHandleAdminNotice(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		body           string
+		wantStatusCode int
+	}{
+		{name: "all rooms", body: `{"message":"Maintenance in 5 minutes"}`, wantStatusCode: http.StatusOK},
+		{name: "warning level", body: `{"level":"warning","message":"Restarting"}`, wantStatusCode: http.StatusOK},
+		{name: "single room", body: `{"message":"Hi","roomCode":"SSEABC"}`, wantStatusCode: http.StatusOK},
+		{name: "unknown room", body: `{"message":"Hi","roomCode":"NOPE00"}`, wantStatusCode: http.StatusNotFound},
+		{name: "empty message", body: `{"message":"  "}`, wantStatusCode: http.StatusBadRequest},
+		{name: "invalid level", body: `{"level":"panic","message":"Hi"}`, wantStatusCode: http.StatusBadRequest},
+		{name: "malformed", body: `{`, wantStatusCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newAdminServer()
+			setupWerewolfGame(t, server)
+
+			rec := httptest.NewRecorder()
+			server.HandleAdminNotice(rec, adminRequest(http.MethodPost, "/api/admin/notice", tt.body))
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleAdminCloseRoom_DisconnectsClients(t *testing.T) {
+	t.Parallel()
+
+	server := newAdminServer()
+	room, tokens := setupWerewolfGame(t, server)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/rooms/{code}/ws", server.HandleWebSocket)
+	mux.HandleFunc("DELETE /api/admin/rooms/{code}", server.AdminOnly(server.HandleAdminCloseRoom))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/rooms/" + room.ID + "/ws"
+	conn, _, err := websocket.Dial(ctx, wsURL, &websocket.DialOptions{
+		HTTPHeader: http.Header{"Origin": []string{"http://localhost:5173"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	authPayload, _ := json.Marshal(AuthenticatePayload{SessionToken: tokens[room.HostID], ProtocolVersion: CurrentProtocolVersion})
+	wsjson.Write(ctx, conn, ClientMessage{Type: ClientMsgAuthenticate, Payload: authPayload})
+
+	var msg ServerMessage
+	if err := wsjson.Read(ctx, conn, &msg); err != nil || msg.Type != ServerMsgAuthenticated {
+		t.Fatalf("expected authenticated message, got %v (%v)", msg.Type, err)
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodDelete, ts.URL+"/api/admin/rooms/"+room.ID, nil)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to close room: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	if _, err := server.store.GetRoom(room.ID); err == nil {
+		t.Error("expected room to be deleted")
+	}
+
+	// The notice arrives after the initial history, then the socket closes
+	for {
+		msg = ServerMessage{}
+		err := wsjson.Read(ctx, conn, &msg)
+		if err != nil {
+			t.Fatalf("expected notice before close, got %v", err)
+		}
+		if msg.Type == ServerMsgNotice {
+			break
+		}
+	}
+
+	err = wsjson.Read(ctx, conn, &msg)
+	if status := websocket.CloseStatus(err); status != websocket.StatusNormalClosure {
+		t.Errorf("expected normal closure after notice, got %v", err)
+	}
+}
+
+func TestHandleAdminExportImportRoom(t *testing.T) {
+	t.Parallel()
+
+	source := newAdminServer()
+	room, tokens := setupWerewolfGame(t, source)
+
+	req := adminRequest(http.MethodGet, "/api/admin/rooms/"+room.ID+"/export", "")
+	req.SetPathValue("code", room.ID)
+	rec := httptest.NewRecorder()
+	source.HandleAdminExportRoom(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("export: expected 200, got %d", rec.Code)
+	}
+	exported := rec.Body.String()
+
+	target := newAdminServer()
+	importRoom := func(query string) int {
+		rec := httptest.NewRecorder()
+		target.HandleAdminImportRoom(rec, adminRequest(http.MethodPost, "/api/admin/rooms/import"+query, exported))
+		return rec.Code
+	}
+
+	if code := importRoom(""); code != http.StatusOK {
+		t.Fatalf("import: expected 200, got %d", code)
+	}
+	if code := importRoom(""); code != http.StatusConflict {
+		t.Errorf("second import: expected 409, got %d", code)
+	}
+	if code := importRoom("?replace=true"); code != http.StatusOK {
+		t.Errorf("replace import: expected 200, got %d", code)
+	}
+
+	imported, err := target.store.GetRoom(room.ID)
+	if err != nil {
+		t.Fatalf("expected imported room: %v", err)
+	}
+	if imported.GetEventLogLength() != room.GetEventLogLength() {
+		t.Errorf("expected %d events, got %d", room.GetEventLogLength(), imported.GetEventLogLength())
+	}
+
+	// Players can keep playing in the imported room with their old tokens
+	action := httptest.NewRequest(http.MethodPost, "/api/rooms/"+room.ID+"/actions",
+		strings.NewReader(`{"action":{"type":"acknowledge_role","payload":{}}}`))
+	action.SetPathValue("code", room.ID)
+	action.Header.Set("X-Session-Token", tokens[room.HostID])
+	rec = httptest.NewRecorder()
+	target.HandleAction(rec, action)
+	if rec.Code != http.StatusOK {
+		t.Errorf("action in imported room: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleAdminImportRoom_ResumesBots(t *testing.T) {
+	t.Parallel()
+
+	source := newAdminServer()
+	room, host, _ := setupLobby(t, source)
+	if rec := addBotRequest(source, room.ID, host.SessionToken, `{}`); rec.Code != http.StatusOK {
+		t.Fatalf("add bot: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	snapshot, _ := room.Snapshot()
+
+	target := newAdminServer()
+	importRoom := func(snapshot core.RoomSnapshot, query string) int {
+		body, _ := json.Marshal(snapshot)
+		rec := httptest.NewRecorder()
+		target.HandleAdminImportRoom(rec, adminRequest(http.MethodPost, "/api/admin/rooms/import"+query, string(body)))
+		return rec.Code
+	}
+
+	if code := importRoom(snapshot, ""); code != http.StatusOK {
+		t.Fatalf("import: expected 200, got %d", code)
+	}
+	if count := target.bots.Count(); count != 1 {
+		t.Errorf("expected the imported bot to resume, got %d bots", count)
+	}
+	if code := importRoom(snapshot, "?replace=true"); code != http.StatusOK {
+		t.Fatalf("replace import: expected 200, got %d", code)
+	}
+	if count := target.bots.Count(); count != 1 {
+		t.Errorf("expected only the replacing room's bot, got %d bots", count)
+	}
+
+	// Players couldn't type a lowercase code, since joining uppercases it
+	snapshot.ID = "lobby1"
+	if code := importRoom(snapshot, ""); code != http.StatusBadRequest {
+		t.Errorf("import with invalid code: expected 400, got %d", code)
+	}
+}
+
+func TestHandleAdminImportRoom_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "malformed", body: `{`},
+		{name: "unknown game", body: `{"version":1,"id":"ABC123","gameType":"chess"}`},
+		{name: "unsupported version", body: `{"version":9,"id":"ABC123","gameType":"werewolf"}`},
+		{name: "host missing", body: `{"version":1,"id":"ABC123","gameType":"werewolf","hostId":"ghost"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newAdminServer()
+			rec := httptest.NewRecorder()
+			server.HandleAdminImportRoom(rec, adminRequest(http.MethodPost, "/api/admin/rooms/import", tt.body))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestHandleAdminRoomEvents(t *testing.T) {
+	t.Parallel()
+
+	server := newAdminServer()
+	room, _ := setupWerewolfGame(t, server)
+	total := room.GetEventLogLength()
+
+	get := func(since string) (int, AdminEventsResponse) {
+		req := adminRequest(http.MethodGet, "/api/admin/rooms/"+room.ID+"/events?since="+since, "")
+		req.SetPathValue("code", room.ID)
+		rec := httptest.NewRecorder()
+		server.HandleAdminRoomEvents(rec, req)
+		var page AdminEventsResponse
+		json.NewDecoder(rec.Body).Decode(&page)
+		return rec.Code, page
+	}
+
+	tests := []struct {
+		name       string
+		since      string
+		wantStatus int
+		wantEvents int
+	}{
+		{name: "from start", since: "0", wantStatus: http.StatusOK, wantEvents: total},
+		{name: "caught up", since: strconv.Itoa(total), wantStatus: http.StatusOK, wantEvents: 0},
+		{name: "past the end after reset", since: strconv.Itoa(total + 5), wantStatus: http.StatusOK, wantEvents: total},
+		{name: "invalid", since: "abc", wantStatus: http.StatusBadRequest},
+		{name: "negative", since: "-1", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, page := get(tt.since)
+			if code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, code)
+			}
+			if code != http.StatusOK {
+				return
+			}
+			if len(page.Events) != tt.wantEvents {
+				t.Errorf("expected %d events, got %d", tt.wantEvents, len(page.Events))
+			}
+			if page.Next != total {
+				t.Errorf("expected next %d, got %d", total, page.Next)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/KonradHerman/roundtable/internal/bot"
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// AddBotRequest is the payload for adding a bot seat. Every field is
+// optional.
+type AddBotRequest struct {
+	DisplayName string `json:"displayName,omitempty"` // Defaults to "Bot N"
+	Strategy    string `json:"strategy,omitempty"`    // Defaults to "random"
+	MinThinkMs  *int   `json:"minThinkMs,omitempty"`  // Defaults to core.DefaultBotMinThinkMs
+	MaxThinkMs  *int   `json:"maxThinkMs,omitempty"`  // Defaults to core.DefaultBotMaxThinkMs
+}
+
+// AddBotResponse describes the bot seat that was added.
+type AddBotResponse struct {
+	PlayerID    string           `json:"playerId"`
+	DisplayName string           `json:"displayName"`
+	Bot         core.BotSettings `json:"bot"`
+}
+
+// botSettings validates a request's strategy and think time for a game type.
+func botSettings(req AddBotRequest, gameType string) (core.BotSettings, error) {
+	settings := core.BotSettings{
+		Strategy:   req.Strategy,
+		MinThinkMs: core.DefaultBotMinThinkMs,
+		MaxThinkMs: core.DefaultBotMaxThinkMs,
+	}
+	if settings.Strategy == "" {
+		settings.Strategy = bot.StrategyRandom
+	}
+	if req.MinThinkMs != nil {
+		settings.MinThinkMs = *req.MinThinkMs
+	}
+	if req.MaxThinkMs != nil {
+		settings.MaxThinkMs = *req.MaxThinkMs
+	}
+
+	if !bot.HasStrategy(gameType, settings.Strategy) {
+		return settings, fmt.Errorf("unknown strategy %q, available: %s",
+			settings.Strategy, strings.Join(bot.Strategies(gameType), ", "))
+	}
+	if settings.MinThinkMs < 0 || settings.MinThinkMs > settings.MaxThinkMs || settings.MaxThinkMs > core.MaxBotThinkMs {
+		return settings, fmt.Errorf("think time must satisfy 0 <= minThinkMs <= maxThinkMs <= %d", core.MaxBotThinkMs)
+	}
+	return settings, nil
+}
+
+// botName picks a display name for a new bot in a room.
+func botName(room *core.Room) string {
+	taken := make(map[string]bool)
+	for _, player := range room.GetPlayers() {
+		taken[player.DisplayName] = true
+	}
+
+	for n := 1; ; n++ {
+		if name := fmt.Sprintf("Bot %d", n); !taken[name] {
+			return name
+		}
+	}
+}
+
+// HandleAddBot lets the host fill a lobby seat with a bot. The host removes
+// bots like any other player.
+// Expected format: POST /api/rooms/{code}/bots
+func (s *Server) HandleAddBot(w http.ResponseWriter, r *http.Request) {
+	// Limit request body to 1MB
+	r.Body = http.MaxBytesReader(w, r.Body, 1*1024*1024)
+
+	roomCode := r.PathValue("code")
+
+	token := r.Header.Get("X-Session-Token")
+	if token == "" {
+		http.Error(w, "Session token required", http.StatusUnauthorized)
+		return
+	}
+
+	var req AddBotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Request too large or malformed", http.StatusBadRequest)
+		return
+	}
+
+	room, err := s.store.GetRoom(roomCode)
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	host, err := s.connMgr.authenticatePlayer(room, token)
+	if err != nil {
+		http.Error(w, "Invalid session token", http.StatusUnauthorized)
+		return
+	}
+	if !room.IsHost(host.ID) {
+		http.Error(w, "Only the host can add bots", http.StatusForbidden)
+		return
+	}
+
+	settings, err := botSettings(req, room.GameType)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	displayName := botName(room)
+	if req.DisplayName != "" {
+		if displayName, err = validateDisplayName(req.DisplayName); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	player := core.NewBotPlayer(displayName, settings)
+	if err := s.joinRoom(room, player); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	if err := s.store.UpdateRoom(room); err != nil {
+		slog.Error("failed to persist room", "roomCode", roomCode, "error", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(AddBotResponse{
+		PlayerID:    player.ID,
+		DisplayName: player.DisplayName,
+		Bot:         settings,
+	})
+}
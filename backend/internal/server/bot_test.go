@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+// addBotRequest asks for a bot seat over HTTP.
+func addBotRequest(s *Server, roomCode, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms/"+roomCode+"/bots", strings.NewReader(body))
+	req.SetPathValue("code", roomCode)
+	if token != "" {
+		req.Header.Set("X-Session-Token", token)
+	}
+	rec := httptest.NewRecorder()
+	s.HandleAddBot(rec, req)
+	return rec
+}
+
+func TestHandleAddBot(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room, host, player := setupLobby(t, server)
+
+	tests := []struct {
+		name       string
+		token      string
+		body       string
+		wantStatus int
+	}{
+		{"no token", "", `{}`, http.StatusUnauthorized},
+		{"not host", player.SessionToken, `{}`, http.StatusForbidden},
+		{"malformed", host.SessionToken, `{`, http.StatusBadRequest},
+		{"unknown strategy", host.SessionToken, `{"strategy": "psychic"}`, http.StatusBadRequest},
+		{"negative think time", host.SessionToken, `{"minThinkMs": -1}`, http.StatusBadRequest},
+		{"min above max", host.SessionToken, `{"minThinkMs": 5000, "maxThinkMs": 1000}`, http.StatusBadRequest},
+		{"think time too long", host.SessionToken, `{"maxThinkMs": 600000}`, http.StatusBadRequest},
+		{"invalid name", host.SessionToken, `{"displayName": "<script>"}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := addBotRequest(server, room.ID, tt.token, tt.body); rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	if len(room.GetPlayers()) != 2 {
+		t.Fatalf("expected rejected requests not to add seats, got %d players", len(room.GetPlayers()))
+	}
+
+	// Defaults
+	rec := addBotRequest(server, room.ID, host.SessionToken, `{}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected bot to be added, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var added AddBotResponse
+	json.NewDecoder(rec.Body).Decode(&added)
+	want := core.BotSettings{Strategy: "random", MinThinkMs: core.DefaultBotMinThinkMs, MaxThinkMs: core.DefaultBotMaxThinkMs}
+	if added.DisplayName != "Bot 1" || added.Bot != want {
+		t.Errorf("unexpected bot %+v", added)
+	}
+
+	bot, err := room.GetPlayer(added.PlayerID)
+	if err != nil || !bot.IsBot() {
+		t.Fatalf("expected a bot seat in the room, got %v", err)
+	}
+
+	// Bots are removed like any other player
+	req := httptest.NewRequest(http.MethodDelete, "/api/rooms/"+room.ID+"/players/"+bot.ID, nil)
+	req.SetPathValue("code", room.ID)
+	req.SetPathValue("playerId", bot.ID)
+	req.Header.Set("X-Session-Token", host.SessionToken)
+	kick := httptest.NewRecorder()
+	server.HandleKickPlayer(kick, req)
+	if kick.Code != http.StatusOK {
+		t.Fatalf("expected bot to be removed, got %d: %s", kick.Code, kick.Body.String())
+	}
+	if server.bots.Count() != 0 {
+		t.Errorf("expected the removed bot to stop, got %d bots", server.bots.Count())
+	}
+}
+
+func TestHandleAddBot_PlaysGame(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room, host, _ := setupLobby(t, server)
+
+	rec := addBotRequest(server, room.ID, host.SessionToken, `{"displayName": "Robo", "minThinkMs": 0, "maxThinkMs": 0}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected bot to be added, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var added AddBotResponse
+	json.NewDecoder(rec.Body).Decode(&added)
+
+	body := `{"config": {"roles": ["werewolf", "seer", "robber", "villager", "villager", "troublemaker"]}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms/"+room.ID+"/start", strings.NewReader(body))
+	req.SetPathValue("code", room.ID)
+	start := httptest.NewRecorder()
+	server.HandleStartGame(start, req)
+	if start.Code != http.StatusOK {
+		t.Fatalf("expected game to start, got %d: %s", start.Code, start.Body.String())
+	}
+
+	// Starting the game broadcasts events, which wakes the bot
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		acknowledged := false
+		for _, event := range room.GetAllEvents() {
+			var payload struct {
+				PlayerID string `json:"playerId"`
+			}
+			json.Unmarshal(event.Payload, &payload)
+			if event.Type == "role_acknowledged" && payload.PlayerID == added.PlayerID {
+				acknowledged = true
+			}
+		}
+		if acknowledged {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the bot to acknowledge its role")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// CapabilityChat delivers in-room chat: a chat_history message after
+// authenticating and whenever the player's channels change, then a chat
+// message for every message the player can see.
+const CapabilityChat = "chat"
+
+func init() {
+	serverCapabilities[CapabilityChat] = true
+}
+
+// allowChat applies the per-session chat limiter.
+func (cm *ConnectionManager) allowChat(playerID string) (bool, time.Duration) {
+	return allowPlayer(cm.chatLimiter, ScopeChat, playerID)
+}
+
+// processChat posts a chat message to the room and delivers it. It is shared
+// by the WebSocket and HTTP chat transports.
+func (cm *ConnectionManager) processChat(room *core.Room, playerID string, msg core.ChatMessage) error {
+	event, err := room.Chat(playerID, msg)
+	if err != nil {
+		return err
+	}
+
+	cm.broadcastChat(room, event)
+	return nil
+}
+
+// broadcastChat sends a chat message to the players who can see it and
+// negotiated the chat capability.
+func (cm *ConnectionManager) broadcastChat(room *core.Room, event core.GameEvent) {
+	chatMsg, _ := NewChatMessage(event)
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	for _, player := range room.GetPlayers() {
+		if !event.CanPlayerSee(player.ID) {
+			continue
+		}
+
+		conn, exists := cm.connections[player.ID]
+		if !exists || conn.RoomCode != room.ID || !conn.HasCapability(CapabilityChat) {
+			continue
+		}
+
+		select {
+		case conn.Send <- chatMsg:
+		default:
+			slog.Warn("failed to send chat message", "playerID", player.ID, "reason", "channel full")
+			cm.metrics.BroadcastDropped(ServerMsgChat)
+		}
+	}
+}
+
+// sendChatHistory queues a player's chat channels and history, if the client
+// negotiated the chat capability.
+func (cm *ConnectionManager) sendChatHistory(conn *Connection, room *core.Room) {
+	if !conn.HasCapability(CapabilityChat) {
+		return
+	}
+
+	historyMsg, _ := NewChatHistoryMessage(room.ChatChannels(conn.PlayerID), room.ChatForPlayer(conn.PlayerID))
+
+	select {
+	case conn.Send <- historyMsg:
+	default:
+		slog.Warn("failed to send chat history", "playerID", conn.PlayerID, "reason", "channel full")
+		cm.metrics.BroadcastDropped(ServerMsgChatHistory)
+	}
+}
+
+// RefreshChat resends chat history to everyone connected to a room, after a
+// game starting or resetting changed the team channels.
+func (cm *ConnectionManager) RefreshChat(room *core.Room) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	for _, player := range room.GetPlayers() {
+		if conn, exists := cm.connections[player.ID]; exists && conn.RoomCode == room.ID {
+			cm.sendChatHistory(conn, room)
+		}
+	}
+}
+
+// chatStatus maps a chat or reaction error to a response status.
+func chatStatus(err error) int {
+	if errors.Is(err, core.ErrMuted) {
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
+
+// HandleChat posts a chat message over plain HTTP, for SSE clients.
+// Expected format: POST /api/rooms/{code}/chat
+func (s *Server) HandleChat(w http.ResponseWriter, r *http.Request) {
+	// Limit request body to 1MB
+	r.Body = http.MaxBytesReader(w, r.Body, 1*1024*1024)
+
+	token := r.Header.Get("X-Session-Token")
+	if token == "" {
+		http.Error(w, "Session token required", http.StatusUnauthorized)
+		return
+	}
+
+	var msg core.ChatMessage
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, "Request too large or malformed", http.StatusBadRequest)
+		return
+	}
+
+	room, err := s.store.GetRoom(r.PathValue("code"))
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	player, err := s.connMgr.authenticatePlayer(room, token)
+	if err != nil {
+		http.Error(w, "Invalid session token", http.StatusUnauthorized)
+		return
+	}
+	player.UpdateLastSeen()
+
+	if allowed, retryAfter := s.connMgr.allowChat(player.ID); !allowed {
+		writeTooManyRequests(w, retryAfter)
+		return
+	}
+
+	if err := s.connMgr.processChat(room, player.ID, msg); err != nil {
+		http.Error(w, fmt.Sprintf("Chat failed: %v", err), chatStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// HandleMutePlayer lets the host mute a player's chat (POST) or unmute it
+// (DELETE).
+// Expected format: POST|DELETE /api/rooms/{code}/players/{playerId}/mute
+func (s *Server) HandleMutePlayer(w http.ResponseWriter, r *http.Request) {
+	roomCode := r.PathValue("code")
+	targetID := r.PathValue("playerId")
+	muted := r.Method != http.MethodDelete
+
+	token := r.Header.Get("X-Session-Token")
+	if token == "" {
+		http.Error(w, "Session token required", http.StatusUnauthorized)
+		return
+	}
+
+	room, err := s.store.GetRoom(roomCode)
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	host, err := s.connMgr.authenticatePlayer(room, token)
+	if err != nil {
+		http.Error(w, "Invalid session token", http.StatusUnauthorized)
+		return
+	}
+	if !room.IsHost(host.ID) {
+		http.Error(w, "Only the host can mute players", http.StatusForbidden)
+		return
+	}
+
+	if err := room.SetMuted(targetID, muted); err != nil {
+		if errors.Is(err, core.ErrPlayerNotInRoom) {
+			http.Error(w, "Player not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.store.UpdateRoom(room); err != nil {
+		slog.Error("failed to persist room", "roomCode", roomCode, "error", err)
+	}
+	s.connMgr.BroadcastRoomState(roomCode)
+
+	slog.Info("player chat muted", "playerID", targetID, "roomCode", roomCode, "muted", muted)
+
+	status := "muted"
+	if !muted {
+		status = "unmuted"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": status})
+}
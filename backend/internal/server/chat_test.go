@@ -0,0 +1,199 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/ratelimit"
+	"github.com/KonradHerman/roundtable/internal/store"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// chatRequest posts a chat message over HTTP.
+func chatRequest(s *Server, roomCode, token string, msg core.ChatMessage) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(msg)
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms/"+roomCode+"/chat", strings.NewReader(string(body)))
+	req.SetPathValue("code", roomCode)
+	req.Header.Set("X-Session-Token", token)
+	rec := httptest.NewRecorder()
+	s.HandleChat(rec, req)
+	return rec
+}
+
+// muteRequest mutes (POST) or unmutes (DELETE) a player.
+func muteRequest(s *Server, method, roomCode, playerID, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/rooms/"+roomCode+"/players/"+playerID+"/mute", nil)
+	req.SetPathValue("code", roomCode)
+	req.SetPathValue("playerId", playerID)
+	if token != "" {
+		req.Header.Set("X-Session-Token", token)
+	}
+	rec := httptest.NewRecorder()
+	s.HandleMutePlayer(rec, req)
+	return rec
+}
+
+func TestHandleWebSocket_Chat(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room, host, player := setupLobby(t, server)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/rooms/{code}/ws", server.HandleWebSocket)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/rooms/" + room.ID + "/ws"
+
+	// connect authenticates and returns the connection after the
+	// authenticated message
+	connect := func(token string, capabilities []string) *websocket.Conn {
+		conn, _, err := websocket.Dial(ctx, wsURL, &websocket.DialOptions{
+			HTTPHeader: http.Header{"Origin": []string{"http://localhost:5173"}},
+		})
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+
+		payload, _ := json.Marshal(AuthenticatePayload{
+			SessionToken:    token,
+			ProtocolVersion: CurrentProtocolVersion,
+			Capabilities:    capabilities,
+		})
+		if err := wsjson.Write(ctx, conn, ClientMessage{Type: ClientMsgAuthenticate, Payload: payload}); err != nil {
+			t.Fatalf("failed to send authenticate: %v", err)
+		}
+
+		var msg ServerMessage
+		if err := wsjson.Read(ctx, conn, &msg); err != nil || msg.Type != ServerMsgAuthenticated {
+			t.Fatalf("expected authenticated message, got %s (%v)", msg.Type, err)
+		}
+		return conn
+	}
+
+	// next reads the next message that isn't a room update
+	next := func(conn *websocket.Conn) ServerMessage {
+		for {
+			var msg ServerMessage
+			if err := wsjson.Read(ctx, conn, &msg); err != nil {
+				t.Fatalf("failed to read message: %v", err)
+			}
+			if msg.Type != ServerMsgRoomState && msg.Type != ServerMsgEvent {
+				return msg
+			}
+		}
+	}
+
+	hostConn := connect(host.SessionToken, []string{CapabilityChat})
+	defer hostConn.Close(websocket.StatusNormalClosure, "")
+
+	var history ChatHistoryPayload
+	msg := next(hostConn)
+	if msg.Type != ServerMsgChatHistory {
+		t.Fatalf("expected chat history, got %s", msg.Type)
+	}
+	json.Unmarshal(msg.Payload, &history)
+	if len(history.Messages) != 0 || len(history.Channels) != 1 || history.Channels[0] != core.ChatChannelAll {
+		t.Fatalf("unexpected initial history %+v", history)
+	}
+
+	// Chat is delivered to the sender as well
+	chatPayload, _ := json.Marshal(core.ChatMessage{Channel: core.ChatChannelAll, Text: "hello"})
+	wsjson.Write(ctx, hostConn, ClientMessage{Type: ClientMsgChat, Payload: chatPayload})
+	msg = next(hostConn)
+	if msg.Type != ServerMsgChat {
+		t.Fatalf("expected chat message, got %s", msg.Type)
+	}
+
+	// Invalid messages are refused with a chat error
+	chatPayload, _ = json.Marshal(core.ChatMessage{Channel: core.ChatChannelWhisper, To: host.ID, Text: "me"})
+	wsjson.Write(ctx, hostConn, ClientMessage{Type: ClientMsgChat, Payload: chatPayload})
+	msg = next(hostConn)
+	var errPayload ErrorPayload
+	json.Unmarshal(msg.Payload, &errPayload)
+	if msg.Type != ServerMsgError || errPayload.Code != ErrorCodeChatFailed {
+		t.Fatalf("expected chat error, got %s %+v", msg.Type, errPayload)
+	}
+
+	// Connecting later includes the history
+	playerConn := connect(player.SessionToken, []string{CapabilityChat})
+	defer playerConn.Close(websocket.StatusNormalClosure, "")
+	msg = next(playerConn)
+	history = ChatHistoryPayload{}
+	json.Unmarshal(msg.Payload, &history)
+	if msg.Type != ServerMsgChatHistory || len(history.Messages) != 1 || history.Messages[0].Type != core.EventChatMessage {
+		t.Fatalf("expected history with one message, got %s %+v", msg.Type, history)
+	}
+}
+
+func TestHandleMutePlayer(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room, host, player := setupLobby(t, server)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		token      string
+		wantStatus int
+	}{
+		{name: "no token", method: http.MethodPost, target: player.ID, wantStatus: http.StatusUnauthorized},
+		{name: "not the host", method: http.MethodPost, target: host.ID, token: player.SessionToken, wantStatus: http.StatusForbidden},
+		{name: "unknown player", method: http.MethodPost, target: "nobody", token: host.SessionToken, wantStatus: http.StatusNotFound},
+		{name: "the host", method: http.MethodPost, target: host.ID, token: host.SessionToken, wantStatus: http.StatusBadRequest},
+		{name: "mute", method: http.MethodPost, target: player.ID, token: host.SessionToken, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		rec := muteRequest(server, tt.method, room.ID, tt.target, tt.token)
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.wantStatus, rec.Code, rec.Body.String())
+		}
+	}
+
+	hello := core.ChatMessage{Channel: core.ChatChannelAll, Text: "hello"}
+	if rec := chatRequest(server, room.ID, player.SessionToken, hello); rec.Code != http.StatusForbidden {
+		t.Errorf("expected muted player to get 403, got %d", rec.Code)
+	}
+	if rec := chatRequest(server, room.ID, host.SessionToken, hello); rec.Code != http.StatusOK {
+		t.Errorf("expected host chat to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := muteRequest(server, http.MethodDelete, room.ID, player.ID, host.SessionToken); rec.Code != http.StatusOK {
+		t.Fatalf("expected unmute to succeed, got %d", rec.Code)
+	}
+	if rec := chatRequest(server, room.ID, player.SessionToken, hello); rec.Code != http.StatusOK {
+		t.Errorf("expected unmuted player to chat, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleChat_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions()
+	options.RateLimits.Chat = ratelimit.Limit{Rate: 0.01, Burst: 1}
+	server := NewServerWithOptions(store.NewMemoryStore(), options)
+	room, _, player := setupLobby(t, server)
+
+	hello := core.ChatMessage{Channel: core.ChatChannelAll, Text: "hello"}
+	if rec := chatRequest(server, room.ID, player.SessionToken, hello); rec.Code != http.StatusOK {
+		t.Fatalf("expected first message to pass, got %d", rec.Code)
+	}
+	rec := chatRequest(server, room.ID, player.SessionToken, hello)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("expected 429 with Retry-After, got %d", rec.Code)
+	}
+}
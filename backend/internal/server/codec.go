@@ -0,0 +1,214 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"nhooyr.io/websocket"
+)
+
+// WebSocket subprotocols used to negotiate the wire codec at connect time.
+// Clients that request no subprotocol get JSON, as before.
+const (
+	SubprotocolJSON = "roundtable.json"
+	SubprotocolCBOR = "roundtable.cbor"
+)
+
+// supportedSubprotocols is in server preference order.
+var supportedSubprotocols = []string{SubprotocolCBOR, SubprotocolJSON}
+
+// compressionThreshold is the message size above which permessage-deflate is
+// applied. Regular events stay uncompressed; large "events" history batches
+// sent on (re)connect get compressed.
+const compressionThreshold = 4096
+
+// Codec encodes server messages and decodes client messages on the wire.
+type Codec interface {
+	// Name is the subprotocol that selects this codec.
+	Name() string
+
+	// MessageType is the WebSocket frame type used for encoded messages.
+	MessageType() websocket.MessageType
+
+	// Encode serializes a server message.
+	Encode(msg ServerMessage) ([]byte, error)
+
+	// Decode parses a client message. The payload is normalized to JSON so
+	// the rest of the server is codec-agnostic.
+	Decode(data []byte, msg *ClientMessage) error
+}
+
+// codecForSubprotocol returns the codec negotiated for a subprotocol.
+func codecForSubprotocol(subprotocol string) Codec {
+	switch subprotocol {
+	case SubprotocolCBOR:
+		return cborCodec{}
+	default:
+		return jsonCodec{}
+	}
+}
+
+// jsonCodec is the default text codec.
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string                       { return SubprotocolJSON }
+func (jsonCodec) MessageType() websocket.MessageType { return websocket.MessageText }
+
+func (jsonCodec) Encode(msg ServerMessage) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (jsonCodec) Decode(data []byte, msg *ClientMessage) error {
+	return json.Unmarshal(data, msg)
+}
+
+// cborCodec is the compact binary codec (RFC 8949).
+// JSON payloads are converted to native CBOR maps and arrays rather than
+// embedded as opaque byte strings, so clients decode a single document.
+type cborCodec struct{}
+
+func (cborCodec) Name() string                       { return SubprotocolCBOR }
+func (cborCodec) MessageType() websocket.MessageType { return websocket.MessageBinary }
+
+// cborEnvelope mirrors ServerMessage/ClientMessage with a decoded payload.
+type cborEnvelope struct {
+	Type    string      `cbor:"type"`
+	Payload interface{} `cbor:"payload,omitempty"`
+}
+
+func (cborCodec) Encode(msg ServerMessage) ([]byte, error) {
+	envelope := cborEnvelope{Type: msg.Type}
+	if len(msg.Payload) > 0 {
+		payload, err := decodeJSONValue(msg.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert payload: %w", err)
+		}
+		envelope.Payload = payload
+	}
+
+	return cbor.Marshal(envelope)
+}
+
+// cborDecMode decodes CBOR maps as map[string]interface{} so decoded
+// payloads can be re-marshaled as JSON.
+var cborDecMode = func() cbor.DecMode {
+	mode, err := cbor.DecOptions{
+		DefaultMapType: reflect.TypeOf(map[string]interface{}(nil)),
+	}.DecMode()
+	if err != nil {
+		panic(fmt.Sprintf("invalid CBOR decode options: %v", err))
+	}
+	return mode
+}()
+
+func (cborCodec) Decode(data []byte, msg *ClientMessage) error {
+	var envelope cborEnvelope
+	if err := cborDecMode.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+
+	msg.Type = envelope.Type
+	msg.Payload = nil
+	if envelope.Payload != nil {
+		payload, err := json.Marshal(envelope.Payload)
+		if err != nil {
+			return fmt.Errorf("failed to convert payload: %w", err)
+		}
+		msg.Payload = payload
+	}
+
+	return nil
+}
+
+// decodeJSONValue decodes JSON into plain Go values, keeping integers as
+// int64 so they encode as CBOR integers rather than floats.
+func decodeJSONValue(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return normalizeNumbers(value), nil
+}
+
+// normalizeNumbers replaces json.Number values with int64 or float64.
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// encodingKey identifies one wire encoding of a message.
+type encodingKey struct {
+	protocolVersion int
+	codec           string
+}
+
+// encodingCache memoizes the wire encodings of a message so a broadcast to
+// many players only adapts and marshals once per (protocol version, codec).
+type encodingCache struct {
+	mu      sync.Mutex
+	entries map[encodingKey]encodedMessage
+}
+
+// encodedMessage is a cached encoding. ok is false when the protocol adapter
+// dropped the message for that version.
+type encodedMessage struct {
+	data []byte
+	ok   bool
+}
+
+// encode returns the wire bytes of msg for the given protocol version and
+// codec, reusing the cached result when the message has been encoded before.
+func (m ServerMessage) encode(protocolVersion int, codec Codec) ([]byte, bool, error) {
+	key := encodingKey{protocolVersion: protocolVersion, codec: codec.Name()}
+
+	if m.cache != nil {
+		m.cache.mu.Lock()
+		defer m.cache.mu.Unlock()
+
+		if cached, exists := m.cache.entries[key]; exists {
+			return cached.data, cached.ok, nil
+		}
+	}
+
+	adapted, ok := adaptMessage(protocolVersion, m)
+	var data []byte
+	if ok {
+		var err error
+		data, err = codec.Encode(adapted)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if m.cache != nil {
+		m.cache.entries[key] = encodedMessage{data: data, ok: ok}
+	}
+
+	return data, ok, nil
+}
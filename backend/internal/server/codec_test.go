@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"nhooyr.io/websocket"
+
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+func TestCBORCodec_Encode(t *testing.T) {
+	t.Parallel()
+
+	msg, _ := NewServerMessage(ServerMsgEvent, map[string]interface{}{
+		"count": 3,
+		"ratio": 0.5,
+		"names": []string{"a", "b"},
+	})
+
+	data, err := cborCodec{}.Encode(msg)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var decoded struct {
+		Type    string `cbor:"type"`
+		Payload struct {
+			Count int64    `cbor:"count"`
+			Ratio float64  `cbor:"ratio"`
+			Names []string `cbor:"names"`
+		} `cbor:"payload"`
+	}
+	if err := cbor.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode CBOR: %v", err)
+	}
+
+	if decoded.Type != ServerMsgEvent {
+		t.Errorf("expected type %s, got %s", ServerMsgEvent, decoded.Type)
+	}
+	if decoded.Payload.Count != 3 || decoded.Payload.Ratio != 0.5 || len(decoded.Payload.Names) != 2 {
+		t.Errorf("payload did not round-trip: %+v", decoded.Payload)
+	}
+
+	jsonData, _ := jsonCodec{}.Encode(msg)
+	if len(data) >= len(jsonData) {
+		t.Errorf("expected CBOR (%d bytes) to be smaller than JSON (%d bytes)", len(data), len(jsonData))
+	}
+}
+
+func TestCBORCodec_Decode(t *testing.T) {
+	t.Parallel()
+
+	data, _ := cbor.Marshal(map[string]interface{}{
+		"type": ClientMsgAction,
+		"payload": map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":    "vote",
+				"payload": map[string]interface{}{"targetId": "p2"},
+			},
+		},
+	})
+
+	var msg ClientMessage
+	if err := (cborCodec{}).Decode(data, &msg); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if msg.Type != ClientMsgAction {
+		t.Errorf("expected type %s, got %s", ClientMsgAction, msg.Type)
+	}
+
+	var payload ActionPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		t.Fatalf("payload is not valid JSON: %v", err)
+	}
+	if payload.Action.Type != "vote" || !strings.Contains(string(payload.Action.Payload), "p2") {
+		t.Errorf("unexpected action: %+v", payload.Action)
+	}
+}
+
+func TestServerMessage_EncodeCache(t *testing.T) {
+	t.Parallel()
+
+	msg, _ := NewCodedErrorMessage(ErrorCodeActionFailed, "nope")
+	copied := msg // Broadcast recipients receive copies
+
+	first, ok, err := msg.encode(CurrentProtocolVersion, jsonCodec{})
+	if err != nil || !ok {
+		t.Fatalf("encode() = %v, %v", ok, err)
+	}
+	second, _, _ := copied.encode(CurrentProtocolVersion, jsonCodec{})
+	if &first[0] != &second[0] {
+		t.Error("expected copies of a message to share the cached encoding")
+	}
+
+	legacy, _, _ := msg.encode(ProtocolVersion1, jsonCodec{})
+	if strings.Contains(string(legacy), ErrorCodeActionFailed) {
+		t.Error("v1 encoding should be adapted separately from v2")
+	}
+
+	binary, _, _ := msg.encode(CurrentProtocolVersion, cborCodec{})
+	if string(binary) == string(first) {
+		t.Error("CBOR encoding should be cached separately from JSON")
+	}
+
+	dropped := ServerMessage{Type: "something_new"}
+	if _, ok, _ := dropped.encode(ProtocolVersion1, jsonCodec{}); ok {
+		t.Error("expected adapter to drop unknown message for v1")
+	}
+}
+
+func TestHandleWebSocket_CBORSubprotocol(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room, tokens := setupWerewolfGame(t, server)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/rooms/{code}/ws", server.HandleWebSocket)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/rooms/" + room.ID + "/ws"
+	conn, _, err := websocket.Dial(ctx, wsURL, &websocket.DialOptions{
+		HTTPHeader:      http.Header{"Origin": []string{"http://localhost:5173"}},
+		Subprotocols:    []string{SubprotocolCBOR},
+		CompressionMode: websocket.CompressionNoContextTakeover,
+	})
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	if conn.Subprotocol() != SubprotocolCBOR {
+		t.Fatalf("expected %s subprotocol, got %q", SubprotocolCBOR, conn.Subprotocol())
+	}
+
+	authData, _ := cbor.Marshal(map[string]interface{}{
+		"type": ClientMsgAuthenticate,
+		"payload": map[string]interface{}{
+			"sessionToken":    tokens[room.HostID],
+			"protocolVersion": CurrentProtocolVersion,
+		},
+	})
+	if err := conn.Write(ctx, websocket.MessageBinary, authData); err != nil {
+		t.Fatalf("failed to send authenticate: %v", err)
+	}
+
+	typ, data, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatalf("failed to read: %v", err)
+	}
+	if typ != websocket.MessageBinary {
+		t.Fatalf("expected binary frame, got %v", typ)
+	}
+
+	var reply struct {
+		Type    string `cbor:"type"`
+		Payload struct {
+			PlayerID        string `cbor:"playerId"`
+			ProtocolVersion int    `cbor:"protocolVersion"`
+		} `cbor:"payload"`
+	}
+	if err := cbor.Unmarshal(data, &reply); err != nil {
+		t.Fatalf("failed to decode CBOR reply: %v", err)
+	}
+	if reply.Type != ServerMsgAuthenticated || reply.Payload.PlayerID != room.HostID {
+		t.Errorf("unexpected reply: %+v", reply)
+	}
+	if reply.Payload.ProtocolVersion != CurrentProtocolVersion {
+		t.Errorf("expected protocol version %d, got %d", CurrentProtocolVersion, reply.Payload.ProtocolVersion)
+	}
+}
@@ -5,15 +5,20 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
-	"os"
 	"regexp"
 	"strings"
 
 	"nhooyr.io/websocket"
 
+	"github.com/KonradHerman/roundtable/internal/bot"
 	"github.com/KonradHerman/roundtable/internal/core"
 	"github.com/KonradHerman/roundtable/internal/games"
+	"github.com/KonradHerman/roundtable/internal/metrics"
+	"github.com/KonradHerman/roundtable/internal/profile"
+	"github.com/KonradHerman/roundtable/internal/ratelimit"
+	"github.com/KonradHerman/roundtable/internal/stats"
 	"github.com/KonradHerman/roundtable/internal/store"
+	"github.com/KonradHerman/roundtable/internal/tournament"
 	"github.com/KonradHerman/roundtable/internal/util"
 )
 
@@ -46,15 +51,80 @@ type Server struct {
 	store        store.Store
 	connMgr      *ConnectionManager
 	gameRegistry *games.Registry
+	options      Options
+	limiters     map[RateLimitScope]*ratelimit.Limiter
+	metrics      *metrics.Metrics
+	origins      *OriginPolicy
+	codes        *util.CodeAllocator
+	profiles     *profile.Store
+	stats        *stats.Store
+	tournaments  *tournament.Store
+	bots         *bot.Manager
 }
 
-// NewServer creates a new server instance.
+// NewServer creates a new server instance with default options.
 func NewServer(store store.Store) *Server {
-	return &Server{
+	return NewServerWithOptions(store, DefaultOptions())
+}
+
+// NewServerWithOptions creates a new server instance.
+func NewServerWithOptions(store store.Store, options Options) *Server {
+	if options.DefaultMaxPlayers <= 0 {
+		options.DefaultMaxPlayers = DefaultOptions().DefaultMaxPlayers
+	}
+
+	limiters := newLimiters(options.RateLimits)
+
+	origins, err := NewOriginPolicy(options.AllowedOrigins)
+	if err != nil {
+		// Validated by config; refuse cross-origin requests rather than
+		// guessing what was meant
+		slog.Error("invalid allowed origins, refusing cross-origin requests", "error", err)
+		origins = &OriginPolicy{}
+	}
+
+	if options.SessionTTL <= 0 {
+		options.SessionTTL = DefaultOptions().SessionTTL
+	}
+	if options.InviteTTL <= 0 {
+		options.InviteTTL = DefaultOptions().InviteTTL
+	}
+
+	if options.RoomCodes == (util.CodeOptions{}) {
+		options.RoomCodes = DefaultOptions().RoomCodes
+	}
+
+	connMgr := NewConnectionManagerWithOptions(store, options.Connections)
+	connMgr.tokens = newIssuer(options.SessionKey, options.SessionTTL)
+	connMgr.actionLimiter = limiters[ScopeActions]
+	connMgr.chatLimiter = limiters[ScopeChat]
+	connMgr.reactionLimiter = limiters[ScopeReactions]
+
+	s := &Server{
 		store:        store,
-		connMgr:      NewConnectionManager(store),
+		connMgr:      connMgr,
 		gameRegistry: games.NewRegistry(),
-	}
+		options:      options,
+		limiters:     limiters,
+		metrics:      metrics.New(),
+		origins:      origins,
+		codes:        newCodeAllocator(store, options.RoomCodes),
+		profiles:     profile.NewStore(),
+		stats:        stats.NewStore(),
+		tournaments:  tournament.NewStore(),
+		bots:         bot.NewManager(connMgr.processAction),
+	}
+	connMgr.metrics = s.metrics
+	connMgr.bots = s.bots
+	connMgr.onGameFinished = s.gameFinished
+	s.metrics.Register(newServerCollector(s))
+
+	return s
+}
+
+// Metrics returns the server's metrics, e.g. to record store cleanups.
+func (s *Server) Metrics() *metrics.Metrics {
+	return s.metrics
 }
 
 // ConnectionManager returns the connection manager (for phase checks).
@@ -67,6 +137,10 @@ type CreateRoomRequest struct {
 	GameType    string `json:"gameType"`
 	DisplayName string `json:"displayName"` // Host's display name
 	MaxPlayers  int    `json:"maxPlayers,omitempty"`
+	Password    string `json:"password,omitempty"` // Required to join, unless invited
+	RoomCode    string `json:"roomCode,omitempty"` // Custom code, if the server allows them
+	Public      bool   `json:"public,omitempty"`   // List in the public room browser
+	Title       string `json:"title,omitempty"`    // Public room title, defaults to the host's name
 }
 
 // CreateRoomResponse is the response for creating a room.
@@ -100,44 +174,55 @@ func (s *Server) HandleCreateRoom(w http.ResponseWriter, r *http.Request) {
 
 	// Default max players
 	if req.MaxPlayers == 0 {
-		req.MaxPlayers = 10
+		req.MaxPlayers = s.options.DefaultMaxPlayers
+	}
+
+	// Optional profile the host creates the room with
+	hostProfile, err := s.requestProfile(r)
+	if err != nil {
+		http.Error(w, "Invalid profile key", http.StatusUnauthorized)
+		return
 	}
 
 	// Validate display name
-	displayName, err := validateDisplayName(req.DisplayName)
+	displayName, err := playerName(req.DisplayName, hostProfile)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Create host player
-	hostPlayer := core.NewPlayer(displayName)
-
-	// Generate room code
-	roomCode := util.GenerateRoomCode()
+	if len(req.Password) > core.MaxPasswordLength {
+		http.Error(w, core.ErrPasswordTooLong.Error(), http.StatusBadRequest)
+		return
+	}
 
-	// Create room
-	room := core.NewRoom(roomCode, req.GameType, hostPlayer, req.MaxPlayers)
+	if req.Public {
+		req.Title, err = validateRoomTitle(req.Title, displayName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
-	// Store room
-	if err := s.store.CreateRoom(room); err != nil {
-		slog.Error("failed to create room", "error", err)
-		http.Error(w, "Failed to create room", http.StatusInternalServerError)
+	// Create and store the room under a free code
+	room, hostPlayer, err := s.createRoom(req, displayName, hostProfile)
+	if err != nil {
+		status, message := createRoomError(err)
+		if status >= http.StatusInternalServerError {
+			slog.Error("failed to create room", "error", err)
+		}
+		http.Error(w, message, status)
 		return
 	}
-
-	// Create initial event
-	event, _ := core.NewPublicEvent(core.EventPlayerJoined, "system", core.PlayerJoinedPayload{
-		PlayerID:    hostPlayer.ID,
-		DisplayName: hostPlayer.DisplayName,
-	})
-	room.AppendEvent(event)
+	roomCode := room.ID
 
 	slog.Info("created room",
 		"roomCode", roomCode,
 		"gameType", req.GameType,
 		"hostName", hostPlayer.DisplayName,
 		"hostID", hostPlayer.ID,
+		"passwordProtected", room.HasPassword(),
+		"public", req.Public,
 	)
 
 	// Return response
@@ -154,6 +239,8 @@ func (s *Server) HandleCreateRoom(w http.ResponseWriter, r *http.Request) {
 // JoinRoomRequest is the payload for joining a room.
 type JoinRoomRequest struct {
 	DisplayName string `json:"displayName"`
+	Password    string `json:"password,omitempty"` // For password-protected rooms
+	Invite      string `json:"invite,omitempty"`   // Invite token, instead of the password
 }
 
 // JoinRoomResponse is the response for joining a room.
@@ -187,8 +274,15 @@ func (s *Server) HandleJoinRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Optional profile the player joins with
+	playerProfile, err := s.requestProfile(r)
+	if err != nil {
+		http.Error(w, "Invalid profile key", http.StatusUnauthorized)
+		return
+	}
+
 	// Validate display name
-	displayName, err := validateDisplayName(req.DisplayName)
+	displayName, err := playerName(req.DisplayName, playerProfile)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -201,41 +295,61 @@ func (s *Server) HandleJoinRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create player
-	player := core.NewPlayer(displayName)
+	// Password-protected rooms need the password or an invite
+	if err := s.checkRoomAccess(room, req.Password, req.Invite); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	// Create player with a session bound to the room
+	player, err := s.newPlayer(room.ID, displayName, playerProfile)
+	if err != nil {
+		slog.Error("failed to issue session token", "error", err)
+		http.Error(w, "Failed to join room", http.StatusInternalServerError)
+		return
+	}
 
 	// Add player to room
-	if err := room.AddPlayer(player); err != nil {
+	if err := s.joinRoom(room, player); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	// Return response
+	resp := JoinRoomResponse{
+		SessionToken: player.SessionToken,
+		PlayerID:     player.ID,
+		RoomCode:     roomCode,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// joinRoom adds a player to the room and announces them to everyone in it.
+func (s *Server) joinRoom(room *core.Room, player *core.Player) error {
+	if err := room.AddPlayer(player); err != nil {
+		return err
+	}
+
 	// Create player joined event
 	event, _ := core.NewPublicEvent(core.EventPlayerJoined, "system", core.PlayerJoinedPayload{
 		PlayerID:    player.ID,
 		DisplayName: player.DisplayName,
 	})
 	room.AppendEvent(event)
+	s.linkProfile(room.ID, player)
 
 	// Broadcast event to connected players
-	s.connMgr.BroadcastEvent(roomCode, event)
-	s.connMgr.BroadcastRoomState(roomCode)
+	s.connMgr.BroadcastEvent(room.ID, event)
+	s.connMgr.BroadcastRoomState(room.ID)
 
 	slog.Info("player joined room",
 		"playerName", player.DisplayName,
 		"playerID", player.ID,
-		"roomCode", roomCode,
+		"roomCode", room.ID,
 	)
-
-	// Return response
-	resp := JoinRoomResponse{
-		SessionToken: player.SessionToken,
-		PlayerID:     player.ID,
-		RoomCode:     roomCode,
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	return nil
 }
 
 // StartGameRequest is the payload for starting a game.
@@ -301,6 +415,7 @@ func (s *Server) HandleStartGame(w http.ResponseWriter, r *http.Request) {
 	for _, event := range newEvents {
 		s.connMgr.BroadcastEvent(roomCode, event)
 	}
+	s.connMgr.RefreshChat(room)
 
 	slog.Info("game started", "roomCode", roomCode, "gameType", room.GameType)
 
@@ -339,6 +454,7 @@ func (s *Server) HandleResetGame(w http.ResponseWriter, r *http.Request) {
 
 	// Broadcast updated room state to all players
 	s.connMgr.BroadcastRoomState(roomCode)
+	s.connMgr.RefreshChat(room)
 
 	slog.Info("room reset for new game", "roomCode", roomCode)
 
@@ -350,6 +466,10 @@ func (s *Server) HandleResetGame(w http.ResponseWriter, r *http.Request) {
 
 // HandleWebSocket upgrades HTTP connection to WebSocket.
 func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	if s.refuseWhileDraining(w) {
+		return
+	}
+
 	// Extract room code from URL path
 	roomCode := r.PathValue("code")
 	if roomCode == "" {
@@ -363,12 +483,20 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get allowed origins from environment
-	allowedOrigins := getWebSocketOrigins()
+	// Browsers don't apply CORS to WebSockets, so check the origin here
+	if !s.origins.AllowedRequest(r) {
+		slog.Warn("rejected WebSocket origin", "origin", r.Header.Get("Origin"), "remoteAddr", r.RemoteAddr)
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return
+	}
 
-	// Upgrade connection with origin restrictions
+	// Upgrade connection with codec negotiation via subprotocol and
+	// compression for large messages. The origin was verified above.
 	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
-		OriginPatterns: allowedOrigins,
+		InsecureSkipVerify:   true,
+		Subprotocols:         supportedSubprotocols,
+		CompressionMode:      websocket.CompressionNoContextTakeover,
+		CompressionThreshold: compressionThreshold,
 	})
 	if err != nil {
 		slog.Error("failed to upgrade WebSocket", "error", err, "remoteAddr", r.RemoteAddr)
@@ -401,31 +529,3 @@ func (s *Server) HandleGetRoom(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(room.GetState())
 }
-
-// getWebSocketOrigins returns allowed WebSocket origin patterns from environment.
-func getWebSocketOrigins() []string {
-	// Try ALLOWED_ORIGINS first (comma-separated list), then fall back to ALLOWED_ORIGIN
-	originsEnv := os.Getenv("ALLOWED_ORIGINS")
-	if originsEnv == "" {
-		originsEnv = os.Getenv("ALLOWED_ORIGIN")
-	}
-
-	if originsEnv == "" {
-		// Dev default - allow localhost on any port
-		return []string{"localhost:*", "127.0.0.1:*"}
-	}
-
-	// Special case: "*" means allow all origins
-	if originsEnv == "*" {
-		return []string{"*"}
-	}
-
-	// Split comma-separated origins
-	origins := strings.Split(originsEnv, ",")
-	// Trim whitespace from each origin
-	for i, origin := range origins {
-		origins[i] = strings.TrimSpace(origin)
-	}
-
-	return origins
-}
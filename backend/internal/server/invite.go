@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/auth"
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+var (
+	errPasswordRequired  = errors.New("room password required")
+	errIncorrectPassword = errors.New("incorrect room password")
+	errInviteExpired     = errors.New("invite link expired")
+)
+
+// checkRoomAccess decides whether a new player may join the room. Rooms
+// with a password admit players giving the password or a valid invite.
+func (s *Server) checkRoomAccess(room *core.Room, password string, invite string) error {
+	if !room.HasPassword() {
+		return nil
+	}
+
+	if invite != "" {
+		claims, err := s.connMgr.tokens.VerifyInvite(invite)
+		if err == nil && claims.ForRoom(room.ID, room.CreatedAt) {
+			return nil
+		}
+		if errors.Is(err, auth.ErrExpiredToken) && password == "" {
+			return errInviteExpired
+		}
+	}
+
+	if password == "" {
+		return errPasswordRequired
+	}
+	if !room.CheckPassword(password) {
+		return errIncorrectPassword
+	}
+	return nil
+}
+
+// InviteResponse is the response for creating an invite.
+type InviteResponse struct {
+	RoomCode  string    `json:"roomCode"`
+	Invite    string    `json:"invite"` // Pass as "invite" when joining
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// HandleCreateInvite lets the host create an expiring invite, which lets
+// players join without the room password.
+func (s *Server) HandleCreateInvite(w http.ResponseWriter, r *http.Request) {
+	roomCode := r.PathValue("code")
+	if roomCode == "" {
+		http.Error(w, "Room code required", http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("X-Session-Token")
+	if token == "" {
+		http.Error(w, "Session token required", http.StatusUnauthorized)
+		return
+	}
+
+	room, err := s.store.GetRoom(roomCode)
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	player, err := s.connMgr.authenticatePlayer(room, token)
+	if err != nil {
+		http.Error(w, "Invalid session token", http.StatusUnauthorized)
+		return
+	}
+	if !room.IsHost(player.ID) {
+		http.Error(w, "Only the host can create invites", http.StatusForbidden)
+		return
+	}
+
+	invite, expiresAt, err := s.connMgr.tokens.IssueInvite(room.ID, room.CreatedAt, s.options.InviteTTL)
+	if err != nil {
+		slog.Error("failed to issue invite", "roomCode", roomCode, "error", err)
+		http.Error(w, "Failed to create invite", http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("created invite", "roomCode", roomCode, "expiresAt", expiresAt)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(InviteResponse{
+		RoomCode:  room.ID,
+		Invite:    invite,
+		ExpiresAt: expiresAt,
+	})
+}
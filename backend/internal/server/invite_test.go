@@ -0,0 +1,182 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+// createRoom creates a room through the API and returns the response.
+func createRoom(t *testing.T, s *Server, req CreateRoomRequest) CreateRoomResponse {
+	t.Helper()
+
+	body, _ := json.Marshal(req)
+	rec := httptest.NewRecorder()
+	s.HandleCreateRoom(rec, httptest.NewRequest(http.MethodPost, "/api/rooms", bytes.NewBuffer(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create room: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp CreateRoomResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	return resp
+}
+
+// createInvite requests an invite for the room with the given session token.
+func createInvite(s *Server, roomCode, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms/"+roomCode+"/invites", nil)
+	req.SetPathValue("code", roomCode)
+	req.Header.Set("X-Session-Token", token)
+	rec := httptest.NewRecorder()
+	s.HandleCreateInvite(rec, req)
+	return rec
+}
+
+func TestHandleJoinRoom_Password(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room := createRoom(t, server, CreateRoomRequest{GameType: "werewolf", DisplayName: "Host", Password: "hunter2"})
+	other := createRoom(t, server, CreateRoomRequest{GameType: "werewolf", DisplayName: "Host", Password: "hunter2"})
+
+	var invite, otherInvite InviteResponse
+	json.Unmarshal(createInvite(server, room.RoomCode, room.SessionToken).Body.Bytes(), &invite)
+	json.Unmarshal(createInvite(server, other.RoomCode, other.SessionToken).Body.Bytes(), &otherInvite)
+
+	created, _ := server.store.GetRoom(room.RoomCode)
+	forged, _, _ := newIssuer(nil, time.Hour).IssueInvite(room.RoomCode, created.CreatedAt, time.Hour)
+	// An invite to an earlier room that had the same code
+	stale, _, _ := server.connMgr.tokens.IssueInvite(room.RoomCode, created.CreatedAt.Add(-time.Hour), time.Hour)
+
+	tests := []struct {
+		name           string
+		req            JoinRoomRequest
+		wantStatusCode int
+		wantBody       string
+	}{
+		{name: "no password", req: JoinRoomRequest{DisplayName: "A"}, wantStatusCode: http.StatusForbidden, wantBody: "room password required"},
+		{name: "wrong password", req: JoinRoomRequest{DisplayName: "B", Password: "hunter3"}, wantStatusCode: http.StatusForbidden, wantBody: "incorrect room password"},
+		{name: "correct password", req: JoinRoomRequest{DisplayName: "C", Password: "hunter2"}, wantStatusCode: http.StatusOK},
+		{name: "invite", req: JoinRoomRequest{DisplayName: "D", Invite: invite.Invite}, wantStatusCode: http.StatusOK},
+		{name: "invite to another room", req: JoinRoomRequest{DisplayName: "E", Invite: otherInvite.Invite}, wantStatusCode: http.StatusForbidden},
+		{name: "forged invite", req: JoinRoomRequest{DisplayName: "F", Invite: forged}, wantStatusCode: http.StatusForbidden},
+		{name: "invite to earlier room with the code", req: JoinRoomRequest{DisplayName: "I", Invite: stale}, wantStatusCode: http.StatusForbidden},
+		{name: "session token as invite", req: JoinRoomRequest{DisplayName: "G", Invite: room.SessionToken}, wantStatusCode: http.StatusForbidden},
+		{name: "bad invite with password", req: JoinRoomRequest{DisplayName: "H", Invite: "nope", Password: "hunter2"}, wantStatusCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			body, _ := json.Marshal(tt.req)
+			req := httptest.NewRequest(http.MethodPost, "/api/rooms/"+room.RoomCode+"/join", bytes.NewBuffer(body))
+			req.SetPathValue("code", room.RoomCode)
+			rec := httptest.NewRecorder()
+			server.HandleJoinRoom(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantBody != "" && !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("expected body containing %q, got %q", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleJoinRoom_ExpiredInvite(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions()
+	options.SessionKey = testSessionKey
+	server := NewServerWithOptions(store.NewMemoryStore(), options)
+	room := createRoom(t, server, CreateRoomRequest{GameType: "werewolf", DisplayName: "Host", Password: "hunter2"})
+
+	// Same key, so the invite is genuine; expiry has second precision, so it
+	// is expired right away
+	created, _ := server.store.GetRoom(room.RoomCode)
+	expired, _, _ := newIssuer(testSessionKey, time.Hour).IssueInvite(room.RoomCode, created.CreatedAt, time.Nanosecond)
+
+	body, _ := json.Marshal(JoinRoomRequest{DisplayName: "Late", Invite: expired})
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms/"+room.RoomCode+"/join", bytes.NewBuffer(body))
+	req.SetPathValue("code", room.RoomCode)
+	rec := httptest.NewRecorder()
+	server.HandleJoinRoom(rec, req)
+
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "invite link expired") {
+		t.Errorf("expected 403 invite link expired, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleCreateRoom_PasswordTooLong(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	body, _ := json.Marshal(CreateRoomRequest{GameType: "werewolf", DisplayName: "Host", Password: strings.Repeat("x", 73)})
+	rec := httptest.NewRecorder()
+	server.HandleCreateRoom(rec, httptest.NewRequest(http.MethodPost, "/api/rooms", bytes.NewBuffer(body)))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestHandleCreateInvite(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room := createRoom(t, server, CreateRoomRequest{GameType: "werewolf", DisplayName: "Host", Password: "hunter2"})
+
+	body, _ := json.Marshal(JoinRoomRequest{DisplayName: "Guest", Password: "hunter2"})
+	joinReq := httptest.NewRequest(http.MethodPost, "/api/rooms/"+room.RoomCode+"/join", bytes.NewBuffer(body))
+	joinReq.SetPathValue("code", room.RoomCode)
+	joinRec := httptest.NewRecorder()
+	server.HandleJoinRoom(joinRec, joinReq)
+	var guest JoinRoomResponse
+	json.Unmarshal(joinRec.Body.Bytes(), &guest)
+
+	tests := []struct {
+		name           string
+		roomCode       string
+		token          string
+		wantStatusCode int
+	}{
+		{name: "host", roomCode: room.RoomCode, token: room.SessionToken, wantStatusCode: http.StatusOK},
+		{name: "not the host", roomCode: room.RoomCode, token: guest.SessionToken, wantStatusCode: http.StatusForbidden},
+		{name: "no token", roomCode: room.RoomCode, wantStatusCode: http.StatusUnauthorized},
+		{name: "invalid token", roomCode: room.RoomCode, token: "nope", wantStatusCode: http.StatusUnauthorized},
+		{name: "unknown room", roomCode: "NOROOM", token: room.SessionToken, wantStatusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := createInvite(server, tt.roomCode, tt.token)
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatusCode != http.StatusOK {
+				return
+			}
+
+			var resp InviteResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Invite == "" || resp.RoomCode != room.RoomCode {
+				t.Errorf("unexpected invite response %+v", resp)
+			}
+			if until := time.Until(resp.ExpiresAt); until < 23*time.Hour || until > 24*time.Hour {
+				t.Errorf("expected invite valid for about 24h, got %s", until)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// maxPublicRooms caps the public room listing.
+const maxPublicRooms = 50
+
+// maxTitleLength is the longest allowed public room title.
+const maxTitleLength = 40
+
+// quickMatchTitle is the title of rooms opened by quick-match.
+const quickMatchTitle = "Quick match"
+
+// validTitleRegex allows display name characters plus common punctuation.
+var validTitleRegex = regexp.MustCompile(`^[a-zA-Z0-9 _\-'\.!?,&#:]+$`)
+
+// validateRoomTitle cleans a public room title, defaulting to the host's name.
+func validateRoomTitle(title, hostName string) (string, error) {
+	cleaned := strings.TrimSpace(title)
+	if cleaned == "" {
+		return hostName + "'s room", nil
+	}
+
+	if len(cleaned) > maxTitleLength {
+		return "", errors.New("room title must be 40 characters or less")
+	}
+	if !validTitleRegex.MatchString(cleaned) {
+		return "", errors.New("room title contains invalid characters")
+	}
+
+	return cleaned, nil
+}
+
+// PublicRoom is a lobby in the public room browser.
+type PublicRoom struct {
+	RoomCode    string    `json:"roomCode"`
+	Title       string    `json:"title"`
+	GameType    string    `json:"gameType"`
+	Players     int       `json:"players"`
+	MaxPlayers  int       `json:"maxPlayers"`
+	HasPassword bool      `json:"hasPassword,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// ListRoomsResponse is the response for listing public rooms.
+type ListRoomsResponse struct {
+	Rooms []PublicRoom `json:"rooms"`
+}
+
+// publicLobbies returns public rooms still waiting for players with free
+// seats, optionally only for one game, fullest first and then oldest first.
+// Rooms everyone has left are skipped until cleanup removes them.
+func (s *Server) publicLobbies(gameType string) ([]*core.Room, []PublicRoom, error) {
+	rooms, err := s.store.ListRooms()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type lobby struct {
+		room  *core.Room
+		entry PublicRoom
+	}
+	lobbies := make([]lobby, 0)
+	for _, room := range rooms {
+		state := room.GetState()
+		if !state.Public || state.Status != core.RoomStatusWaiting {
+			continue
+		}
+		if gameType != "" && state.GameType != gameType {
+			continue
+		}
+		if len(state.Players) >= state.MaxPlayers || !room.IsAnyPlayerConnected() {
+			continue
+		}
+
+		lobbies = append(lobbies, lobby{room: room, entry: PublicRoom{
+			RoomCode:    state.ID,
+			Title:       state.Title,
+			GameType:    state.GameType,
+			Players:     len(state.Players),
+			MaxPlayers:  state.MaxPlayers,
+			HasPassword: state.HasPassword,
+			CreatedAt:   state.CreatedAt,
+		}})
+	}
+
+	sort.Slice(lobbies, func(i, j int) bool {
+		a, b := lobbies[i].entry, lobbies[j].entry
+		if a.Players != b.Players {
+			return a.Players > b.Players
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.RoomCode < b.RoomCode
+	})
+
+	matched := make([]*core.Room, len(lobbies))
+	entries := make([]PublicRoom, len(lobbies))
+	for i, l := range lobbies {
+		matched[i] = l.room
+		entries[i] = l.entry
+	}
+	return matched, entries, nil
+}
+
+// HandleListRooms lists public lobbies for the room browser.
+// Expected format: GET /api/rooms?public=true&game=avalon
+func (s *Server) HandleListRooms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Only public rooms are ever listed
+	if r.URL.Query().Get("public") != "true" {
+		http.Error(w, "Only public rooms can be listed, use public=true", http.StatusBadRequest)
+		return
+	}
+
+	gameType := r.URL.Query().Get("game")
+	if gameType != "" && !s.gameRegistry.IsRegistered(gameType) {
+		http.Error(w, "Unknown game type", http.StatusBadRequest)
+		return
+	}
+
+	_, entries, err := s.publicLobbies(gameType)
+	if err != nil {
+		slog.Error("failed to list rooms", "error", err)
+		http.Error(w, "Failed to list rooms", http.StatusInternalServerError)
+		return
+	}
+	if len(entries) > maxPublicRooms {
+		entries = entries[:maxPublicRooms]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ListRoomsResponse{Rooms: entries})
+}
+
+// QuickMatchRequest is the payload for quick-match.
+type QuickMatchRequest struct {
+	DisplayName string `json:"displayName"`
+	GameType    string `json:"gameType"`
+}
+
+// QuickMatchResponse is the response for quick-match.
+type QuickMatchResponse struct {
+	RoomCode     string `json:"roomCode"`
+	SessionToken string `json:"sessionToken"`
+	PlayerID     string `json:"playerId"`
+	Created      bool   `json:"created"` // A new public room was opened, with the player as host
+}
+
+// HandleQuickMatch puts a player into the fullest open public lobby for the
+// game, or opens a new public room if there is none.
+func (s *Server) HandleQuickMatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Limit request body to 1MB
+	r.Body = http.MaxBytesReader(w, r.Body, 1*1024*1024)
+
+	var req QuickMatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Request too large or malformed", http.StatusBadRequest)
+		return
+	}
+
+	if !s.gameRegistry.IsRegistered(req.GameType) {
+		http.Error(w, "Unknown game type", http.StatusBadRequest)
+		return
+	}
+
+	// Optional profile the player joins with
+	playerProfile, err := s.requestProfile(r)
+	if err != nil {
+		http.Error(w, "Invalid profile key", http.StatusUnauthorized)
+		return
+	}
+
+	displayName, err := playerName(req.DisplayName, playerProfile)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rooms, _, err := s.publicLobbies(req.GameType)
+	if err != nil {
+		slog.Error("failed to list rooms", "error", err)
+		http.Error(w, "Failed to find a room", http.StatusInternalServerError)
+		return
+	}
+
+	for _, room := range rooms {
+		// Quick-match never guesses passwords
+		if room.HasPassword() {
+			continue
+		}
+
+		player, err := s.newPlayer(room.ID, displayName, playerProfile)
+		if err != nil {
+			slog.Error("failed to issue session token", "error", err)
+			http.Error(w, "Failed to join room", http.StatusInternalServerError)
+			return
+		}
+
+		// The room may have filled up or started since it was listed
+		if err := s.joinRoom(room, player); err != nil {
+			continue
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(QuickMatchResponse{
+			RoomCode:     room.ID,
+			SessionToken: player.SessionToken,
+			PlayerID:     player.ID,
+		})
+		return
+	}
+
+	// No open lobby, so open one
+	room, hostPlayer, err := s.createRoom(CreateRoomRequest{
+		GameType:    req.GameType,
+		DisplayName: displayName,
+		MaxPlayers:  s.options.DefaultMaxPlayers,
+		Public:      true,
+		Title:       quickMatchTitle,
+	}, displayName, playerProfile)
+	if err != nil {
+		status, message := createRoomError(err)
+		if status >= http.StatusInternalServerError {
+			slog.Error("failed to create room", "error", err)
+		}
+		http.Error(w, message, status)
+		return
+	}
+
+	slog.Info("created quick-match room",
+		"roomCode", room.ID,
+		"gameType", req.GameType,
+		"hostName", hostPlayer.DisplayName,
+		"hostID", hostPlayer.ID,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(QuickMatchResponse{
+		RoomCode:     room.ID,
+		SessionToken: hostPlayer.SessionToken,
+		PlayerID:     hostPlayer.ID,
+		Created:      true,
+	})
+}
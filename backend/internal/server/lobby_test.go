@@ -0,0 +1,207 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+// listRooms lists rooms with the given query string.
+func listRooms(s *Server, query string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.HandleListRooms(rec, httptest.NewRequest(http.MethodGet, "/api/rooms?"+query, nil))
+	return rec
+}
+
+// quickMatch requests a quick-match for the player.
+func quickMatch(t *testing.T, s *Server, displayName, gameType string) QuickMatchResponse {
+	t.Helper()
+
+	body, _ := json.Marshal(QuickMatchRequest{DisplayName: displayName, GameType: gameType})
+	rec := httptest.NewRecorder()
+	s.HandleQuickMatch(rec, httptest.NewRequest(http.MethodPost, "/api/quickmatch", bytes.NewBuffer(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("quick-match: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp QuickMatchResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	return resp
+}
+
+func TestValidateRoomTitle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		title   string
+		want    string
+		wantErr bool
+	}{
+		{name: "title", title: "  Friday night! ", want: "Friday night!"},
+		{name: "default", title: "", want: "Alice's room"},
+		{name: "too long", title: "This title is far too long for the room browser", wantErr: true},
+		{name: "invalid characters", title: "<script>", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := validateRoomTitle(tt.title, "Alice")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateRoomTitle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("validateRoomTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleListRooms(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+
+	quiet := createRoom(t, s, CreateRoomRequest{GameType: "avalon", DisplayName: "Quiet", Public: true, Title: "Quiet table"})
+	busy := createRoom(t, s, CreateRoomRequest{GameType: "avalon", DisplayName: "Busy", Public: true})
+	createRoom(t, s, CreateRoomRequest{GameType: "werewolf", DisplayName: "Wolf", Public: true})
+	createRoom(t, s, CreateRoomRequest{GameType: "avalon", DisplayName: "Private"})
+	full := createRoom(t, s, CreateRoomRequest{GameType: "avalon", DisplayName: "Full", Public: true, MaxPlayers: 1})
+
+	busyRoom, _ := s.store.GetRoom(busy.RoomCode)
+	busyRoom.AddPlayer(core.NewPlayer("Guest"))
+
+	rec := listRooms(s, "public=true&game=avalon")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ListRoomsResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Rooms) != 2 {
+		t.Fatalf("expected 2 open avalon lobbies, got %+v", resp.Rooms)
+	}
+	if resp.Rooms[0].RoomCode != busy.RoomCode || resp.Rooms[0].Players != 2 {
+		t.Errorf("expected fullest room %s first, got %+v", busy.RoomCode, resp.Rooms[0])
+	}
+	if resp.Rooms[0].Title != "Busy's room" {
+		t.Errorf("expected default title, got %q", resp.Rooms[0].Title)
+	}
+	if resp.Rooms[1].RoomCode != quiet.RoomCode || resp.Rooms[1].Title != "Quiet table" {
+		t.Errorf("expected %s second, got %+v", quiet.RoomCode, resp.Rooms[1])
+	}
+	for _, room := range resp.Rooms {
+		if room.RoomCode == full.RoomCode {
+			t.Errorf("full room %s should not be listed", full.RoomCode)
+		}
+	}
+
+	json.Unmarshal(listRooms(s, "public=true").Body.Bytes(), &resp)
+	if len(resp.Rooms) != 3 {
+		t.Errorf("expected 3 open lobbies across games, got %d", len(resp.Rooms))
+	}
+
+	if rec := listRooms(s, ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without public=true, got %d", rec.Code)
+	}
+	if rec := listRooms(s, "public=true&game=chess"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown game, got %d", rec.Code)
+	}
+}
+
+func TestHandleQuickMatch(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+
+	// No lobby yet, so the first player opens one
+	first := quickMatch(t, s, "Alice", "avalon")
+	if !first.Created {
+		t.Fatal("expected a new room")
+	}
+	room, err := s.store.GetRoom(first.RoomCode)
+	if err != nil {
+		t.Fatalf("expected room in store: %v", err)
+	}
+	if state := room.GetState(); !state.Public || state.HostID != first.PlayerID {
+		t.Errorf("expected public room hosted by the player, got %+v", state)
+	}
+
+	// A fuller lobby and a password-protected one are preferred in that order
+	fuller := createRoom(t, s, CreateRoomRequest{GameType: "avalon", DisplayName: "Bob", Public: true})
+	fullerRoom, _ := s.store.GetRoom(fuller.RoomCode)
+	fullerRoom.AddPlayer(core.NewPlayer("Carol"))
+	locked := createRoom(t, s, CreateRoomRequest{GameType: "avalon", DisplayName: "Dave", Public: true, Password: "secret"})
+	lockedRoom, _ := s.store.GetRoom(locked.RoomCode)
+	lockedRoom.AddPlayer(core.NewPlayer("Erin"))
+	lockedRoom.AddPlayer(core.NewPlayer("Frank"))
+
+	second := quickMatch(t, s, "Grace", "avalon")
+	if second.Created || second.RoomCode != fuller.RoomCode {
+		t.Errorf("expected to join fullest open room %s, got %+v", fuller.RoomCode, second)
+	}
+	if _, err := fullerRoom.GetPlayer(second.PlayerID); err != nil {
+		t.Errorf("expected player in room: %v", err)
+	}
+
+	// A fuller lobby everyone has left is a ghost room and never matches
+	ghost := createRoom(t, s, CreateRoomRequest{GameType: "avalon", DisplayName: "Ivan", Public: true})
+	ghostRoom, _ := s.store.GetRoom(ghost.RoomCode)
+	for _, name := range []string{"Judy", "Mallory", "Niaj"} {
+		ghostRoom.AddPlayer(core.NewPlayer(name))
+	}
+	for _, player := range ghostRoom.GetPlayers() {
+		player.Disconnect()
+	}
+	if ghostly := quickMatch(t, s, "Olivia", "avalon"); ghostly.RoomCode != fuller.RoomCode {
+		t.Errorf("expected to skip the abandoned room for %s, got %+v", fuller.RoomCode, ghostly)
+	}
+	var listed ListRoomsResponse
+	json.Unmarshal(listRooms(s, "public=true").Body.Bytes(), &listed)
+	for _, room := range listed.Rooms {
+		if room.RoomCode == ghost.RoomCode {
+			t.Error("expected the abandoned room to be left out of the browser")
+		}
+	}
+
+	// Other games never match
+	third := quickMatch(t, s, "Heidi", "werewolf")
+	if !third.Created {
+		t.Errorf("expected a new werewolf room, got %+v", third)
+	}
+}
+
+func TestHandleQuickMatch_OneSeatPerProfile(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+	alice := createProfile(t, s, CreateProfileRequest{DisplayName: "Alice"})
+
+	match := func() QuickMatchResponse {
+		body, _ := json.Marshal(QuickMatchRequest{GameType: "avalon"})
+		req := httptest.NewRequest(http.MethodPost, "/api/quickmatch", bytes.NewBuffer(body))
+		req.Header.Set("X-Profile-Key", alice.Key)
+		rec := httptest.NewRecorder()
+		s.HandleQuickMatch(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("quick-match: expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp QuickMatchResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return resp
+	}
+
+	// The profile's own lobby is skipped rather than joined a second time
+	first := match()
+	second := match()
+	if !second.Created || second.RoomCode == first.RoomCode {
+		t.Errorf("expected a new room instead of a second seat in %s, got %+v", first.RoomCode, second)
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/KonradHerman/roundtable/internal/core"
 )
@@ -17,11 +18,16 @@ const (
 	ClientMsgAuthenticate = "authenticate"
 	ClientMsgAction       = "action"
 	ClientMsgPing         = "ping"
+	ClientMsgChat         = "chat"  // Payload is a core.ChatMessage
+	ClientMsgReact        = "react" // Payload is a core.Reaction
 )
 
 // AuthenticatePayload is sent when a client connects or reconnects.
+// ProtocolVersion is omitted by v1 clients.
 type AuthenticatePayload struct {
-	SessionToken string `json:"sessionToken"`
+	SessionToken    string   `json:"sessionToken"`
+	ProtocolVersion int      `json:"protocolVersion,omitempty"`
+	Capabilities    []string `json:"capabilities,omitempty"`
 }
 
 // ActionPayload wraps a game action.
@@ -30,9 +36,14 @@ type ActionPayload struct {
 }
 
 // ServerMessage represents messages sent from server to client.
+// Messages built with NewServerMessage carry an encoding cache that is shared
+// by every copy, so broadcasting one message encodes it once per wire format.
 type ServerMessage struct {
 	Type    string          `json:"type"`
 	Payload json.RawMessage `json:"payload,omitempty"`
+
+	cache      *encodingCache
+	closeAfter *closeRequest // Close the connection once this message is written
 }
 
 // Server message types
@@ -43,12 +54,21 @@ const (
 	ServerMsgEvents        = "events" // Batch for reconnection
 	ServerMsgError         = "error"
 	ServerMsgPong          = "pong"
+	ServerMsgNotice        = "notice"            // Operator announcement (protocol v2+)
+	ServerMsgRestarting    = "server_restarting" // Server is shutting down (protocol v2+)
+	ServerMsgChat          = "chat"              // Chat message (chat capability)
+	ServerMsgChatHistory   = "chat_history"      // Chat channels and history (chat capability)
+	ServerMsgReaction      = "reaction"          // Ephemeral reaction (reactions capability)
 )
 
-// AuthenticatedPayload confirms successful authentication.
+// AuthenticatedPayload confirms successful authentication and reports the
+// negotiated protocol version and capabilities.
 type AuthenticatedPayload struct {
-	PlayerID  string         `json:"playerId"`
-	RoomState core.RoomState `json:"roomState"`
+	PlayerID        string         `json:"playerId"`
+	RoomState       core.RoomState `json:"roomState"`
+	ProtocolVersion int            `json:"protocolVersion"`
+	Capabilities    []string       `json:"capabilities"`
+	SessionToken    string         `json:"sessionToken,omitempty"` // Rotated token (token_rotation capability)
 }
 
 // RoomStatePayload contains current room state.
@@ -66,11 +86,56 @@ type EventsPayload struct {
 	Events []core.GameEvent `json:"events"`
 }
 
+// ChatHistoryPayload lists the channels a player can post to, apart from
+// whispers, and the chat messages they can see.
+type ChatHistoryPayload struct {
+	Channels []string         `json:"channels"`
+	Messages []core.GameEvent `json:"messages"`
+}
+
+// ReactionPayload contains a reaction, which is not kept in any history.
+type ReactionPayload struct {
+	Reaction core.Reaction `json:"reaction"`
+}
+
 // ErrorPayload contains error information.
+// Code is a machine-readable error code (protocol v2+).
 type ErrorPayload struct {
+	Code    string `json:"code,omitempty"`
 	Message string `json:"message"`
 }
 
+// NoticePayload is an announcement from the server operators, such as
+// upcoming maintenance.
+type NoticePayload struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// ServerRestartingPayload is sent before the server closes connections for a
+// restart. Clients should reconnect after the hinted delay; their room and
+// session survive the restart when the server persists rooms.
+type ServerRestartingPayload struct {
+	Message          string `json:"message"`
+	ReconnectAfterMs int64  `json:"reconnectAfterMs"`
+}
+
+// Notice levels
+const (
+	NoticeLevelInfo    = "info"
+	NoticeLevelWarning = "warning"
+)
+
+// Error codes
+const (
+	ErrorCodeInvalidPayload = "invalid_payload"
+	ErrorCodeActionFailed   = "action_failed"
+	ErrorCodeUnknownMessage = "unknown_message"
+	ErrorCodeRateLimited    = "rate_limited"
+	ErrorCodeChatFailed     = "chat_failed"
+	ErrorCodeReactionFailed = "reaction_failed"
+)
+
 // Helper functions to create server messages
 
 func NewServerMessage(msgType string, payload interface{}) (ServerMessage, error) {
@@ -82,13 +147,17 @@ func NewServerMessage(msgType string, payload interface{}) (ServerMessage, error
 	return ServerMessage{
 		Type:    msgType,
 		Payload: payloadBytes,
+		cache:   &encodingCache{entries: make(map[encodingKey]encodedMessage)},
 	}, nil
 }
 
-func NewAuthenticatedMessage(playerID string, roomState core.RoomState) (ServerMessage, error) {
+func NewAuthenticatedMessage(playerID string, roomState core.RoomState, protocolVersion int, capabilities []string, sessionToken string) (ServerMessage, error) {
 	return NewServerMessage(ServerMsgAuthenticated, AuthenticatedPayload{
-		PlayerID:  playerID,
-		RoomState: roomState,
+		PlayerID:        playerID,
+		RoomState:       roomState,
+		ProtocolVersion: protocolVersion,
+		Capabilities:    capabilities,
+		SessionToken:    sessionToken,
 	})
 }
 
@@ -110,12 +179,52 @@ func NewEventsMessage(events []core.GameEvent) (ServerMessage, error) {
 	})
 }
 
+func NewChatMessage(event core.GameEvent) (ServerMessage, error) {
+	return NewServerMessage(ServerMsgChat, EventPayload{
+		Event: event,
+	})
+}
+
+func NewChatHistoryMessage(channels []string, messages []core.GameEvent) (ServerMessage, error) {
+	return NewServerMessage(ServerMsgChatHistory, ChatHistoryPayload{
+		Channels: channels,
+		Messages: messages,
+	})
+}
+
+func NewReactionMessage(reaction core.Reaction) (ServerMessage, error) {
+	return NewServerMessage(ServerMsgReaction, ReactionPayload{
+		Reaction: reaction,
+	})
+}
+
+func NewNoticeMessage(level string, message string) (ServerMessage, error) {
+	return NewServerMessage(ServerMsgNotice, NoticePayload{
+		Level:   level,
+		Message: message,
+	})
+}
+
+func NewServerRestartingMessage(reconnectAfter time.Duration) (ServerMessage, error) {
+	return NewServerMessage(ServerMsgRestarting, ServerRestartingPayload{
+		Message:          "The server is restarting, reconnecting shortly",
+		ReconnectAfterMs: reconnectAfter.Milliseconds(),
+	})
+}
+
 func NewErrorMessage(errMsg string) (ServerMessage, error) {
 	return NewServerMessage(ServerMsgError, ErrorPayload{
 		Message: errMsg,
 	})
 }
 
+func NewCodedErrorMessage(code string, errMsg string) (ServerMessage, error) {
+	return NewServerMessage(ServerMsgError, ErrorPayload{
+		Code:    code,
+		Message: errMsg,
+	})
+}
+
 func NewPongMessage() (ServerMessage, error) {
 	return ServerMessage{Type: ServerMsgPong}, nil
 }
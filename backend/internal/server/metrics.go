@@ -0,0 +1,85 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// serverCollector exports gauges computed from live server state at scrape
+// time: rooms, connections and rate limiter counters.
+type serverCollector struct {
+	server *Server
+
+	rooms           *prometheus.Desc
+	connections     *prometheus.Desc
+	rateLimitChecks *prometheus.Desc
+}
+
+func newServerCollector(server *Server) *serverCollector {
+	return &serverCollector{
+		server: server,
+		rooms: prometheus.NewDesc(
+			"roundtable_rooms",
+			"Rooms currently held by the store.",
+			[]string{"status", "game_type"}, nil,
+		),
+		connections: prometheus.NewDesc(
+			"roundtable_connections",
+			"Open client connections.",
+			[]string{"transport"}, nil,
+		),
+		rateLimitChecks: prometheus.NewDesc(
+			"roundtable_rate_limit_requests_total",
+			"Requests checked by rate limiters.",
+			[]string{"scope", "result"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *serverCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.rooms
+	ch <- c.connections
+	ch <- c.rateLimitChecks
+}
+
+// Collect implements prometheus.Collector.
+func (c *serverCollector) Collect(ch chan<- prometheus.Metric) {
+	type roomKey struct {
+		status   core.RoomStatus
+		gameType string
+	}
+
+	rooms, err := c.server.store.ListRooms()
+	if err != nil {
+		slog.Error("failed to list rooms for metrics", "error", err)
+	}
+
+	roomCounts := make(map[roomKey]int)
+	for _, room := range rooms {
+		status, _, _ := room.GetCleanupInfo()
+		roomCounts[roomKey{status: status, gameType: room.GameType}]++
+	}
+	for key, count := range roomCounts {
+		ch <- prometheus.MustNewConstMetric(c.rooms, prometheus.GaugeValue, float64(count), string(key.status), key.gameType)
+	}
+
+	connectionCounts := c.server.connMgr.ConnectionCounts()
+	for _, transport := range []string{"websocket", "sse"} {
+		ch <- prometheus.MustNewConstMetric(c.connections, prometheus.GaugeValue, float64(connectionCounts[transport]), transport)
+	}
+
+	for _, stats := range c.server.RateLimitStats() {
+		ch <- prometheus.MustNewConstMetric(c.rateLimitChecks, prometheus.CounterValue, float64(stats.Allowed), stats.Name, "allowed")
+		ch <- prometheus.MustNewConstMetric(c.rateLimitChecks, prometheus.CounterValue, float64(stats.Throttled), stats.Name, "throttled")
+	}
+}
+
+// HandleMetrics serves Prometheus metrics.
+func (s *Server) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.metrics.Handler().ServeHTTP(w, r)
+}
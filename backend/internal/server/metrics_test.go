@@ -0,0 +1,43 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+func TestHandleMetrics(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	setupWerewolfGame(t, server)
+
+	createReq := httptest.NewRequest(http.MethodPost, "/api/rooms",
+		strings.NewReader(`{"gameType":"avalon","displayName":"Host"}`))
+	server.HandleCreateRoom(httptest.NewRecorder(), createReq)
+
+	rec := httptest.NewRecorder()
+	server.HandleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{
+		`roundtable_rooms{game_type="werewolf",status="playing"} 1`,
+		`roundtable_rooms{game_type="avalon",status="waiting"} 1`,
+		`roundtable_connections{transport="websocket"} 0`,
+		`roundtable_events_appended_total{event_type="player_joined",game_type="avalon"} 1`,
+		`roundtable_rate_limit_requests_total{result="throttled",scope="create_room"} 0`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected metrics output to contain %q", want)
+		}
+	}
+}
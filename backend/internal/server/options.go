@@ -0,0 +1,121 @@
+package server
+
+import (
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/ratelimit"
+	"github.com/KonradHerman/roundtable/internal/util"
+)
+
+// Options configures a Server.
+type Options struct {
+	RateLimits  RateLimits
+	Connections ConnectionOptions
+
+	// AllowedOrigins are the origin patterns WebSocket upgrades are accepted
+	// from. Empty allows localhost on any port, for development.
+	AllowedOrigins []string
+
+	// DefaultMaxPlayers is the room size used when create requests omit it.
+	DefaultMaxPlayers int
+
+	// TrustProxyHeaders makes client IPs come from X-Forwarded-For.
+	// Only enable this behind a reverse proxy that sets the header.
+	TrustProxyHeaders bool
+
+	// AdminToken is the bearer token for the /api/admin endpoints.
+	// The admin API is disabled when it is empty.
+	AdminToken string
+
+	// SessionKey signs session tokens. When empty a random key is generated,
+	// so sessions don't survive a restart.
+	SessionKey []byte
+
+	// SessionTTL is how long session tokens are valid. Clients using token
+	// rotation get a fresh token on every reconnect.
+	SessionTTL time.Duration
+
+	// InviteTTL is how long invite links are valid.
+	InviteTTL time.Duration
+
+	// RoomCodes configures how room codes are allocated.
+	RoomCodes util.CodeOptions
+
+	// PublicURL is the frontend's base URL, used in join links and QR
+	// codes. Empty uses the host the request was made to.
+	PublicURL string
+
+	// Scoring holds the session scoring rules per game type. Game types
+	// without rules use core.DefaultScoringRules.
+	Scoring map[string]core.ScoringRules
+}
+
+// RateLimits configures the token buckets protecting the API.
+// A zero limit disables that bucket.
+type RateLimits struct {
+	CreateRoom ratelimit.Limit `yaml:"createRoom"` // Per client IP
+	JoinRoom   ratelimit.Limit `yaml:"joinRoom"`   // Per client IP, joining a room
+	Connect    ratelimit.Limit `yaml:"connect"`    // Per client IP, WebSocket upgrades and SSE streams
+	Reads      ratelimit.Limit `yaml:"reads"`      // Per client IP, read-only lookups such as rooms, stats and QR codes
+	SignIn     ratelimit.Limit `yaml:"signIn"`     // Per client IP, profile sign-ins with a PIN
+	Actions    ratelimit.Limit `yaml:"actions"`    // Per session, shared by WebSocket and HTTP actions
+	Chat       ratelimit.Limit `yaml:"chat"`       // Per session, chat messages over any transport
+	Reactions  ratelimit.Limit `yaml:"reactions"`  // Per session, emoji and pointing over any transport
+	Writes     ratelimit.Limit `yaml:"writes"`     // Per client IP, other changes such as starting games, kicks, invites and profile edits
+	Admin      ratelimit.Limit `yaml:"admin"`      // Per client IP, admin API requests
+}
+
+// ConnectionOptions configures WebSocket and SSE connections.
+// Zero values use the defaults.
+type ConnectionOptions struct {
+	// SendBuffer is how many outgoing messages are queued per connection
+	// before broadcasts to it are dropped.
+	SendBuffer int `yaml:"sendBuffer"`
+
+	// AuthTimeout is how long a new WebSocket has to authenticate.
+	AuthTimeout time.Duration `yaml:"authTimeout"`
+}
+
+// DefaultConnectionOptions returns the options used by NewConnectionManager.
+func DefaultConnectionOptions() ConnectionOptions {
+	return ConnectionOptions{
+		SendBuffer:  256,
+		AuthTimeout: 10 * time.Second,
+	}
+}
+
+// withDefaults fills in zero values.
+func (o ConnectionOptions) withDefaults() ConnectionOptions {
+	defaults := DefaultConnectionOptions()
+	if o.SendBuffer <= 0 {
+		o.SendBuffer = defaults.SendBuffer
+	}
+	if o.AuthTimeout <= 0 {
+		o.AuthTimeout = defaults.AuthTimeout
+	}
+	return o
+}
+
+// DefaultOptions returns the options used by NewServer.
+func DefaultOptions() Options {
+	return Options{
+		Connections:       DefaultConnectionOptions(),
+		DefaultMaxPlayers: 10,
+		SessionTTL:        24 * time.Hour,
+		InviteTTL:         24 * time.Hour,
+		RoomCodes:         util.DefaultCodeOptions(),
+		RateLimits: RateLimits{
+			CreateRoom: ratelimit.PerMinute(10, 10),
+			JoinRoom:   ratelimit.PerMinute(30, 15),
+			Connect:    ratelimit.PerMinute(120, 60),
+			Reads:      ratelimit.PerMinute(600, 120),
+			SignIn:     ratelimit.PerMinute(10, 10),
+			Actions:    ratelimit.Limit{Rate: 10, Burst: 20},
+			Chat:       ratelimit.Limit{Rate: 1, Burst: 5},
+			Reactions:  ratelimit.Limit{Rate: 2, Burst: 6},
+			Writes:     ratelimit.PerMinute(120, 30),
+			Admin:      ratelimit.PerMinute(120, 60),
+		},
+	}
+}
@@ -0,0 +1,222 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultOriginPatterns are allowed when no origins are configured, for
+// development.
+var defaultOriginPatterns = []string{"localhost:*", "127.0.0.1:*"}
+
+// CORS settings sent in preflight responses.
+const (
+	corsAllowedMethods = "GET, HEAD, POST, PUT, PATCH, DELETE"
+	corsAllowedHeaders = "Authorization, Content-Type, X-Profile-Key, X-Session-Token"
+	corsMaxAge         = "600" // Seconds browsers may cache a preflight
+)
+
+// OriginPolicy decides which browser origins may call the API and open
+// WebSockets. A single "*" allows any origin; other patterns are:
+//
+//	https://example.com      exact origin
+//	https://*.example.com    any subdomain of example.com
+//	http://localhost:*       any port
+//	example.com              either scheme
+type OriginPolicy struct {
+	allowAll bool
+	patterns []originPattern
+}
+
+// originPattern is a parsed origin pattern. An empty scheme matches http and
+// https; an empty port matches the scheme's default port; "*" matches any port.
+type originPattern struct {
+	scheme string
+	host   string // "*.example.com" matches subdomains
+	port   string
+}
+
+// NewOriginPolicy parses origin patterns. No patterns allows localhost on any
+// port.
+func NewOriginPolicy(patterns []string) (*OriginPolicy, error) {
+	if len(patterns) == 0 {
+		patterns = defaultOriginPatterns
+	}
+
+	policy := &OriginPolicy{}
+	for _, raw := range patterns {
+		if raw == "*" {
+			policy.allowAll = true
+			continue
+		}
+		pattern, err := parseOriginPattern(raw)
+		if err != nil {
+			return nil, err
+		}
+		policy.patterns = append(policy.patterns, pattern)
+	}
+	return policy, nil
+}
+
+// parseOriginPattern parses one pattern such as "https://*.example.com:8443".
+func parseOriginPattern(raw string) (originPattern, error) {
+	var pattern originPattern
+
+	rest := strings.ToLower(strings.TrimSpace(raw))
+	if scheme, hostport, ok := strings.Cut(rest, "://"); ok {
+		if scheme != "http" && scheme != "https" {
+			return originPattern{}, fmt.Errorf("origin %q: scheme must be http or https", raw)
+		}
+		pattern.scheme = scheme
+		rest = hostport
+	}
+
+	if strings.ContainsAny(rest, "/?#@") {
+		return originPattern{}, fmt.Errorf("origin %q: must not contain a path", raw)
+	}
+
+	pattern.host = rest
+	if host, port, err := net.SplitHostPort(rest); err == nil {
+		pattern.host, pattern.port = host, port
+	}
+
+	// "*" may only appear as the leftmost label
+	domain, _ := strings.CutPrefix(pattern.host, "*.")
+	if domain == "" || strings.Contains(domain, "*") {
+		return originPattern{}, fmt.Errorf("origin %q: invalid host", raw)
+	}
+
+	return pattern, nil
+}
+
+// AllowsAll reports whether every origin is allowed.
+func (p *OriginPolicy) AllowsAll() bool {
+	return p.allowAll
+}
+
+// Allowed reports whether an Origin header value is allowed.
+func (p *OriginPolicy) Allowed(origin string) bool {
+	if p.allowAll {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+
+	for _, pattern := range p.patterns {
+		if pattern.matches(scheme, host, port) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedRequest reports whether a request may be served cross-origin.
+// Requests without an Origin header (non-browser clients) and same-origin
+// requests are always allowed.
+func (p *OriginPolicy) AllowedRequest(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || sameOrigin(r) {
+		return true
+	}
+	return p.Allowed(origin)
+}
+
+// sameOrigin reports whether the request's Origin is the host it was sent to.
+func sameOrigin(r *http.Request) bool {
+	u, err := url.Parse(r.Header.Get("Origin"))
+	return err == nil && u.Host != "" && strings.EqualFold(u.Host, r.Host)
+}
+
+// matches reports whether an origin's parts match the pattern.
+func (o originPattern) matches(scheme, host, port string) bool {
+	if o.scheme != "" && o.scheme != scheme {
+		return false
+	}
+
+	switch o.port {
+	case "*":
+	case "":
+		if port != "" && port != defaultPort(scheme) {
+			return false
+		}
+	default:
+		if port != o.port && !(port == "" && o.port == defaultPort(scheme)) {
+			return false
+		}
+	}
+
+	if domain, ok := strings.CutPrefix(o.host, "*."); ok {
+		return strings.HasSuffix(host, "."+domain)
+	}
+	return host == o.host
+}
+
+// defaultPort returns the implicit port of a scheme.
+func defaultPort(scheme string) string {
+	if scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
+
+// CORS wraps a handler with CORS headers for the server's origin policy.
+// Preflight requests are answered directly.
+func (s *Server) CORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		// Responses differ by origin, so caches must not mix them up
+		w.Header().Add("Vary", "Origin")
+		if preflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		// Non-browser and same-origin requests need no CORS headers
+		if origin == "" || sameOrigin(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !s.origins.Allowed(origin) {
+			slog.Warn("rejected CORS request", "origin", origin, "path", r.URL.Path)
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			// Serve without CORS headers; the browser withholds the response
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if s.origins.AllowsAll() {
+			// Credentials cannot be combined with a wildcard origin
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			w.Header().Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			w.Header().Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
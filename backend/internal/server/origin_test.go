@@ -0,0 +1,242 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/store"
+	"nhooyr.io/websocket"
+)
+
+func TestOriginPolicy_Allowed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		patterns []string
+		origin   string
+		want     bool
+	}{
+		{name: "default allows localhost", patterns: nil, origin: "http://localhost:5173", want: true},
+		{name: "default allows loopback", patterns: nil, origin: "http://127.0.0.1:3000", want: true},
+		{name: "default rejects others", patterns: nil, origin: "https://evil.example", want: false},
+		{name: "wildcard", patterns: []string{"*"}, origin: "https://anything.example", want: true},
+		{name: "exact", patterns: []string{"https://app.example.com"}, origin: "https://app.example.com", want: true},
+		{name: "exact case-insensitive", patterns: []string{"https://App.Example.com"}, origin: "https://app.example.com", want: true},
+		{name: "exact default port", patterns: []string{"https://app.example.com"}, origin: "https://app.example.com:443", want: true},
+		{name: "exact other port", patterns: []string{"https://app.example.com"}, origin: "https://app.example.com:8443", want: false},
+		{name: "exact other scheme", patterns: []string{"https://app.example.com"}, origin: "http://app.example.com", want: false},
+		{name: "exact other host", patterns: []string{"https://app.example.com"}, origin: "https://app.example.com.evil.example", want: false},
+		{name: "explicit port", patterns: []string{"https://app.example.com:8443"}, origin: "https://app.example.com:8443", want: true},
+		{name: "any port", patterns: []string{"http://localhost:*"}, origin: "http://localhost:4173", want: true},
+		{name: "any port without port", patterns: []string{"http://localhost:*"}, origin: "http://localhost", want: true},
+		{name: "subdomain", patterns: []string{"https://*.example.com"}, origin: "https://pr-42.example.com", want: true},
+		{name: "nested subdomain", patterns: []string{"https://*.example.com"}, origin: "https://a.b.example.com", want: true},
+		{name: "subdomain excludes apex", patterns: []string{"https://*.example.com"}, origin: "https://example.com", want: false},
+		{name: "subdomain suffix attack", patterns: []string{"https://*.example.com"}, origin: "https://evilexample.com", want: false},
+		{name: "any scheme", patterns: []string{"example.com"}, origin: "http://example.com", want: true},
+		{name: "one of many", patterns: []string{"https://a.example", "https://b.example"}, origin: "https://b.example", want: true},
+		{name: "null origin", patterns: []string{"https://a.example"}, origin: "null", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy, err := NewOriginPolicy(tt.patterns)
+			if err != nil {
+				t.Fatalf("NewOriginPolicy() error = %v", err)
+			}
+			if got := policy.Allowed(tt.origin); got != tt.want {
+				t.Errorf("Allowed(%q) = %v, want %v", tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewOriginPolicy_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, pattern := range []string{"", "ftp://example.com", "https://example.com/app", "https://ex*mple.com", "https://*.*.example.com", "https://*"} {
+		if _, err := NewOriginPolicy([]string{pattern}); err == nil {
+			t.Errorf("expected error for pattern %q", pattern)
+		}
+	}
+}
+
+func TestServer_CORS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		allowedOrigins  []string
+		method          string
+		origin          string
+		preflight       bool
+		wantStatusCode  int
+		wantAllowOrigin string
+		wantCredentials bool
+		wantNextCalled  bool
+	}{
+		{
+			name:            "allowed request",
+			allowedOrigins:  []string{"https://a.example", "https://b.example"},
+			method:          http.MethodPost,
+			origin:          "https://b.example",
+			wantStatusCode:  http.StatusOK,
+			wantAllowOrigin: "https://b.example",
+			wantCredentials: true,
+			wantNextCalled:  true,
+		},
+		{
+			name:           "rejected request is served without headers",
+			allowedOrigins: []string{"https://a.example"},
+			method:         http.MethodGet,
+			origin:         "https://evil.example",
+			wantStatusCode: http.StatusOK,
+			wantNextCalled: true,
+		},
+		{
+			name:           "no origin",
+			allowedOrigins: []string{"https://a.example"},
+			method:         http.MethodGet,
+			wantStatusCode: http.StatusOK,
+			wantNextCalled: true,
+		},
+		{
+			name:            "wildcard",
+			allowedOrigins:  []string{"*"},
+			method:          http.MethodGet,
+			origin:          "https://anything.example",
+			wantStatusCode:  http.StatusOK,
+			wantAllowOrigin: "*",
+			wantNextCalled:  true,
+		},
+		{
+			name:            "allowed preflight",
+			allowedOrigins:  []string{"https://*.example.com"},
+			method:          http.MethodOptions,
+			origin:          "https://admin.example.com",
+			preflight:       true,
+			wantStatusCode:  http.StatusNoContent,
+			wantAllowOrigin: "https://admin.example.com",
+			wantCredentials: true,
+		},
+		{
+			name:           "rejected preflight",
+			allowedOrigins: []string{"https://a.example"},
+			method:         http.MethodOptions,
+			origin:         "https://evil.example",
+			preflight:      true,
+			wantStatusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			options := DefaultOptions()
+			options.AllowedOrigins = tt.allowedOrigins
+			server := NewServerWithOptions(store.NewMemoryStore(), options)
+
+			nextCalled := false
+			handler := server.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+			}))
+
+			req := httptest.NewRequest(tt.method, "http://api.example.net/api/rooms", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+				req.Header.Set("Access-Control-Request-Headers", "authorization")
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+			if nextCalled != tt.wantNextCalled {
+				t.Errorf("expected next called %v, got %v", tt.wantNextCalled, nextCalled)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowOrigin {
+				t.Errorf("expected Allow-Origin %q, got %q", tt.wantAllowOrigin, got)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Credentials") == "true"; got != tt.wantCredentials {
+				t.Errorf("expected credentials %v, got %v", tt.wantCredentials, got)
+			}
+			if vary := rec.Header().Values("Vary"); len(vary) == 0 || vary[0] != "Origin" {
+				t.Errorf("expected Vary: Origin, got %v", vary)
+			}
+
+			if tt.preflight && tt.wantStatusCode == http.StatusNoContent {
+				if methods := rec.Header().Get("Access-Control-Allow-Methods"); !strings.Contains(methods, http.MethodDelete) {
+					t.Errorf("expected DELETE in allowed methods, got %q", methods)
+				}
+				if headers := rec.Header().Get("Access-Control-Allow-Headers"); !strings.Contains(headers, "Authorization") {
+					t.Errorf("expected Authorization in allowed headers, got %q", headers)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleWebSocket_OriginPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		origin  string
+		wantErr bool
+	}{
+		{name: "allowed origin", origin: "https://play.example.com"},
+		{name: "rejected origin", origin: "https://evil.example", wantErr: true},
+		{name: "no origin", origin: ""},
+	}
+
+	options := DefaultOptions()
+	options.AllowedOrigins = []string{"https://*.example.com"}
+	server := NewServerWithOptions(store.NewMemoryStore(), options)
+	room, _ := setupWerewolfGame(t, server)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/rooms/{code}/ws", server.HandleWebSocket)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/rooms/" + room.ID + "/ws"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			header := http.Header{}
+			if tt.origin != "" {
+				header.Set("Origin", tt.origin)
+			}
+			conn, resp, err := websocket.Dial(ctx, wsURL, &websocket.DialOptions{HTTPHeader: header})
+			if tt.wantErr {
+				if err == nil {
+					conn.Close(websocket.StatusNormalClosure, "")
+					t.Fatal("expected dial to fail")
+				}
+				if resp == nil || resp.StatusCode != http.StatusForbidden {
+					t.Errorf("expected 403, got %v", resp)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to dial: %v", err)
+			}
+			conn.Close(websocket.StatusNormalClosure, "")
+		})
+	}
+}
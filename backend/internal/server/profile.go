@@ -0,0 +1,249 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/profile"
+)
+
+// SetProfiles replaces the server's in-memory profile store, e.g. with one
+// persisted to the data directory. Call it before serving requests.
+func (s *Server) SetProfiles(profiles *profile.Store) {
+	s.profiles = profiles
+}
+
+// requestProfile returns the profile of the request's X-Profile-Key header,
+// or nil if the request has none.
+func (s *Server) requestProfile(r *http.Request) (*profile.Profile, error) {
+	key := r.Header.Get("X-Profile-Key")
+	if key == "" {
+		return nil, nil
+	}
+
+	p, err := s.profiles.Authenticate(key)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// playerName returns the display name a player joins with: the requested
+// name, or the profile's when none was given.
+func playerName(requested string, p *profile.Profile) (string, error) {
+	if requested == "" && p != nil {
+		requested = p.DisplayName
+	}
+	return validateDisplayName(requested)
+}
+
+// linkProfile records the player in its profile's room memberships.
+func (s *Server) linkProfile(roomCode string, player *core.Player) {
+	if player.ProfileID == "" {
+		return
+	}
+	if err := s.profiles.Link(player.ProfileID, roomCode, player.ID); err != nil {
+		slog.Error("failed to link profile", "profileID", player.ProfileID, "roomCode", roomCode, "error", err)
+	}
+}
+
+// CreateProfileRequest is the payload for creating a profile.
+type CreateProfileRequest struct {
+	DisplayName string `json:"displayName"`
+	AvatarColor string `json:"avatarColor,omitempty"` // Random if empty
+	Username    string `json:"username,omitempty"`    // For signing in on other devices
+	PIN         string `json:"pin,omitempty"`         // Required with a username
+}
+
+// LoginProfileRequest is the payload for signing into a profile.
+type LoginProfileRequest struct {
+	Username string `json:"username"`
+	PIN      string `json:"pin"`
+}
+
+// ProfileResponse is the response for creating or signing into a profile.
+// The key is only ever returned here; clients send it as X-Profile-Key.
+type ProfileResponse struct {
+	Profile profile.Profile `json:"profile"`
+	Key     string          `json:"key"`
+}
+
+// profileError maps a profile store error to a response status.
+func profileError(err error) int {
+	switch {
+	case errors.Is(err, profile.ErrInvalidKey), errors.Is(err, profile.ErrInvalidLogin):
+		return http.StatusUnauthorized
+	case errors.Is(err, profile.ErrLocked):
+		return http.StatusTooManyRequests
+	case errors.Is(err, profile.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, profile.ErrUsernameTaken):
+		return http.StatusConflict
+	case errors.Is(err, profile.ErrInvalidUsername), errors.Is(err, profile.ErrInvalidPIN),
+		errors.Is(err, profile.ErrInvalidAvatarColor), errors.Is(err, profile.ErrInvalidPreferences):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeProfileError responds with a profile store error.
+func writeProfileError(w http.ResponseWriter, err error) {
+	status := profileError(err)
+	if status == http.StatusInternalServerError {
+		slog.Error("profile request failed", "error", err)
+		http.Error(w, "Profile request failed", status)
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// HandleCreateProfile creates a profile and returns the device key for it.
+func (s *Server) HandleCreateProfile(w http.ResponseWriter, r *http.Request) {
+	// Limit request body to 1MB
+	r.Body = http.MaxBytesReader(w, r.Body, 1*1024*1024)
+
+	var req CreateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Request too large or malformed", http.StatusBadRequest)
+		return
+	}
+
+	displayName, err := validateDisplayName(req.DisplayName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p, key, err := s.profiles.Create(profile.New{
+		DisplayName: displayName,
+		AvatarColor: req.AvatarColor,
+		Username:    req.Username,
+		PIN:         req.PIN,
+	})
+	if err != nil {
+		writeProfileError(w, err)
+		return
+	}
+
+	slog.Info("created profile", "profileID", p.ID, "withUsername", p.Username != "")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ProfileResponse{Profile: p, Key: key})
+}
+
+// HandleLoginProfile signs a new device into a profile with its username and
+// PIN. Failed PINs lock the username out for the client's IP only.
+func (s *Server) HandleLoginProfile(w http.ResponseWriter, r *http.Request) {
+	// Limit request body to 1MB
+	r.Body = http.MaxBytesReader(w, r.Body, 1*1024*1024)
+
+	var req LoginProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Request too large or malformed", http.StatusBadRequest)
+		return
+	}
+
+	p, key, err := s.profiles.Login(req.Username, req.PIN, clientIP(r, s.options.TrustProxyHeaders))
+	if err != nil {
+		writeProfileError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ProfileResponse{Profile: p, Key: key})
+}
+
+// authenticatedProfile returns the request's profile, responding with an
+// error if the request has no valid profile key.
+func (s *Server) authenticatedProfile(w http.ResponseWriter, r *http.Request) (*profile.Profile, bool) {
+	p, err := s.requestProfile(r)
+	if err != nil {
+		writeProfileError(w, err)
+		return nil, false
+	}
+	if p == nil {
+		http.Error(w, "Profile key required", http.StatusUnauthorized)
+		return nil, false
+	}
+	return p, true
+}
+
+// HandleGetProfile returns the caller's profile.
+func (s *Server) HandleGetProfile(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.authenticatedProfile(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// HandleUpdateProfile changes the caller's display name, avatar color or
+// preferences. Rooms joined before keep the old name and color.
+func (s *Server) HandleUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.authenticatedProfile(w, r)
+	if !ok {
+		return
+	}
+
+	// Limit request body to 1MB
+	r.Body = http.MaxBytesReader(w, r.Body, 1*1024*1024)
+
+	var changes profile.Changes
+	if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+		http.Error(w, "Request too large or malformed", http.StatusBadRequest)
+		return
+	}
+
+	if changes.DisplayName != nil {
+		displayName, err := validateDisplayName(*changes.DisplayName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		changes.DisplayName = &displayName
+	}
+
+	updated, err := s.profiles.Update(p.ID, changes)
+	if err != nil {
+		writeProfileError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// HandleLogoutProfile signs the caller's device out of its profile.
+func (s *Server) HandleLogoutProfile(w http.ResponseWriter, r *http.Request) {
+	if err := s.profiles.SignOut(r.Header.Get("X-Profile-Key")); err != nil {
+		writeProfileError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleDeleteProfile deletes the caller's profile and signs out all its
+// devices.
+func (s *Server) HandleDeleteProfile(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.authenticatedProfile(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.profiles.Delete(p.ID); err != nil {
+		writeProfileError(w, err)
+		return
+	}
+	if err := s.stats.Delete(p.ID); err != nil {
+		slog.Error("failed to delete profile stats", "profileID", p.ID, "error", err)
+	}
+
+	slog.Info("deleted profile", "profileID", p.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/profile"
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+// profileRequest sends a profile API request with an optional profile key.
+func profileRequest(handler http.HandlerFunc, method, key string, body interface{}) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, "/api/profiles", bytes.NewBuffer(data))
+	if key != "" {
+		req.Header.Set("X-Profile-Key", key)
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+// createProfile creates a profile and returns it with its key.
+func createProfile(t *testing.T, s *Server, req CreateProfileRequest) ProfileResponse {
+	t.Helper()
+
+	rec := profileRequest(s.HandleCreateProfile, http.MethodPost, "", req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create profile: expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ProfileResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	return resp
+}
+
+func TestHandleJoinRoom_WithProfile(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+	created := createProfile(t, s, CreateProfileRequest{DisplayName: "Alice", AvatarColor: "#22c55e"})
+	room := createRoom(t, s, CreateRoomRequest{GameType: "werewolf", DisplayName: "Host"})
+
+	// No display name: the profile's is used
+	body, _ := json.Marshal(JoinRoomRequest{})
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms/"+room.RoomCode+"/join", bytes.NewBuffer(body))
+	req.SetPathValue("code", room.RoomCode)
+	req.Header.Set("X-Profile-Key", created.Key)
+	rec := httptest.NewRecorder()
+	s.HandleJoinRoom(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp JoinRoomResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+
+	stored, _ := s.store.GetRoom(room.RoomCode)
+	player, err := stored.GetPlayer(resp.PlayerID)
+	if err != nil {
+		t.Fatalf("expected player in room: %v", err)
+	}
+	if player.DisplayName != "Alice" || player.ProfileID != created.Profile.ID || player.AvatarColor != "#22c55e" {
+		t.Errorf("expected player linked to profile, got %q %q %q", player.DisplayName, player.ProfileID, player.AvatarColor)
+	}
+
+	rec = profileRequest(s.HandleGetProfile, http.MethodGet, created.Key, nil)
+	var got profile.Profile
+	json.Unmarshal(rec.Body.Bytes(), &got)
+	if len(got.Memberships) != 1 || got.Memberships[0].RoomCode != room.RoomCode || got.Memberships[0].PlayerID != resp.PlayerID {
+		t.Errorf("expected membership of %s, got %+v", room.RoomCode, got.Memberships)
+	}
+
+	// The profile already has a seat, so it can't take another
+	req = httptest.NewRequest(http.MethodPost, "/api/rooms/"+room.RoomCode+"/join", bytes.NewBuffer(body))
+	req.SetPathValue("code", room.RoomCode)
+	req.Header.Set("X-Profile-Key", created.Key)
+	rec = httptest.NewRecorder()
+	s.HandleJoinRoom(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a second seat, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// A bad key is rejected rather than silently ignored
+	req = httptest.NewRequest(http.MethodPost, "/api/rooms/"+room.RoomCode+"/join", bytes.NewBuffer(body))
+	req.SetPathValue("code", room.RoomCode)
+	req.Header.Set("X-Profile-Key", "bogus")
+	rec = httptest.NewRecorder()
+	s.HandleJoinRoom(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for invalid profile key, got %d", rec.Code)
+	}
+}
+
+func TestHandleCreateRoom_WithProfile(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+	created := createProfile(t, s, CreateProfileRequest{DisplayName: "Alice"})
+
+	body, _ := json.Marshal(CreateRoomRequest{GameType: "werewolf", DisplayName: "Ali"})
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms", bytes.NewBuffer(body))
+	req.Header.Set("X-Profile-Key", created.Key)
+	rec := httptest.NewRecorder()
+	s.HandleCreateRoom(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp CreateRoomResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	room, _ := s.store.GetRoom(resp.RoomCode)
+	host, _ := room.GetPlayer(resp.PlayerID)
+	if host.DisplayName != "Ali" || host.ProfileID != created.Profile.ID {
+		t.Errorf("expected requested name with profile link, got %q %q", host.DisplayName, host.ProfileID)
+	}
+}
+
+func TestProfileHandlers(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+
+	rec := profileRequest(s.HandleCreateProfile, http.MethodPost, "", CreateProfileRequest{DisplayName: "Bob", Username: "bob"})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for username without PIN, got %d", rec.Code)
+	}
+
+	created := createProfile(t, s, CreateProfileRequest{DisplayName: "Bob", Username: "bob", PIN: "4321"})
+
+	rec = profileRequest(s.HandleCreateProfile, http.MethodPost, "", CreateProfileRequest{DisplayName: "Bobby", Username: "BOB", PIN: "1111"})
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected 409 for taken username, got %d", rec.Code)
+	}
+
+	rec = profileRequest(s.HandleLoginProfile, http.MethodPost, "", LoginProfileRequest{Username: "bob", PIN: "4321"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 signing in, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var login ProfileResponse
+	json.Unmarshal(rec.Body.Bytes(), &login)
+	if login.Profile.ID != created.Profile.ID || login.Key == "" || login.Key == created.Key {
+		t.Errorf("expected a new key for the same profile, got %+v", login)
+	}
+
+	rec = profileRequest(s.HandleLoginProfile, http.MethodPost, "", LoginProfileRequest{Username: "bob", PIN: "0000"})
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for wrong PIN, got %d", rec.Code)
+	}
+
+	rec = profileRequest(s.HandleUpdateProfile, http.MethodPatch, login.Key, map[string]interface{}{
+		"displayName": "Robert",
+		"preferences": map[string]string{"theme": "dark"},
+	})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 updating, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var updated profile.Profile
+	json.Unmarshal(rec.Body.Bytes(), &updated)
+	if updated.DisplayName != "Robert" || updated.Preferences["theme"] != "dark" {
+		t.Errorf("unexpected profile after update: %+v", updated)
+	}
+
+	rec = profileRequest(s.HandleUpdateProfile, http.MethodPatch, login.Key, map[string]interface{}{"displayName": "<b>"})
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid display name, got %d", rec.Code)
+	}
+
+	if rec := profileRequest(s.HandleGetProfile, http.MethodGet, "", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without key, got %d", rec.Code)
+	}
+
+	if rec := profileRequest(s.HandleLogoutProfile, http.MethodPost, login.Key, nil); rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204 signing out, got %d", rec.Code)
+	}
+	if rec := profileRequest(s.HandleGetProfile, http.MethodGet, login.Key, nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected signed out key rejected, got %d", rec.Code)
+	}
+
+	if rec := profileRequest(s.HandleDeleteProfile, http.MethodDelete, created.Key, nil); rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204 deleting, got %d", rec.Code)
+	}
+	if rec := profileRequest(s.HandleGetProfile, http.MethodGet, created.Key, nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected deleted profile's key rejected, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Protocol versions spoken over the WebSocket and SSE transports.
+//
+// Version 1 is the original, unversioned protocol: clients that omit
+// protocolVersion in their authenticate message are treated as v1.
+// Version 2 adds the negotiated version and capabilities to the authenticated
+// message and a machine-readable code to error messages.
+const (
+	ProtocolVersion1 = 1
+	ProtocolVersion2 = 2
+
+	// CurrentProtocolVersion is the newest version this server speaks.
+	CurrentProtocolVersion = ProtocolVersion2
+
+	// MinProtocolVersion is the oldest version still served by default.
+	// Older versions are kept alive through adapters for a deprecation window.
+	MinProtocolVersion = ProtocolVersion1
+)
+
+// serverCapabilities lists optional features clients may opt in to via the
+// capabilities field of the authenticate message. Features register here as
+// they are added.
+var serverCapabilities = map[string]bool{}
+
+// negotiateProtocol picks the version to speak with a client.
+// A client newer than the server is downgraded to the server's version;
+// a client older than minVersion is rejected.
+func negotiateProtocol(requested int, minVersion int) (int, error) {
+	if requested == 0 {
+		requested = ProtocolVersion1
+	}
+
+	if requested > CurrentProtocolVersion {
+		return CurrentProtocolVersion, nil
+	}
+
+	if requested < minVersion {
+		return 0, fmt.Errorf("protocol version %d is no longer supported (minimum %d)", requested, minVersion)
+	}
+
+	return requested, nil
+}
+
+// negotiateCapabilities returns the capabilities both the client and the
+// server support, sorted for stable output.
+func negotiateCapabilities(requested []string) []string {
+	negotiated := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, capability := range requested {
+		if serverCapabilities[capability] && !seen[capability] {
+			negotiated = append(negotiated, capability)
+			seen[capability] = true
+		}
+	}
+
+	sort.Strings(negotiated)
+	return negotiated
+}
+
+// protocolAdapter rewrites a message built for the current protocol into the
+// shape expected by an older version. Returning false drops the message.
+type protocolAdapter func(msg ServerMessage) (ServerMessage, bool)
+
+// protocolAdapters maps each deprecated version to its adapter.
+// The current version needs no adapter.
+var protocolAdapters = map[int]protocolAdapter{
+	ProtocolVersion1: adaptToV1,
+}
+
+// v1MessageTypes are the server message types v1 clients understand.
+var v1MessageTypes = map[string]bool{
+	ServerMsgAuthenticated: true,
+	ServerMsgRoomState:     true,
+	ServerMsgEvent:         true,
+	ServerMsgEvents:        true,
+	ServerMsgError:         true,
+	ServerMsgPong:          true,
+}
+
+// adaptToV1 strips fields introduced in v2 and drops message types that v1
+// clients don't know about.
+func adaptToV1(msg ServerMessage) (ServerMessage, bool) {
+	if !v1MessageTypes[msg.Type] {
+		return msg, false
+	}
+
+	switch msg.Type {
+	case ServerMsgAuthenticated:
+		var payload AuthenticatedPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return msg, true
+		}
+		adapted, err := NewServerMessage(msg.Type, struct {
+			PlayerID  string      `json:"playerId"`
+			RoomState interface{} `json:"roomState"`
+		}{payload.PlayerID, payload.RoomState})
+		if err != nil {
+			return msg, true
+		}
+		return adapted, true
+
+	case ServerMsgError:
+		var payload ErrorPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return msg, true
+		}
+		adapted, err := NewServerMessage(msg.Type, struct {
+			Message string `json:"message"`
+		}{payload.Message})
+		if err != nil {
+			return msg, true
+		}
+		return adapted, true
+	}
+
+	return msg, true
+}
+
+// adaptMessage converts a message for the given protocol version.
+func adaptMessage(version int, msg ServerMessage) (ServerMessage, bool) {
+	adapter, exists := protocolAdapters[version]
+	if !exists {
+		return msg, true
+	}
+	return adapter(msg)
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+func TestNegotiateProtocol(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		requested  int
+		minVersion int
+		want       int
+		wantErr    bool
+	}{
+		{name: "missing version is v1", requested: 0, minVersion: ProtocolVersion1, want: ProtocolVersion1},
+		{name: "current version", requested: CurrentProtocolVersion, minVersion: ProtocolVersion1, want: CurrentProtocolVersion},
+		{name: "newer client is downgraded", requested: CurrentProtocolVersion + 5, minVersion: ProtocolVersion1, want: CurrentProtocolVersion},
+		{name: "v1 rejected after deprecation window", requested: ProtocolVersion1, minVersion: ProtocolVersion2, wantErr: true},
+		{name: "missing version rejected after deprecation window", requested: 0, minVersion: ProtocolVersion2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := negotiateProtocol(tt.requested, tt.minVersion)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("negotiateProtocol() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("negotiateProtocol() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNegotiateCapabilities(t *testing.T) {
+	serverCapabilities["test_b"] = true
+	serverCapabilities["test_a"] = true
+	defer func() {
+		delete(serverCapabilities, "test_a")
+		delete(serverCapabilities, "test_b")
+	}()
+
+	got := negotiateCapabilities([]string{"test_b", "unknown", "test_a", "test_b"})
+	if len(got) != 2 || got[0] != "test_a" || got[1] != "test_b" {
+		t.Errorf("expected [test_a test_b], got %v", got)
+	}
+
+	if got := negotiateCapabilities(nil); len(got) != 0 {
+		t.Errorf("expected no capabilities, got %v", got)
+	}
+}
+
+func TestAdaptToV1(t *testing.T) {
+	t.Parallel()
+
+	t.Run("authenticated drops negotiation fields", func(t *testing.T) {
+		msg, _ := NewServerMessage(ServerMsgAuthenticated, AuthenticatedPayload{
+			PlayerID:        "p1",
+			ProtocolVersion: ProtocolVersion2,
+			Capabilities:    []string{"x"},
+		})
+
+		adapted, ok := adaptMessage(ProtocolVersion1, msg)
+		if !ok {
+			t.Fatal("authenticated message should not be dropped")
+		}
+
+		var payload map[string]interface{}
+		json.Unmarshal(adapted.Payload, &payload)
+		if payload["playerId"] != "p1" {
+			t.Errorf("expected playerId to survive, got %v", payload["playerId"])
+		}
+		if _, exists := payload["protocolVersion"]; exists {
+			t.Error("v1 authenticated payload should not contain protocolVersion")
+		}
+		if _, exists := payload["capabilities"]; exists {
+			t.Error("v1 authenticated payload should not contain capabilities")
+		}
+	})
+
+	t.Run("error drops code", func(t *testing.T) {
+		msg, _ := NewCodedErrorMessage(ErrorCodeActionFailed, "nope")
+
+		adapted, ok := adaptMessage(ProtocolVersion1, msg)
+		if !ok {
+			t.Fatal("error message should not be dropped")
+		}
+		if strings.Contains(string(adapted.Payload), "code") {
+			t.Errorf("v1 error payload should not contain code: %s", adapted.Payload)
+		}
+	})
+
+	t.Run("unknown message types are dropped", func(t *testing.T) {
+		msg := ServerMessage{Type: "something_new"}
+		if _, ok := adaptMessage(ProtocolVersion1, msg); ok {
+			t.Error("expected unknown message type to be dropped for v1")
+		}
+	})
+
+	t.Run("current version is untouched", func(t *testing.T) {
+		msg, _ := NewCodedErrorMessage(ErrorCodeActionFailed, "nope")
+		adapted, ok := adaptMessage(CurrentProtocolVersion, msg)
+		if !ok || string(adapted.Payload) != string(msg.Payload) {
+			t.Error("current protocol messages should pass through unchanged")
+		}
+	})
+}
+
+func TestHandleWebSocket_ProtocolNegotiation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		payload     AuthenticatePayload
+		minVersion  int
+		wantVersion int
+		wantClosed  bool
+	}{
+		{
+			name:        "v2 client",
+			payload:     AuthenticatePayload{ProtocolVersion: ProtocolVersion2},
+			minVersion:  ProtocolVersion1,
+			wantVersion: ProtocolVersion2,
+		},
+		{
+			name:        "legacy client without version",
+			payload:     AuthenticatePayload{},
+			minVersion:  ProtocolVersion1,
+			wantVersion: 0, // v1 payload has no protocolVersion field
+		},
+		{
+			name:       "legacy client after deprecation window",
+			payload:    AuthenticatePayload{},
+			minVersion: ProtocolVersion2,
+			wantClosed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := NewServer(store.NewMemoryStore())
+			server.ConnectionManager().SetMinProtocolVersion(tt.minVersion)
+			room, tokens := setupWerewolfGame(t, server)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/rooms/{code}/ws", server.HandleWebSocket)
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/rooms/" + room.ID + "/ws"
+			conn, _, err := websocket.Dial(ctx, wsURL, &websocket.DialOptions{
+				HTTPHeader: http.Header{"Origin": []string{"http://localhost:5173"}},
+			})
+			if err != nil {
+				t.Fatalf("failed to dial: %v", err)
+			}
+			defer conn.Close(websocket.StatusNormalClosure, "")
+
+			tt.payload.SessionToken = tokens[room.HostID]
+			payloadBytes, _ := json.Marshal(tt.payload)
+			if err := wsjson.Write(ctx, conn, ClientMessage{Type: ClientMsgAuthenticate, Payload: payloadBytes}); err != nil {
+				t.Fatalf("failed to send authenticate: %v", err)
+			}
+
+			var msg ServerMessage
+			err = wsjson.Read(ctx, conn, &msg)
+			if tt.wantClosed {
+				if websocket.CloseStatus(err) != websocket.StatusPolicyViolation {
+					t.Fatalf("expected policy violation close, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to read authenticated message: %v", err)
+			}
+			if msg.Type != ServerMsgAuthenticated {
+				t.Fatalf("expected authenticated message, got %s", msg.Type)
+			}
+
+			var payload struct {
+				ProtocolVersion int `json:"protocolVersion"`
+			}
+			json.Unmarshal(msg.Payload, &payload)
+			if payload.ProtocolVersion != tt.wantVersion {
+				t.Errorf("expected protocol version %d, got %d", tt.wantVersion, payload.ProtocolVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	defaultQRSize = 256  // PNG width and height in pixels
+	minQRSize     = 128  // Smaller codes are hard to scan across a table
+	maxQRSize     = 1024 // Bounds the work per request
+)
+
+// joinURL returns the frontend URL for joining the room, including the
+// invite if one is given.
+func (s *Server) joinURL(r *http.Request, roomCode string, invite string) string {
+	base := s.options.PublicURL
+	if base == "" {
+		// Without a configured URL, assume the frontend is served by the
+		// same host as the API
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		if s.options.TrustProxyHeaders {
+			if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+				scheme = proto
+			}
+		}
+		base = scheme + "://" + r.Host
+	}
+
+	joinURL := strings.TrimSuffix(base, "/") + "/join/" + url.PathEscape(roomCode)
+	if invite != "" {
+		joinURL += "?invite=" + url.QueryEscape(invite)
+	}
+	return joinURL
+}
+
+// roomQRCode encodes the join URL of the room in the request. On failure it
+// writes the error response and returns nil.
+func (s *Server) roomQRCode(w http.ResponseWriter, r *http.Request) *qrcode.QRCode {
+	roomCode := r.PathValue("code")
+	if roomCode == "" {
+		http.Error(w, "Room code required", http.StatusBadRequest)
+		return nil
+	}
+
+	room, err := s.store.GetRoom(roomCode)
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return nil
+	}
+
+	// Only embed invites for this room, so the endpoint can't be used to
+	// dress up arbitrary content
+	invite := r.URL.Query().Get("invite")
+	if invite != "" {
+		claims, err := s.connMgr.tokens.VerifyInvite(invite)
+		if err != nil || !claims.ForRoom(room.ID, room.CreatedAt) {
+			http.Error(w, "Invalid invite", http.StatusBadRequest)
+			return nil
+		}
+	}
+
+	code, err := qrcode.New(s.joinURL(r, room.ID, invite), qrcode.Medium)
+	if err != nil {
+		slog.Error("failed to encode QR code", "roomCode", room.ID, "error", err)
+		http.Error(w, "Failed to generate QR code", http.StatusInternalServerError)
+		return nil
+	}
+	return code
+}
+
+// HandleRoomQRPNG renders a PNG QR code for joining the room.
+// The optional size query parameter sets the width in pixels.
+func (s *Server) HandleRoomQRPNG(w http.ResponseWriter, r *http.Request) {
+	size := defaultQRSize
+	if value := r.URL.Query().Get("size"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < minQRSize || parsed > maxQRSize {
+			http.Error(w, fmt.Sprintf("size must be between %d and %d", minQRSize, maxQRSize), http.StatusBadRequest)
+			return
+		}
+		size = parsed
+	}
+
+	code := s.roomQRCode(w, r)
+	if code == nil {
+		return
+	}
+
+	png, err := code.PNG(size)
+	if err != nil {
+		slog.Error("failed to render QR code", "roomCode", r.PathValue("code"), "error", err)
+		http.Error(w, "Failed to generate QR code", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.Write(png)
+}
+
+// HandleRoomQRSVG renders an SVG QR code for joining the room, which scales
+// to any screen.
+func (s *Server) HandleRoomQRSVG(w http.ResponseWriter, r *http.Request) {
+	code := s.roomQRCode(w, r)
+	if code == nil {
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.Write([]byte(qrSVG(code.Bitmap())))
+}
+
+// qrSVG draws a QR code bitmap (including its quiet zone) as an SVG with one
+// unit per module.
+func qrSVG(bitmap [][]bool) string {
+	var b strings.Builder
+	size := len(bitmap)
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, size, size)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&b, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+
+	return b.String()
+}
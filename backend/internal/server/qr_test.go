@@ -0,0 +1,152 @@
+package server
+
+import (
+	"bytes"
+	"crypto/tls"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+func TestServer_JoinURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		publicURL  string
+		trustProxy bool
+		tls        bool
+		header     http.Header
+		invite     string
+		want       string
+	}{
+		{name: "configured", publicURL: "https://play.example.com", want: "https://play.example.com/join/ABC123"},
+		{name: "configured with path", publicURL: "https://example.com/roundtable/", want: "https://example.com/roundtable/join/ABC123"},
+		{name: "request host", want: "http://api.example.net/join/ABC123"},
+		{name: "request over TLS", tls: true, want: "https://api.example.net/join/ABC123"},
+		{name: "forwarded proto ignored", header: http.Header{"X-Forwarded-Proto": {"https"}}, want: "http://api.example.net/join/ABC123"},
+		{name: "forwarded proto trusted", trustProxy: true, header: http.Header{"X-Forwarded-Proto": {"https"}}, want: "https://api.example.net/join/ABC123"},
+		{name: "invite", publicURL: "https://play.example.com", invite: "a.b+c", want: "https://play.example.com/join/ABC123?invite=a.b%2Bc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			options := DefaultOptions()
+			options.PublicURL = tt.publicURL
+			options.TrustProxyHeaders = tt.trustProxy
+			server := NewServerWithOptions(store.NewMemoryStore(), options)
+
+			req := httptest.NewRequest(http.MethodGet, "http://api.example.net/api/rooms/ABC123/qr.png", nil)
+			for key, values := range tt.header {
+				req.Header[key] = values
+			}
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			if got := server.joinURL(req, "ABC123", tt.invite); got != tt.want {
+				t.Errorf("joinURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleRoomQR(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions()
+	options.PublicURL = "https://play.example.com"
+	server := NewServerWithOptions(store.NewMemoryStore(), options)
+	room, _, _ := setupLobby(t, server)
+
+	invite, _, _ := server.connMgr.tokens.IssueInvite(room.ID, room.CreatedAt, time.Hour)
+	otherInvite, _, _ := server.connMgr.tokens.IssueInvite("OTHER1", room.CreatedAt, time.Hour)
+	staleInvite, _, _ := server.connMgr.tokens.IssueInvite(room.ID, room.CreatedAt.Add(-time.Hour), time.Hour)
+
+	tests := []struct {
+		name           string
+		svg            bool
+		roomCode       string
+		query          string
+		wantStatusCode int
+		wantSize       int
+	}{
+		{name: "png", roomCode: room.ID, wantStatusCode: http.StatusOK, wantSize: defaultQRSize},
+		{name: "png with size", roomCode: room.ID, query: "?size=512", wantStatusCode: http.StatusOK, wantSize: 512},
+		{name: "png with invite", roomCode: room.ID, query: "?invite=" + invite, wantStatusCode: http.StatusOK, wantSize: defaultQRSize},
+		{name: "svg", svg: true, roomCode: room.ID, wantStatusCode: http.StatusOK},
+		{name: "size too small", roomCode: room.ID, query: "?size=16", wantStatusCode: http.StatusBadRequest},
+		{name: "size too large", roomCode: room.ID, query: "?size=100000", wantStatusCode: http.StatusBadRequest},
+		{name: "invite for another room", roomCode: room.ID, query: "?invite=" + otherInvite, wantStatusCode: http.StatusBadRequest},
+		{name: "invite for earlier room with the code", roomCode: room.ID, query: "?invite=" + staleInvite, wantStatusCode: http.StatusBadRequest},
+		{name: "unknown room", roomCode: "NOROOM", wantStatusCode: http.StatusNotFound},
+		{name: "unknown room svg", svg: true, roomCode: "NOROOM", wantStatusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := httptest.NewRecorder()
+			if tt.svg {
+				req := httptest.NewRequest(http.MethodGet, "/api/rooms/"+tt.roomCode+"/qr.svg"+tt.query, nil)
+				req.SetPathValue("code", tt.roomCode)
+				server.HandleRoomQRSVG(rec, req)
+			} else {
+				req := httptest.NewRequest(http.MethodGet, "/api/rooms/"+tt.roomCode+"/qr.png"+tt.query, nil)
+				req.SetPathValue("code", tt.roomCode)
+				server.HandleRoomQRPNG(rec, req)
+			}
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			if tt.svg {
+				if ct := rec.Header().Get("Content-Type"); ct != "image/svg+xml" {
+					t.Errorf("expected image/svg+xml, got %q", ct)
+				}
+				body := rec.Body.String()
+				if !strings.HasPrefix(body, "<svg") || !strings.HasSuffix(body, "</svg>") || !strings.Contains(body, "h1v1h-1z") {
+					t.Errorf("unexpected SVG: %.100s", body)
+				}
+				return
+			}
+
+			if ct := rec.Header().Get("Content-Type"); ct != "image/png" {
+				t.Errorf("expected image/png, got %q", ct)
+			}
+			img, err := png.Decode(bytes.NewReader(rec.Body.Bytes()))
+			if err != nil {
+				t.Fatalf("invalid PNG: %v", err)
+			}
+			if bounds := img.Bounds(); bounds.Dx() != tt.wantSize || bounds.Dy() != tt.wantSize {
+				t.Errorf("expected %dx%d image, got %dx%d", tt.wantSize, tt.wantSize, bounds.Dx(), bounds.Dy())
+			}
+		})
+	}
+}
+
+func TestQRSVG(t *testing.T) {
+	t.Parallel()
+
+	got := qrSVG([][]bool{
+		{true, false},
+		{false, true},
+	})
+	want := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 2 2" shape-rendering="crispEdges">` +
+		`<rect width="2" height="2" fill="#fff"/><path fill="#000" d="M0 0h1v1h-1zM1 1h1v1h-1z"/></svg>`
+	if got != want {
+		t.Errorf("qrSVG() = %s, want %s", got, want)
+	}
+}
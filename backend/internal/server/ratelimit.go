@@ -0,0 +1,105 @@
+package server
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/ratelimit"
+)
+
+// RateLimitScope selects which limiter guards an endpoint.
+type RateLimitScope string
+
+const (
+	ScopeCreateRoom RateLimitScope = "create_room"
+	ScopeJoinRoom   RateLimitScope = "join_room"
+	ScopeConnect    RateLimitScope = "connect"
+	ScopeReads      RateLimitScope = "reads"
+	ScopeSignIn     RateLimitScope = "sign_in"
+	ScopeActions    RateLimitScope = "actions"
+	ScopeChat       RateLimitScope = "chat"
+	ScopeReactions  RateLimitScope = "reactions"
+	ScopeWrites     RateLimitScope = "writes"
+	ScopeAdmin      RateLimitScope = "admin"
+)
+
+// newLimiters builds the server's limiters from its options.
+func newLimiters(limits RateLimits) map[RateLimitScope]*ratelimit.Limiter {
+	return map[RateLimitScope]*ratelimit.Limiter{
+		ScopeCreateRoom: ratelimit.New(string(ScopeCreateRoom), limits.CreateRoom),
+		ScopeJoinRoom:   ratelimit.New(string(ScopeJoinRoom), limits.JoinRoom),
+		ScopeConnect:    ratelimit.New(string(ScopeConnect), limits.Connect),
+		ScopeReads:      ratelimit.New(string(ScopeReads), limits.Reads),
+		ScopeSignIn:     ratelimit.New(string(ScopeSignIn), limits.SignIn),
+		ScopeActions:    ratelimit.New(string(ScopeActions), limits.Actions),
+		ScopeChat:       ratelimit.New(string(ScopeChat), limits.Chat),
+		ScopeReactions:  ratelimit.New(string(ScopeReactions), limits.Reactions),
+		ScopeWrites:     ratelimit.New(string(ScopeWrites), limits.Writes),
+		ScopeAdmin:      ratelimit.New(string(ScopeAdmin), limits.Admin),
+	}
+}
+
+// RateLimited wraps a handler with the per-IP limiter for the given scope.
+// Throttled requests get 429 Too Many Requests with a Retry-After header.
+func (s *Server) RateLimited(scope RateLimitScope, next http.HandlerFunc) http.HandlerFunc {
+	limiter := s.limiters[scope]
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ip := clientIP(r, s.options.TrustProxyHeaders)
+
+		if allowed, retryAfter := limiter.Allow(ip); !allowed {
+			slog.Warn("rate limited", "scope", scope, "ip", ip, "path", r.URL.Path)
+			writeTooManyRequests(w, retryAfter)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// RateLimitStats returns the counters of every limiter.
+func (s *Server) RateLimitStats() []ratelimit.Stats {
+	stats := make([]ratelimit.Stats, 0, len(s.limiters))
+	for _, scope := range []RateLimitScope{ScopeCreateRoom, ScopeJoinRoom, ScopeConnect, ScopeReads, ScopeSignIn, ScopeActions, ScopeChat, ScopeReactions, ScopeWrites, ScopeAdmin} {
+		stats = append(stats, s.limiters[scope].Stats())
+	}
+	return stats
+}
+
+// writeTooManyRequests writes a 429 response with a Retry-After header.
+func writeTooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	http.Error(w, "Too many requests", http.StatusTooManyRequests)
+}
+
+// clientIP returns the client's IP address. When trustProxy is set the last
+// X-Forwarded-For entry is used: it is the one our proxy appended, while
+// anything before it came from the client and can be forged.
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+			forwarded := values[len(values)-1]
+			if i := strings.LastIndex(forwarded, ","); i >= 0 {
+				forwarded = forwarded[i+1:]
+			}
+			if ip := strings.TrimSpace(forwarded); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/ratelimit"
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+func TestRateLimited_CreateRoom(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions()
+	options.RateLimits.CreateRoom = ratelimit.Limit{Rate: 0.01, Burst: 2}
+	server := NewServerWithOptions(store.NewMemoryStore(), options)
+	handler := server.RateLimited(ScopeCreateRoom, server.HandleCreateRoom)
+
+	create := func(remoteAddr string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(CreateRoomRequest{GameType: "werewolf", DisplayName: "Host"})
+		req := httptest.NewRequest(http.MethodPost, "/api/rooms", bytes.NewBuffer(body))
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	for i := 0; i < 2; i++ {
+		if rec := create("203.0.113.1:1000"); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, rec.Code)
+		}
+	}
+
+	rec := create("203.0.113.1:1001")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+
+	if rec := create("203.0.113.2:1000"); rec.Code != http.StatusOK {
+		t.Errorf("different IP should not be throttled, got %d", rec.Code)
+	}
+
+	var throttled uint64
+	for _, stats := range server.RateLimitStats() {
+		if stats.Name == string(ScopeCreateRoom) {
+			throttled = stats.Throttled
+		}
+	}
+	if throttled != 1 {
+		t.Errorf("expected 1 throttled create, got %d", throttled)
+	}
+}
+
+func TestHandleAction_RateLimited(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions()
+	options.RateLimits.Actions = ratelimit.Limit{Rate: 0.01, Burst: 1}
+	server := NewServerWithOptions(store.NewMemoryStore(), options)
+	room, tokens := setupWerewolfGame(t, server)
+
+	post := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/api/rooms/"+room.ID+"/actions",
+			bytes.NewBufferString(`{"action":{"type":"acknowledge_role","payload":{}}}`))
+		req.SetPathValue("code", room.ID)
+		req.Header.Set("X-Session-Token", tokens[room.HostID])
+		rec := httptest.NewRecorder()
+		server.HandleAction(rec, req)
+		return rec.Code
+	}
+
+	if code := post(); code != http.StatusOK {
+		t.Fatalf("first action: expected 200, got %d", code)
+	}
+	if code := post(); code != http.StatusTooManyRequests {
+		t.Fatalf("second action: expected 429, got %d", code)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		trustProxy bool
+		want       string
+	}{
+		{name: "remote addr", remoteAddr: "198.51.100.7:5000", want: "198.51.100.7"},
+		{name: "ipv6 remote addr", remoteAddr: "[2001:db8::1]:5000", want: "2001:db8::1"},
+		{name: "forwarded ignored without trust", remoteAddr: "10.0.0.1:5000", forwarded: "198.51.100.7", want: "10.0.0.1"},
+		{name: "forwarded used with trust", remoteAddr: "10.0.0.1:5000", forwarded: "198.51.100.7", trustProxy: true, want: "198.51.100.7"},
+		{name: "forged leading entry ignored", remoteAddr: "10.0.0.1:5000", forwarded: "203.0.113.9, 198.51.100.7", trustProxy: true, want: "198.51.100.7"},
+		{name: "trust without header", remoteAddr: "10.0.0.1:5000", trustProxy: true, want: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+
+			if got := clientIP(req, tt.trustProxy); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimited_ScopesAreSeparate(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions()
+	options.RateLimits.JoinRoom = ratelimit.Limit{Rate: 0.01, Burst: 1}
+	server := NewServerWithOptions(store.NewMemoryStore(), options)
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+
+	request := func(scope RateLimitScope) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "203.0.113.1:1000"
+		rec := httptest.NewRecorder()
+		server.RateLimited(scope, ok)(rec, req)
+		return rec.Code
+	}
+
+	// A table behind one NAT uses up the join burst...
+	request(ScopeJoinRoom)
+	if code := request(ScopeJoinRoom); code != http.StatusTooManyRequests {
+		t.Fatalf("expected joins to be throttled, got %d", code)
+	}
+
+	// ...but can still look rooms up and reconnect
+	for i := 0; i < 10; i++ {
+		if code := request(ScopeReads); code != http.StatusOK {
+			t.Fatalf("read %d: expected 200, got %d", i+1, code)
+		}
+		if code := request(ScopeConnect); code != http.StatusOK {
+			t.Fatalf("connect %d: expected 200, got %d", i+1, code)
+		}
+	}
+}
+
+func TestRateLimited_AdminTokenGuessing(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions()
+	options.AdminToken = testAdminToken
+	options.RateLimits.Admin = ratelimit.Limit{Rate: 0.01, Burst: 3}
+	server := NewServerWithOptions(store.NewMemoryStore(), options)
+	handler := server.RateLimited(ScopeAdmin, server.AdminOnly(server.HandleAdminListRooms))
+
+	guess := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/admin/rooms", nil)
+		req.RemoteAddr = "203.0.113.1:1000"
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+
+	for i := 0; i < 3; i++ {
+		if code := guess("wrong"); code != http.StatusUnauthorized {
+			t.Fatalf("guess %d: expected 401, got %d", i+1, code)
+		}
+	}
+	if code := guess(testAdminToken); code != http.StatusTooManyRequests {
+		t.Errorf("expected further requests to be throttled, got %d", code)
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// CapabilityReactions delivers a reaction message for every emoji or pointing
+// gesture in the room. Reactions are ephemeral: they are not part of any
+// history sent on reconnect.
+const CapabilityReactions = "reactions"
+
+func init() {
+	serverCapabilities[CapabilityReactions] = true
+}
+
+// allowReaction applies the per-session reaction limiter.
+func (cm *ConnectionManager) allowReaction(playerID string) (bool, time.Duration) {
+	return allowPlayer(cm.reactionLimiter, ScopeReactions, playerID)
+}
+
+// processReaction passes a reaction through the room and broadcasts it. It is
+// shared by the WebSocket and HTTP reaction transports. Pointing during a game
+// also broadcasts the room state, which carries the accusation summary.
+func (cm *ConnectionManager) processReaction(room *core.Room, playerID string, reaction core.Reaction) error {
+	reaction, err := room.React(playerID, reaction)
+	if err != nil {
+		return err
+	}
+
+	cm.broadcastReaction(room, reaction)
+	if reaction.Kind == core.ReactionPoint && room.GetState().Status == core.RoomStatusPlaying {
+		cm.BroadcastRoomState(room.ID)
+	}
+	return nil
+}
+
+// broadcastReaction sends a reaction to everyone in the room who negotiated
+// the reactions capability.
+func (cm *ConnectionManager) broadcastReaction(room *core.Room, reaction core.Reaction) {
+	reactionMsg, _ := NewReactionMessage(reaction)
+
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	for _, player := range room.GetPlayers() {
+		conn, exists := cm.connections[player.ID]
+		if !exists || conn.RoomCode != room.ID || !conn.HasCapability(CapabilityReactions) {
+			continue
+		}
+
+		// Reactions are only worth showing live, so a full buffer just drops them
+		select {
+		case conn.Send <- reactionMsg:
+		default:
+			cm.metrics.BroadcastDropped(ServerMsgReaction)
+		}
+	}
+}
+
+// HandleReaction sends a reaction over plain HTTP, for SSE clients.
+// Expected format: POST /api/rooms/{code}/reactions
+func (s *Server) HandleReaction(w http.ResponseWriter, r *http.Request) {
+	// Limit request body to 1MB
+	r.Body = http.MaxBytesReader(w, r.Body, 1*1024*1024)
+
+	token := r.Header.Get("X-Session-Token")
+	if token == "" {
+		http.Error(w, "Session token required", http.StatusUnauthorized)
+		return
+	}
+
+	var reaction core.Reaction
+	if err := json.NewDecoder(r.Body).Decode(&reaction); err != nil {
+		http.Error(w, "Request too large or malformed", http.StatusBadRequest)
+		return
+	}
+
+	room, err := s.store.GetRoom(r.PathValue("code"))
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	player, err := s.connMgr.authenticatePlayer(room, token)
+	if err != nil {
+		http.Error(w, "Invalid session token", http.StatusUnauthorized)
+		return
+	}
+	player.UpdateLastSeen()
+
+	if allowed, retryAfter := s.connMgr.allowReaction(player.ID); !allowed {
+		writeTooManyRequests(w, retryAfter)
+		return
+	}
+
+	if err := s.connMgr.processReaction(room, player.ID, reaction); err != nil {
+		http.Error(w, fmt.Sprintf("Reaction failed: %v", err), chatStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/games/werewolf"
+	"github.com/KonradHerman/roundtable/internal/ratelimit"
+	"github.com/KonradHerman/roundtable/internal/store"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// reactionRequest sends a reaction over HTTP.
+func reactionRequest(s *Server, roomCode, token string, reaction core.Reaction) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(reaction)
+	req := httptest.NewRequest(http.MethodPost, "/api/rooms/"+roomCode+"/reactions", strings.NewReader(string(body)))
+	req.SetPathValue("code", roomCode)
+	req.Header.Set("X-Session-Token", token)
+	rec := httptest.NewRecorder()
+	s.HandleReaction(rec, req)
+	return rec
+}
+
+func TestHandleReaction(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions()
+	options.RateLimits.Reactions = ratelimit.Limit{Rate: 0.01, Burst: 3}
+	server := NewServerWithOptions(store.NewMemoryStore(), options)
+	room, host, player := setupLobby(t, server)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/rooms/{code}/ws", server.HandleWebSocket)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The host watches over a WebSocket with the reactions capability
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/rooms/" + room.ID + "/ws"
+	conn, _, err := websocket.Dial(ctx, wsURL, &websocket.DialOptions{
+		HTTPHeader: http.Header{"Origin": []string{"http://localhost:5173"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	payload, _ := json.Marshal(AuthenticatePayload{
+		SessionToken:    host.SessionToken,
+		ProtocolVersion: CurrentProtocolVersion,
+		Capabilities:    []string{CapabilityReactions},
+	})
+	wsjson.Write(ctx, conn, ClientMessage{Type: ClientMsgAuthenticate, Payload: payload})
+	var msg ServerMessage
+	if err := wsjson.Read(ctx, conn, &msg); err != nil || msg.Type != ServerMsgAuthenticated {
+		t.Fatalf("expected authenticated message, got %s (%v)", msg.Type, err)
+	}
+
+	point := core.Reaction{Kind: core.ReactionPoint, TargetID: host.ID}
+	if rec := reactionRequest(server, room.ID, player.SessionToken, point); rec.Code != http.StatusOK {
+		t.Fatalf("expected reaction to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if err := wsjson.Read(ctx, conn, &msg); err != nil || msg.Type != ServerMsgReaction {
+		t.Fatalf("expected reaction message, got %s (%v)", msg.Type, err)
+	}
+	var got ReactionPayload
+	json.Unmarshal(msg.Payload, &got)
+	if got.Reaction.PlayerID != player.ID || got.Reaction.TargetID != host.ID || got.Reaction.Kind != core.ReactionPoint {
+		t.Errorf("unexpected reaction %+v", got.Reaction)
+	}
+	if room.GetEventLogLength() != 0 {
+		t.Error("expected reactions to stay out of the event log")
+	}
+
+	invalid := core.Reaction{Kind: core.ReactionEmoji, Emoji: "🦄"}
+	if rec := reactionRequest(server, room.ID, player.SessionToken, invalid); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unsupported emoji, got %d", rec.Code)
+	}
+
+	room.SetMuted(player.ID, true)
+	if rec := reactionRequest(server, room.ID, player.SessionToken, point); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a muted player, got %d", rec.Code)
+	}
+
+	// The burst of 3 is used up
+	if rec := reactionRequest(server, room.ID, player.SessionToken, point); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 once rate limited, got %d", rec.Code)
+	}
+}
+
+func TestHandleReaction_BroadcastsAccusations(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room, host, player := setupLobby(t, server)
+
+	config := &werewolf.Config{Roles: []werewolf.RoleType{
+		werewolf.RoleWerewolf, werewolf.RoleVillager, werewolf.RoleVillager, werewolf.RoleVillager, werewolf.RoleVillager,
+	}}
+	if err := room.StartGame(werewolf.NewGame(), config); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	for _, a := range []struct{ playerID, action string }{
+		{host.ID, "acknowledge_role"},
+		{player.ID, "acknowledge_role"},
+		{host.ID, "advance_phase"},
+	} {
+		if err := server.connMgr.processAction(room, a.playerID, core.Action{Type: a.action}); err != nil {
+			t.Fatalf("%s: failed to process action: %v", a.action, err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/rooms/{code}/ws", server.HandleWebSocket)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The host watches over a plain WebSocket, without the reactions capability
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/rooms/" + room.ID + "/ws"
+	conn, _, err := websocket.Dial(ctx, wsURL, &websocket.DialOptions{
+		HTTPHeader: http.Header{"Origin": []string{"http://localhost:5173"}},
+	})
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	payload, _ := json.Marshal(AuthenticatePayload{SessionToken: host.SessionToken, ProtocolVersion: CurrentProtocolVersion})
+	wsjson.Write(ctx, conn, ClientMessage{Type: ClientMsgAuthenticate, Payload: payload})
+	var msg ServerMessage
+	if err := wsjson.Read(ctx, conn, &msg); err != nil || msg.Type != ServerMsgAuthenticated {
+		t.Fatalf("expected authenticated message, got %s (%v)", msg.Type, err)
+	}
+
+	point := core.Reaction{Kind: core.ReactionPoint, TargetID: host.ID}
+	if rec := reactionRequest(server, room.ID, player.SessionToken, point); rec.Code != http.StatusOK {
+		t.Fatalf("expected reaction to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	for {
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			t.Fatalf("expected a room state with accusations: %v", err)
+		}
+		if msg.Type != ServerMsgRoomState {
+			continue
+		}
+		var state RoomStatePayload
+		json.Unmarshal(msg.Payload, &state)
+		accusations := state.RoomState.Accusations
+		if len(accusations) != 1 || accusations[0].SuspectID != host.ID || accusations[0].AccusedBy[0] != player.ID {
+			t.Fatalf("expected the host accused by %s, got %+v", player.ID, accusations)
+		}
+		return
+	}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/profile"
+	"github.com/KonradHerman/roundtable/internal/store"
+	"github.com/KonradHerman/roundtable/internal/util"
+)
+
+// createRoomAttempts is how often room creation is retried when another room
+// takes a freshly allocated code first.
+const createRoomAttempts = 3
+
+// newCodeAllocator creates the server's room code allocator, checking codes
+// against rooms in the store.
+func newCodeAllocator(roomStore store.Store, options util.CodeOptions) *util.CodeAllocator {
+	inUse := func(code string) bool {
+		_, err := roomStore.GetRoom(code)
+		return err == nil
+	}
+
+	allocator, err := util.NewCodeAllocator(options, inUse)
+	if err != nil {
+		// Validated by config
+		slog.Error("invalid room code options, using defaults", "error", err)
+		allocator, _ = util.NewCodeAllocator(util.DefaultCodeOptions(), inUse)
+	}
+	return allocator
+}
+
+// createRoom stores a new room with the host, under the host's custom code if
+// requested or else a newly allocated one. The host's profile is optional.
+func (s *Server) createRoom(req CreateRoomRequest, displayName string, hostProfile *profile.Profile) (*core.Room, *core.Player, error) {
+	for attempt := 1; ; attempt++ {
+		var roomCode string
+		var err error
+		if req.RoomCode != "" {
+			roomCode, err = s.codes.Claim(req.RoomCode)
+		} else {
+			roomCode, err = s.codes.Allocate()
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Create host player with a session bound to the room
+		hostPlayer, err := s.newPlayer(roomCode, displayName, hostProfile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		room := core.NewRoom(roomCode, req.GameType, hostPlayer, req.MaxPlayers)
+		room.SetObserver(s.metrics)
+		if err := room.SetPassword(req.Password); err != nil {
+			return nil, nil, err
+		}
+		if req.Public {
+			room.Publish(req.Title)
+		}
+
+		err = s.store.CreateRoom(room)
+		if errors.Is(err, store.ErrRoomExists) {
+			if req.RoomCode != "" {
+				return nil, nil, util.ErrCodeTaken
+			}
+			if attempt < createRoomAttempts {
+				continue
+			}
+			return nil, nil, util.ErrCodeSpaceExhausted
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Create initial event
+		event, _ := core.NewPublicEvent(core.EventPlayerJoined, "system", core.PlayerJoinedPayload{
+			PlayerID:    hostPlayer.ID,
+			DisplayName: hostPlayer.DisplayName,
+		})
+		room.AppendEvent(event)
+		s.linkProfile(room.ID, hostPlayer)
+
+		return room, hostPlayer, nil
+	}
+}
+
+// createRoomError maps a createRoom error to a response status and message.
+func createRoomError(err error) (int, string) {
+	switch {
+	case errors.Is(err, util.ErrInvalidCode), errors.Is(err, util.ErrCustomCodesDisabled):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, util.ErrCodeTaken):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, util.ErrCodeSpaceExhausted):
+		return http.StatusServiceUnavailable, "No room codes available, try again later"
+	default:
+		return http.StatusInternalServerError, "Failed to create room"
+	}
+}
+
+// RoomRemoved records a room removed by stale room cleanup and holds back
+// its code. Register it with the store's cleanup listener.
+func (s *Server) RoomRemoved(room *core.Room, reason string) {
+	s.metrics.RoomCleanedUp(room, reason)
+	if room != nil {
+		s.codes.Release(room.ID)
+		s.bots.RemoveRoom(room.ID)
+	}
+}
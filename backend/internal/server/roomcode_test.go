@@ -0,0 +1,112 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/store"
+	"github.com/KonradHerman/roundtable/internal/util"
+)
+
+func TestHandleCreateRoom_CustomCode(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions()
+	options.RoomCodes.AllowCustom = true
+	server := NewServerWithOptions(store.NewMemoryStore(), options)
+	disabled := NewServer(store.NewMemoryStore())
+
+	tests := []struct {
+		name           string
+		server         *Server
+		roomCode       string
+		wantStatusCode int
+		wantCode       string
+	}{
+		{name: "custom code", server: server, roomCode: "party", wantStatusCode: http.StatusOK, wantCode: "PARTY"},
+		{name: "taken", server: server, roomCode: "PARTY", wantStatusCode: http.StatusConflict},
+		{name: "invalid", server: server, roomCode: "no!", wantStatusCode: http.StatusBadRequest},
+		{name: "disabled", server: disabled, roomCode: "PARTY", wantStatusCode: http.StatusBadRequest},
+	}
+
+	// Subtests share a server and run in order
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(CreateRoomRequest{GameType: "werewolf", DisplayName: "Host", RoomCode: tt.roomCode})
+			rec := httptest.NewRecorder()
+			tt.server.HandleCreateRoom(rec, httptest.NewRequest(http.MethodPost, "/api/rooms", bytes.NewBuffer(body)))
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode == "" {
+				return
+			}
+
+			var resp CreateRoomResponse
+			json.Unmarshal(rec.Body.Bytes(), &resp)
+			if resp.RoomCode != tt.wantCode {
+				t.Errorf("expected room code %s, got %s", tt.wantCode, resp.RoomCode)
+			}
+			if _, err := tt.server.store.GetRoom(tt.wantCode); err != nil {
+				t.Errorf("expected room %s in store: %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestHandleCreateRoom_CodeSpaceExhausted(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions()
+	options.RoomCodes = util.CodeOptions{Length: 4, Alphabet: "AB"}
+	server := NewServerWithOptions(store.NewMemoryStore(), options)
+
+	// Fill all 16 codes
+	for i := 0; i < 16; i++ {
+		code := ""
+		for bit := 3; bit >= 0; bit-- {
+			code += string("AB"[(i>>bit)&1])
+		}
+		host := core.NewPlayer("Host")
+		if err := server.store.CreateRoom(core.NewRoom(code, "werewolf", host, 10)); err != nil {
+			t.Fatalf("failed to create room %s: %v", code, err)
+		}
+	}
+
+	body, _ := json.Marshal(CreateRoomRequest{GameType: "werewolf", DisplayName: "Host"})
+	rec := httptest.NewRecorder()
+	server.HandleCreateRoom(rec, httptest.NewRequest(http.MethodPost, "/api/rooms", bytes.NewBuffer(body)))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestServer_RoomRemovedHoldsBackCode(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions()
+	options.RoomCodes.AllowCustom = true
+	options.RoomCodes.Cooldown = time.Hour
+	server := NewServerWithOptions(store.NewMemoryStore(), options)
+
+	host := core.NewPlayer("Host")
+	room := core.NewRoom("PARTY", "werewolf", host, 10)
+	server.store.CreateRoom(room)
+	server.store.DeleteRoom(room.ID)
+	server.RoomRemoved(room, "abandoned")
+
+	body, _ := json.Marshal(CreateRoomRequest{GameType: "werewolf", DisplayName: "Host", RoomCode: "PARTY"})
+	rec := httptest.NewRecorder()
+	server.HandleCreateRoom(rec, httptest.NewRequest(http.MethodPost, "/api/rooms", bytes.NewBuffer(body)))
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected freed code to be held back with 409, got %d", rec.Code)
+	}
+}
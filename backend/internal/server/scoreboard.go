@@ -0,0 +1,102 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// scoringRules returns the session scoring rules for a game type.
+func (s *Server) scoringRules(gameType string) core.ScoringRules {
+	if rules, ok := s.options.Scoring[gameType]; ok {
+		return rules
+	}
+	return core.DefaultScoringRules()
+}
+
+// gameFinished scores a finished game on the room's scoreboard, records it
+// in the players' profile statistics and, for a tournament table, in the
+// tournament.
+func (s *Server) gameFinished(room *core.Room) {
+	event, scored, err := room.ScoreGame(s.scoringRules(room.GameType))
+	if err != nil {
+		slog.Error("failed to score round", "roomCode", room.ID, "error", err)
+	}
+	if scored {
+		s.connMgr.BroadcastEvent(room.ID, event)
+	}
+
+	s.recordGame(room)
+	s.recordTournamentTable(room)
+}
+
+// HandleSessionSummary returns the room's session scoreboard: rounds played,
+// points per round and standings.
+// Expected format: GET /api/rooms/{code}/session_summary
+func (s *Server) HandleSessionSummary(w http.ResponseWriter, r *http.Request) {
+	room, err := s.store.GetRoom(r.PathValue("code"))
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(room.SessionSummary())
+}
+
+// HandleEndSession lets the host end the session. Everyone receives a
+// final_standings event, and the scoreboard starts over.
+// Expected format: POST /api/rooms/{code}/end_session
+func (s *Server) HandleEndSession(w http.ResponseWriter, r *http.Request) {
+	roomCode := r.PathValue("code")
+	if roomCode == "" {
+		http.Error(w, "Room code required", http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("X-Session-Token")
+	if token == "" {
+		http.Error(w, "Session token required", http.StatusUnauthorized)
+		return
+	}
+
+	room, err := s.store.GetRoom(roomCode)
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	host, err := s.connMgr.authenticatePlayer(room, token)
+	if err != nil {
+		http.Error(w, "Invalid session token", http.StatusUnauthorized)
+		return
+	}
+	if !room.IsHost(host.ID) {
+		http.Error(w, "Only the host can end the session", http.StatusForbidden)
+		return
+	}
+
+	summary, event, err := room.EndSession()
+	if err != nil {
+		if errors.Is(err, core.ErrNoRounds) {
+			http.Error(w, "No games have finished this session", http.StatusConflict)
+			return
+		}
+		slog.Error("failed to end session", "roomCode", roomCode, "error", err)
+		http.Error(w, "Failed to end session", http.StatusInternalServerError)
+		return
+	}
+
+	if err := s.store.UpdateRoom(room); err != nil {
+		slog.Error("failed to persist room", "roomCode", roomCode, "error", err)
+	}
+	s.connMgr.BroadcastEvent(roomCode, event)
+
+	slog.Info("session ended", "roomCode", roomCode, "rounds", len(summary.Rounds))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
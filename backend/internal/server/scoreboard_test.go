@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+// sessionRequest calls a session handler for a room.
+func sessionRequest(handler http.HandlerFunc, method, roomCode, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/rooms/"+roomCode+"/session", nil)
+	req.SetPathValue("code", roomCode)
+	if token != "" {
+		req.Header.Set("X-Session-Token", token)
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestServer_SessionScoreboard(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions()
+	options.Scoring = map[string]core.ScoringRules{"werewolf": {Win: 3, Survived: 1}}
+	s := NewServerWithOptions(store.NewMemoryStore(), options)
+	room, host, player := setupLobby(t, s)
+
+	// The host wins every round of the stand-in game
+	for round := 0; round < 2; round++ {
+		if err := room.StartGame(&oneMoveGame{}, oneMoveConfig{}); err != nil {
+			t.Fatalf("failed to start game: %v", err)
+		}
+		if err := s.connMgr.processAction(room, host.ID, core.Action{Type: "finish"}); err != nil {
+			t.Fatalf("failed to process action: %v", err)
+		}
+		if err := room.ResetGame(); err != nil {
+			t.Fatalf("failed to reset game: %v", err)
+		}
+	}
+
+	rec := sessionRequest(s.HandleSessionSummary, http.MethodGet, room.ID, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var summary core.SessionSummary
+	json.Unmarshal(rec.Body.Bytes(), &summary)
+	if summary.Round != 2 || len(summary.Rounds) != 2 {
+		t.Fatalf("expected 2 rounds, got %+v", summary)
+	}
+	if first := summary.Standings[0]; first.PlayerID != host.ID || first.Points != 8 || first.Wins != 2 {
+		t.Errorf("expected the host first with 8 points, got %+v", first)
+	}
+	if second := summary.Standings[1]; second.PlayerID != player.ID || second.Points != 2 {
+		t.Errorf("expected Alice second with 2 survival points, got %+v", second)
+	}
+
+	if rec := sessionRequest(s.HandleEndSession, http.MethodPost, room.ID, player.SessionToken); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a non-host, got %d", rec.Code)
+	}
+	if rec := sessionRequest(s.HandleEndSession, http.MethodPost, room.ID, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", rec.Code)
+	}
+
+	rec = sessionRequest(s.HandleEndSession, http.MethodPost, room.ID, host.SessionToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var finalStandings bool
+	for _, event := range room.GetPublicEvents() {
+		if event.Type == core.EventFinalStandings {
+			finalStandings = true
+		}
+	}
+	if !finalStandings {
+		t.Error("expected a final_standings event")
+	}
+
+	if rec := sessionRequest(s.HandleEndSession, http.MethodPost, room.ID, host.SessionToken); rec.Code != http.StatusConflict {
+		t.Errorf("expected 409 for an empty session, got %d", rec.Code)
+	}
+	if rec := sessionRequest(s.HandleSessionSummary, http.MethodGet, "NOROOM", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown room, got %d", rec.Code)
+	}
+}
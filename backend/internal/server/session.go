@@ -0,0 +1,194 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/auth"
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/profile"
+	"nhooyr.io/websocket"
+)
+
+// CapabilityTokenRotation makes the server issue a fresh session token on
+// every WebSocket authentication, returned in the authenticated message.
+// Clients opting in must store the new token for their next connection.
+const CapabilityTokenRotation = "token_rotation"
+
+func init() {
+	serverCapabilities[CapabilityTokenRotation] = true
+}
+
+// newIssuer creates a token issuer, generating a random key if none is given.
+func newIssuer(key []byte, ttl time.Duration) *auth.Issuer {
+	if len(key) == 0 {
+		var err error
+		if key, err = auth.GenerateKey(); err != nil {
+			// crypto/rand only fails if the OS entropy source is broken
+			panic("failed to generate session key: " + err.Error())
+		}
+	}
+
+	issuer, err := auth.NewIssuer(key, ttl)
+	if err != nil {
+		// Options are validated by config
+		panic("invalid session options: " + err.Error())
+	}
+	return issuer
+}
+
+// newPlayer creates a player holding a signed session token for the room,
+// linked to the profile if one is given.
+func (s *Server) newPlayer(roomCode string, displayName string, p *profile.Profile) (*core.Player, error) {
+	player := core.NewPlayer(displayName)
+	if p != nil {
+		player.ProfileID = p.ID
+		player.AvatarColor = p.AvatarColor
+	}
+
+	token, err := s.connMgr.tokens.Issue(roomCode, player.ID)
+	if err != nil {
+		return nil, err
+	}
+	player.SessionToken = token
+
+	return player, nil
+}
+
+// authenticatePlayer resolves a session token to a player of the room.
+// The token must be validly signed, unexpired, issued for this room and
+// player, and not rotated away or revoked.
+func (cm *ConnectionManager) authenticatePlayer(room *core.Room, token string) (*core.Player, error) {
+	claims, err := cm.tokens.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.RoomCode != room.ID {
+		return nil, auth.ErrInvalidToken
+	}
+
+	player, err := room.GetPlayerByToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if player.ID != claims.PlayerID {
+		return nil, auth.ErrInvalidToken
+	}
+
+	return player, nil
+}
+
+// rotateToken replaces a player's session token after they authenticated
+// with presented, and persists the change so the new token survives a
+// restart. It returns the new token.
+func (cm *ConnectionManager) rotateToken(room *core.Room, player *core.Player, presented string) (string, error) {
+	token, err := cm.tokens.Issue(room.ID, player.ID)
+	if err != nil {
+		return "", err
+	}
+	if err := room.RotatePlayerToken(player.ID, presented, token); err != nil {
+		return "", err
+	}
+	if err := cm.store.UpdateRoom(room); err != nil {
+		slog.Error("failed to persist rotated session", "roomCode", room.ID, "error", err)
+	}
+	return token, nil
+}
+
+// disconnectPlayer closes a player's connection, telling them why first.
+func (cm *ConnectionManager) disconnectPlayer(playerID string, msg ServerMessage, reason string) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if conn, exists := cm.connections[playerID]; exists {
+		conn.sendAndClose(msg, websocket.StatusPolicyViolation, reason)
+	}
+}
+
+// HandleKickPlayer lets the host remove a player from the lobby.
+// The player's session is revoked, so they cannot reconnect.
+func (s *Server) HandleKickPlayer(w http.ResponseWriter, r *http.Request) {
+	roomCode := r.PathValue("code")
+	targetID := r.PathValue("playerId")
+	if roomCode == "" || targetID == "" {
+		http.Error(w, "Room code and player ID required", http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("X-Session-Token")
+	if token == "" {
+		http.Error(w, "Session token required", http.StatusUnauthorized)
+		return
+	}
+
+	room, err := s.store.GetRoom(roomCode)
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	host, err := s.connMgr.authenticatePlayer(room, token)
+	if err != nil {
+		http.Error(w, "Invalid session token", http.StatusUnauthorized)
+		return
+	}
+	if !room.IsHost(host.ID) {
+		http.Error(w, "Only the host can remove players", http.StatusForbidden)
+		return
+	}
+	if targetID == host.ID {
+		http.Error(w, "The host cannot remove themselves", http.StatusBadRequest)
+		return
+	}
+
+	target, err := room.GetPlayer(targetID)
+	if err != nil {
+		http.Error(w, "Player not found", http.StatusNotFound)
+		return
+	}
+	if err := room.KickPlayer(targetID); err != nil {
+		if errors.Is(err, core.ErrGameInProgress) {
+			http.Error(w, "Players can only be removed before the game starts", http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if err := s.store.UpdateRoom(room); err != nil {
+		slog.Error("failed to persist room", "roomCode", roomCode, "error", err)
+	}
+	s.bots.Remove(targetID)
+
+	notice, err := NewNoticeMessage(NoticeLevelWarning, "The host removed you from the room")
+	if err == nil {
+		s.connMgr.disconnectPlayer(targetID, notice, "removed from room")
+	}
+
+	event, _ := core.NewPublicEvent(core.EventPlayerLeft, "system", core.PlayerLeftPayload{
+		PlayerID: targetID,
+	})
+	room.AppendEvent(event)
+	s.connMgr.BroadcastEvent(roomCode, event)
+	s.connMgr.BroadcastRoomState(roomCode)
+
+	slog.Info("player removed from room",
+		"playerName", target.DisplayName,
+		"playerID", targetID,
+		"roomCode", roomCode,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "removed"})
+}
+
+// sessionError returns the close reason for a failed authentication.
+func sessionError(err error) string {
+	if errors.Is(err, auth.ErrExpiredToken) {
+		return "session expired"
+	}
+	return "invalid session token"
+}
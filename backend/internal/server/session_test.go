@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/auth"
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/store"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+// setupLobby creates a waiting room with a host and one other player.
+func setupLobby(t *testing.T, s *Server) (*core.Room, *core.Player, *core.Player) {
+	t.Helper()
+
+	host, err := s.newPlayer("LOBBY1", "Host", nil)
+	if err != nil {
+		t.Fatalf("failed to create player: %v", err)
+	}
+	room := core.NewRoom("LOBBY1", "werewolf", host, 10)
+	if err := s.store.CreateRoom(room); err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+
+	player, err := s.newPlayer(room.ID, "Alice", nil)
+	if err != nil {
+		t.Fatalf("failed to create player: %v", err)
+	}
+	if err := room.AddPlayer(player); err != nil {
+		t.Fatalf("failed to add player: %v", err)
+	}
+
+	return room, host, player
+}
+
+func TestConnectionManager_AuthenticatePlayer(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room, host, player := setupLobby(t, server)
+
+	otherRoomToken, _ := server.connMgr.tokens.Issue("OTHER1", player.ID)
+	wrongPlayerToken, _ := server.connMgr.tokens.Issue(room.ID, host.ID)
+	otherKeyIssuer := newIssuer(nil, time.Hour)
+	forgedToken, _ := otherKeyIssuer.Issue(room.ID, player.ID)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantID  string
+		wantErr error
+	}{
+		{name: "valid token", token: player.SessionToken, wantID: player.ID},
+		{name: "token for another room", token: otherRoomToken, wantErr: auth.ErrInvalidToken},
+		{name: "signed but never handed out", token: wrongPlayerToken, wantErr: core.ErrInvalidSessionToken},
+		{name: "signed with another key", token: forgedToken, wantErr: auth.ErrInvalidToken},
+		{name: "legacy random token", token: "dGhpcyBpcyBub3QgYSBzaWduZWQgdG9rZW4", wantErr: auth.ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := server.connMgr.authenticatePlayer(room, tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.ID != tt.wantID {
+				t.Errorf("expected player %s, got %s", tt.wantID, got.ID)
+			}
+		})
+	}
+}
+
+func TestHandleWebSocket_TokenRotation(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room, _, player := setupLobby(t, server)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/rooms/{code}/ws", server.HandleWebSocket)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/rooms/" + room.ID + "/ws"
+
+	// connect authenticates with token and returns the authenticated payload,
+	// or the close status if the server refused.
+	connect := func(token string, capabilities []string) (AuthenticatedPayload, websocket.StatusCode) {
+		conn, _, err := websocket.Dial(ctx, wsURL, &websocket.DialOptions{
+			HTTPHeader: http.Header{"Origin": []string{"http://localhost:5173"}},
+		})
+		if err != nil {
+			t.Fatalf("failed to dial: %v", err)
+		}
+		defer conn.Close(websocket.StatusNormalClosure, "")
+
+		payload, _ := json.Marshal(AuthenticatePayload{
+			SessionToken:    token,
+			ProtocolVersion: CurrentProtocolVersion,
+			Capabilities:    capabilities,
+		})
+		if err := wsjson.Write(ctx, conn, ClientMessage{Type: ClientMsgAuthenticate, Payload: payload}); err != nil {
+			t.Fatalf("failed to send authenticate: %v", err)
+		}
+
+		var msg ServerMessage
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			return AuthenticatedPayload{}, websocket.CloseStatus(err)
+		}
+		if msg.Type != ServerMsgAuthenticated {
+			t.Fatalf("expected authenticated message, got %s", msg.Type)
+		}
+
+		var authenticated AuthenticatedPayload
+		json.Unmarshal(msg.Payload, &authenticated)
+		return authenticated, -1
+	}
+
+	// Without the capability the token is kept
+	if got, status := connect(player.SessionToken, nil); status != -1 || got.SessionToken != "" {
+		t.Fatalf("expected no rotation without capability, got %q (status %d)", got.SessionToken, status)
+	}
+
+	first := player.SessionToken
+	got, status := connect(first, []string{CapabilityTokenRotation})
+	if status != -1 || got.SessionToken == "" || got.SessionToken == first {
+		t.Fatalf("expected a rotated token, got %q (status %d)", got.SessionToken, status)
+	}
+	second := got.SessionToken
+
+	got, status = connect(second, []string{CapabilityTokenRotation})
+	if status != -1 || got.SessionToken == "" {
+		t.Fatalf("expected a rotated token, got %q (status %d)", got.SessionToken, status)
+	}
+
+	// The token presented last stays valid, older ones are revoked
+	if _, status := connect(second, nil); status != -1 {
+		t.Errorf("expected presented token to stay valid, got status %d", status)
+	}
+	if _, status := connect(first, nil); status != websocket.StatusPolicyViolation {
+		t.Errorf("expected revoked token to be refused, got status %d", status)
+	}
+}
+
+func TestHandleKickPlayer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		asHost         bool
+		target         string // "player", "host" or a literal ID
+		startGame      bool
+		wantStatusCode int
+	}{
+		{name: "host kicks player", asHost: true, target: "player", wantStatusCode: http.StatusOK},
+		{name: "player cannot kick", asHost: false, target: "host", wantStatusCode: http.StatusForbidden},
+		{name: "host cannot kick themselves", asHost: true, target: "host", wantStatusCode: http.StatusBadRequest},
+		{name: "unknown player", asHost: true, target: "missing", wantStatusCode: http.StatusNotFound},
+		{name: "refused during game", asHost: true, target: "player", startGame: true, wantStatusCode: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := NewServer(store.NewMemoryStore())
+			room, host, player := setupLobby(t, server)
+			if tt.startGame {
+				room.Status = core.RoomStatusPlaying
+			}
+
+			caller := player
+			if tt.asHost {
+				caller = host
+			}
+			targetID := tt.target
+			switch tt.target {
+			case "player":
+				targetID = player.ID
+			case "host":
+				targetID = host.ID
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/rooms/"+room.ID+"/players/"+targetID, nil)
+			req.SetPathValue("code", room.ID)
+			req.SetPathValue("playerId", targetID)
+			req.Header.Set("X-Session-Token", caller.SessionToken)
+			rec := httptest.NewRecorder()
+			server.HandleKickPlayer(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+
+			_, err := server.connMgr.authenticatePlayer(room, player.SessionToken)
+			if tt.wantStatusCode == http.StatusOK {
+				if err == nil {
+					t.Error("expected kicked player's token to be revoked")
+				}
+				if _, err := room.GetPlayer(player.ID); err == nil {
+					t.Error("expected kicked player to leave the room")
+				}
+			} else if err != nil {
+				t.Errorf("expected player's token to stay valid, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+// Drain prepares the server to stop: bots stop playing, clients are told to
+// reconnect after reconnectAfter, their connections are closed and new ones
+// are refused. It returns once every connection is gone or ctx expires.
+func (s *Server) Drain(ctx context.Context, reconnectAfter time.Duration) error {
+	s.bots.Stop()
+	return s.connMgr.Drain(ctx, reconnectAfter)
+}
+
+// FlushStore persists every room if the store supports it. It reports false
+// when rooms are held in memory only and will be lost on exit.
+func (s *Server) FlushStore() (bool, error) {
+	flusher, ok := s.store.(store.Flusher)
+	if !ok {
+		return false, nil
+	}
+	return true, flusher.Flush()
+}
+
+// RestoreRooms attaches the server to rooms the store loaded at startup,
+// so restored games report metrics like new ones and bot seats resume
+// playing. Players reconnect with their existing session tokens. It returns
+// the number of rooms restored.
+func (s *Server) RestoreRooms() (int, error) {
+	rooms, err := s.store.ListRooms()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, room := range rooms {
+		room.SetObserver(s.metrics)
+		s.bots.Notify(room)
+	}
+
+	if len(rooms) > 0 {
+		slog.Info("restored rooms from store", "count", len(rooms))
+	}
+	return len(rooms), nil
+}
+
+// refuseWhileDraining writes 503 Service Unavailable if the server is
+// shutting down, and reports whether it did.
+func (s *Server) refuseWhileDraining(w http.ResponseWriter) bool {
+	draining, reconnectAfter := s.connMgr.Draining()
+	if !draining {
+		return false
+	}
+
+	seconds := int(reconnectAfter.Round(time.Second) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	http.Error(w, "Server is restarting", http.StatusServiceUnavailable)
+	return true
+}
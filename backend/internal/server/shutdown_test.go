@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/games"
+	"github.com/KonradHerman/roundtable/internal/store"
+	"nhooyr.io/websocket"
+	"nhooyr.io/websocket/wsjson"
+)
+
+func TestServer_Drain(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room, tokens := setupWerewolfGame(t, server)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/rooms/{code}/ws", server.HandleWebSocket)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/rooms/" + room.ID + "/ws"
+	dialOptions := &websocket.DialOptions{
+		HTTPHeader: http.Header{"Origin": []string{"http://localhost:5173"}},
+	}
+	conn, _, err := websocket.Dial(ctx, wsURL, dialOptions)
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	authPayload, _ := json.Marshal(AuthenticatePayload{SessionToken: tokens[room.HostID], ProtocolVersion: CurrentProtocolVersion})
+	wsjson.Write(ctx, conn, ClientMessage{Type: ClientMsgAuthenticate, Payload: authPayload})
+
+	var msg ServerMessage
+	if err := wsjson.Read(ctx, conn, &msg); err != nil || msg.Type != ServerMsgAuthenticated {
+		t.Fatalf("expected authenticated message, got %v (%v)", msg.Type, err)
+	}
+
+	drained := make(chan error, 1)
+	go func() {
+		drained <- server.Drain(ctx, 2*time.Second)
+	}()
+
+	// The restart message arrives after the initial history, then the socket closes
+	for {
+		msg = ServerMessage{}
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			t.Fatalf("expected server_restarting before close, got %v", err)
+		}
+		if msg.Type == ServerMsgRestarting {
+			break
+		}
+	}
+
+	var payload ServerRestartingPayload
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.ReconnectAfterMs != 2000 {
+		t.Errorf("expected reconnect hint of 2000ms, got %d", payload.ReconnectAfterMs)
+	}
+
+	err = wsjson.Read(ctx, conn, &msg)
+	if status := websocket.CloseStatus(err); status != websocket.StatusServiceRestart {
+		t.Errorf("expected service restart closure, got %v", err)
+	}
+
+	if err := <-drained; err != nil {
+		t.Fatalf("drain failed: %v", err)
+	}
+	if counts := server.connMgr.ConnectionCounts(); len(counts) != 0 {
+		t.Errorf("expected no connections after drain, got %v", counts)
+	}
+
+	// New connections are refused with a retry hint
+	_, resp, err := websocket.Dial(ctx, wsURL, dialOptions)
+	if err == nil {
+		t.Fatal("expected dial to fail while draining")
+	}
+	if resp == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 while draining, got %v", resp)
+	}
+	if resp.Header.Get("Retry-After") != "2" {
+		t.Errorf("expected Retry-After 2, got %q", resp.Header.Get("Retry-After"))
+	}
+}
+
+func TestServer_DrainTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room, _ := setupWerewolfGame(t, server)
+
+	// A connection whose writer never runs is never removed
+	conn := &Connection{PlayerID: room.HostID, RoomCode: room.ID, Send: make(chan ServerMessage, 1), cancel: func() {}}
+	server.connMgr.mu.Lock()
+	server.connMgr.connections[room.HostID] = conn
+	server.connMgr.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := server.Drain(ctx, time.Second); err == nil {
+		t.Error("expected drain to time out")
+	}
+}
+
+func TestServer_FlushAndRestoreRooms(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		fileStore     bool
+		wantPersisted bool
+	}{
+		{name: "memory store", fileStore: false, wantPersisted: false},
+		{name: "file store", fileStore: true, wantPersisted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			newStore := func() store.Store {
+				if !tt.fileStore {
+					return store.NewMemoryStore()
+				}
+				fileStore, err := store.NewFileStore(dir, games.NewRegistry().CreateGame, store.DefaultOptions())
+				if err != nil {
+					t.Fatalf("failed to open file store: %v", err)
+				}
+				return fileStore
+			}
+
+			server := NewServer(newStore())
+			room, _ := setupWerewolfGame(t, server)
+
+			persisted, err := server.FlushStore()
+			if err != nil {
+				t.Fatalf("flush failed: %v", err)
+			}
+			if persisted != tt.wantPersisted {
+				t.Errorf("expected persisted %v, got %v", tt.wantPersisted, persisted)
+			}
+
+			if !tt.fileStore {
+				return
+			}
+
+			restarted := NewServer(newStore())
+			restored, err := restarted.RestoreRooms()
+			if err != nil {
+				t.Fatalf("restore failed: %v", err)
+			}
+			if restored != 1 {
+				t.Fatalf("expected 1 restored room, got %d", restored)
+			}
+
+			restoredRoom, err := restarted.store.GetRoom(room.ID)
+			if err != nil {
+				t.Fatalf("room not restored: %v", err)
+			}
+			if restoredRoom.Game == nil {
+				t.Error("expected game to be restored")
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// sseKeepAliveInterval is how often a comment line is written to idle SSE
+// streams so proxies don't time them out.
+const sseKeepAliveInterval = 30 * time.Second
+
+// sessionTokenFromRequest returns the session token from the X-Session-Token
+// header, falling back to the "token" query parameter. EventSource cannot set
+// custom headers, so SSE clients have to pass the token in the URL.
+func sessionTokenFromRequest(r *http.Request) string {
+	if token := r.Header.Get("X-Session-Token"); token != "" {
+		return token
+	}
+	return r.URL.Query().Get("token")
+}
+
+// HandleAction processes a game action submitted over plain HTTP.
+// This is the fallback for clients that cannot keep a WebSocket open.
+func (s *Server) HandleAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Limit request body to 1MB
+	r.Body = http.MaxBytesReader(w, r.Body, 1*1024*1024)
+
+	roomCode := r.PathValue("code")
+	if roomCode == "" {
+		http.Error(w, "Room code required", http.StatusBadRequest)
+		return
+	}
+
+	token := r.Header.Get("X-Session-Token")
+	if token == "" {
+		http.Error(w, "Session token required", http.StatusUnauthorized)
+		return
+	}
+
+	var req ActionPayload
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Request too large or malformed", http.StatusBadRequest)
+		return
+	}
+
+	room, err := s.store.GetRoom(roomCode)
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	player, err := s.connMgr.authenticatePlayer(room, token)
+	if err != nil {
+		http.Error(w, "Invalid session token", http.StatusUnauthorized)
+		return
+	}
+	player.UpdateLastSeen()
+
+	if allowed, retryAfter := s.connMgr.allowAction(player.ID); !allowed {
+		writeTooManyRequests(w, retryAfter)
+		return
+	}
+
+	if err := s.connMgr.processAction(room, player.ID, req.Action); err != nil {
+		http.Error(w, fmt.Sprintf("Action failed: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// HandleEvents streams server messages to a player as Server-Sent Events.
+// The stream carries the same messages as the WebSocket (authenticated,
+// events, room_state, ...), filtered by the same event visibility rules.
+func (s *Server) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	if s.refuseWhileDraining(w) {
+		return
+	}
+
+	roomCode := r.PathValue("code")
+	if roomCode == "" {
+		http.Error(w, "Room code required", http.StatusBadRequest)
+		return
+	}
+
+	token := sessionTokenFromRequest(r)
+	if token == "" {
+		http.Error(w, "Session token required", http.StatusUnauthorized)
+		return
+	}
+
+	room, err := s.store.GetRoom(roomCode)
+	if err != nil {
+		http.Error(w, "Room not found", http.StatusNotFound)
+		return
+	}
+
+	player, err := s.connMgr.authenticatePlayer(room, token)
+	if err != nil {
+		http.Error(w, "Invalid session token", http.StatusUnauthorized)
+		return
+	}
+
+	// SSE is newer than protocol v1, so clients default to the current version
+	requestedVersion := CurrentProtocolVersion
+	if v := r.URL.Query().Get("protocolVersion"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "Invalid protocol version", http.StatusBadRequest)
+			return
+		}
+		requestedVersion = parsed
+	}
+
+	version, capabilities, err := s.connMgr.negotiate(requestedVersion, r.URL.Query()["capability"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// The HTTP server's WriteTimeout would otherwise cut the stream off.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		slog.Warn("failed to clear SSE write deadline", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	connection := s.connMgr.newConnection(r.Context(), player, roomCode, nil, jsonCodec{}, version, capabilities)
+
+	s.connMgr.register(connection, player)
+	s.connMgr.sendInitialState(connection, room, player, "")
+
+	connection.ssePump(w, flusher)
+
+	s.connMgr.handleDisconnect(connection, room)
+}
+
+// ssePump writes queued messages to the event stream until the client goes away
+// or the connection is replaced.
+func (c *Connection) ssePump(w http.ResponseWriter, flusher http.Flusher) {
+	defer c.cancel()
+
+	ticker := time.NewTicker(sseKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg := <-c.Send:
+			data, ok, err := msg.encode(c.protocolVersion, c.codec)
+			if err != nil {
+				slog.Error("encode error", "playerID", c.PlayerID, "type", msg.Type, "error", err)
+				continue
+			}
+			if ok {
+				if err := writeSSEFrame(w, msg.Type, data); err != nil {
+					slog.Error("sse write error", "playerID", c.PlayerID, "error", err)
+					return
+				}
+				flusher.Flush()
+			}
+
+			if msg.closeAfter != nil {
+				return
+			}
+
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				slog.Error("sse keepalive error", "playerID", c.PlayerID, "error", err)
+				return
+			}
+			flusher.Flush()
+
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// writeSSEFrame writes a single encoded server message as an SSE frame.
+// The SSE event name is the message type and the data is the full JSON
+// message, so clients can reuse their WebSocket message handling.
+func writeSSEFrame(w http.ResponseWriter, msgType string, data []byte) error {
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msgType, data)
+	return err
+}
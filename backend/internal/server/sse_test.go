@@ -0,0 +1,237 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/games/werewolf"
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+// setupWerewolfGame creates a room with three players and starts a werewolf game.
+// Returns the room and a playerID → session token map.
+func setupWerewolfGame(t *testing.T, s *Server) (*core.Room, map[string]string) {
+	t.Helper()
+
+	host, err := s.newPlayer("SSEABC", "Host", nil)
+	if err != nil {
+		t.Fatalf("failed to create player: %v", err)
+	}
+	room := core.NewRoom("SSEABC", "werewolf", host, 10)
+	if err := s.store.CreateRoom(room); err != nil {
+		t.Fatalf("failed to create room: %v", err)
+	}
+
+	tokens := map[string]string{host.ID: host.SessionToken}
+	for _, name := range []string{"Alice", "Bob"} {
+		p, err := s.newPlayer(room.ID, name, nil)
+		if err != nil {
+			t.Fatalf("failed to create player: %v", err)
+		}
+		if err := room.AddPlayer(p); err != nil {
+			t.Fatalf("failed to add player: %v", err)
+		}
+		tokens[p.ID] = p.SessionToken
+	}
+
+	config := &werewolf.Config{Roles: []werewolf.RoleType{
+		werewolf.RoleWerewolf, werewolf.RoleWerewolf, werewolf.RoleSeer,
+		werewolf.RoleRobber, werewolf.RoleVillager, werewolf.RoleVillager,
+	}}
+	if err := room.StartGame(werewolf.NewGame(), config); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	return room, tokens
+}
+
+func TestHandleAction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		token          func(tokens map[string]string, playerID string) string
+		body           string
+		wantStatusCode int
+	}{
+		{
+			name:           "acknowledge role succeeds",
+			token:          func(tokens map[string]string, playerID string) string { return tokens[playerID] },
+			body:           `{"action":{"type":"acknowledge_role","payload":{}}}`,
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "missing token",
+			token:          func(tokens map[string]string, playerID string) string { return "" },
+			body:           `{"action":{"type":"acknowledge_role","payload":{}}}`,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid token",
+			token:          func(tokens map[string]string, playerID string) string { return "not-a-token" },
+			body:           `{"action":{"type":"acknowledge_role","payload":{}}}`,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid action for phase",
+			token:          func(tokens map[string]string, playerID string) string { return tokens[playerID] },
+			body:           `{"action":{"type":"vote","payload":{"targetId":"x"}}}`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed body",
+			token:          func(tokens map[string]string, playerID string) string { return tokens[playerID] },
+			body:           `not json`,
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := NewServer(store.NewMemoryStore())
+			room, tokens := setupWerewolfGame(t, server)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/rooms/"+room.ID+"/actions", bytes.NewBufferString(tt.body))
+			req.SetPathValue("code", room.ID)
+			if token := tt.token(tokens, room.HostID); token != "" {
+				req.Header.Set("X-Session-Token", token)
+			}
+			rec := httptest.NewRecorder()
+
+			server.HandleAction(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d (body: %s)", tt.wantStatusCode, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleEvents_Stream(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room, tokens := setupWerewolfGame(t, server)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/rooms/{code}/events", server.HandleEvents)
+	mux.HandleFunc("POST /api/rooms/{code}/actions", server.HandleAction)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/api/rooms/"+room.ID+"/events?token="+tokens[room.HostID], nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to open event stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream content type, got %q", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readFrame := func() (string, ServerMessage) {
+		var eventName string
+		var msg ServerMessage
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("failed to read frame: %v", err)
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case line == "":
+				return eventName, msg
+			case strings.HasPrefix(line, "event: "):
+				eventName = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &msg); err != nil {
+					t.Fatalf("failed to decode data line: %v", err)
+				}
+			}
+		}
+	}
+
+	if name, msg := readFrame(); name != ServerMsgAuthenticated || msg.Type != ServerMsgAuthenticated {
+		t.Fatalf("expected authenticated frame first, got %q", name)
+	}
+	if name, _ := readFrame(); name != ServerMsgEvents {
+		t.Fatalf("expected events history frame, got %q", name)
+	}
+
+	// Acknowledge via the HTTP action endpoint and expect the event on the stream
+	actionReq, _ := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL+"/api/rooms/"+room.ID+"/actions",
+		bytes.NewBufferString(`{"action":{"type":"acknowledge_role","payload":{}}}`))
+	actionReq.Header.Set("X-Session-Token", tokens[room.HostID])
+	actionResp, err := http.DefaultClient.Do(actionReq)
+	if err != nil {
+		t.Fatalf("failed to post action: %v", err)
+	}
+	actionResp.Body.Close()
+	if actionResp.StatusCode != http.StatusOK {
+		t.Fatalf("expected action status 200, got %d", actionResp.StatusCode)
+	}
+
+	for {
+		name, msg := readFrame()
+		if name != ServerMsgEvent {
+			continue
+		}
+		var payload EventPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			t.Fatalf("failed to decode event payload: %v", err)
+		}
+		if payload.Event.Type == "role_acknowledged" {
+			break
+		}
+	}
+}
+
+func TestHandleEvents_Errors(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(store.NewMemoryStore())
+	room, _ := setupWerewolfGame(t, server)
+
+	tests := []struct {
+		name           string
+		roomCode       string
+		query          string
+		wantStatusCode int
+	}{
+		{name: "missing token", roomCode: room.ID, wantStatusCode: http.StatusUnauthorized},
+		{name: "invalid token", roomCode: room.ID, query: "?token=bogus", wantStatusCode: http.StatusUnauthorized},
+		{name: "unknown room", roomCode: "NOROOM", query: "?token=bogus", wantStatusCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/rooms/"+tt.roomCode+"/events"+tt.query, nil)
+			req.SetPathValue("code", tt.roomCode)
+			rec := httptest.NewRecorder()
+
+			server.HandleEvents(rec, req)
+
+			if rec.Code != tt.wantStatusCode {
+				t.Errorf("expected status %d, got %d", tt.wantStatusCode, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/games/avalon"
+	"github.com/KonradHerman/roundtable/internal/games/werewolf"
+	"github.com/KonradHerman/roundtable/internal/stats"
+)
+
+// maxLeaderboardProfiles caps the group a leaderboard is requested for.
+const maxLeaderboardProfiles = 50
+
+// SetStats replaces the server's in-memory statistics store, e.g. with one
+// persisted to the data directory. Call it before serving requests.
+func (s *Server) SetStats(statsStore *stats.Store) {
+	s.stats = statsStore
+}
+
+// recordGame counts a finished game in the statistics of every player who
+// joined with a profile, and updates their ratings. Games with bot seats are
+// not recorded: beating bots would inflate records and ratings.
+func (s *Server) recordGame(room *core.Room) {
+	for _, player := range room.GetPlayers() {
+		if player.IsBot() {
+			slog.Info("skipped game stats for a room with bots", "roomCode", room.ID)
+			return
+		}
+	}
+
+	byProfile := make(map[string]core.PlayerOutcome)
+	var rated []stats.RatedPlayer
+	for _, outcome := range room.GameOutcomes() {
+		player, err := room.GetPlayer(outcome.PlayerID)
+		if err != nil {
+			continue
+		}
+		// Players without a profile still count as opponents
+		rated = append(rated, stats.RatedPlayer{ProfileID: player.ProfileID, Team: outcome.Team, Won: outcome.Won})
+		if player.ProfileID != "" {
+			byProfile[player.ProfileID] = outcome
+		}
+	}
+	if len(byProfile) == 0 {
+		return
+	}
+
+	if err := s.stats.RecordGame(room.GameType, byProfile); err != nil {
+		slog.Error("failed to record game stats", "roomCode", room.ID, "error", err)
+		return
+	}
+	if err := s.stats.RateGame(room.GameType, rated); err != nil {
+		slog.Error("failed to rate game", "roomCode", room.ID, "error", err)
+		return
+	}
+	slog.Info("recorded game stats", "roomCode", room.ID, "gameType", room.GameType, "profiles", len(byProfile))
+}
+
+// StatsHighlights are the records players argue about most.
+type StatsHighlights struct {
+	// MerlinSurvivalRate is the share of Avalon games as Merlin in which
+	// Merlin was not assassinated, if the player has been Merlin.
+	MerlinSurvivalRate *float64 `json:"merlinSurvivalRate,omitempty"`
+
+	// EliminatedAsWerewolf counts Werewolf games in which the player was
+	// voted out holding the werewolf card.
+	EliminatedAsWerewolf int `json:"eliminatedAsWerewolf"`
+}
+
+// StatsResponse is the response for a profile's statistics.
+type StatsResponse struct {
+	stats.Stats
+	DisplayName string          `json:"displayName"`
+	AvatarColor string          `json:"avatarColor"`
+	Highlights  StatsHighlights `json:"highlights"`
+}
+
+// highlights picks the highlights out of a profile's statistics.
+func highlights(profileStats stats.Stats) StatsHighlights {
+	var h StatsHighlights
+	if games, ok := profileStats.Games["avalon"]; ok {
+		if merlin := games.Role(string(avalon.RoleMerlin)); merlin.Played > 0 {
+			rate := float64(merlin.Played-merlin.Eliminated) / float64(merlin.Played)
+			h.MerlinSurvivalRate = &rate
+		}
+	}
+	if games, ok := profileStats.Games["werewolf"]; ok {
+		h.EliminatedAsWerewolf = games.Role(string(werewolf.RoleWerewolf)).Eliminated
+	}
+	return h
+}
+
+// HandleProfileStats returns a profile's statistics. "me" refers to the
+// caller's profile.
+// Expected format: GET /api/profiles/{id}/stats
+func (s *Server) HandleProfileStats(w http.ResponseWriter, r *http.Request) {
+	profileID := r.PathValue("id")
+	if profileID == "me" {
+		p, ok := s.authenticatedProfile(w, r)
+		if !ok {
+			return
+		}
+		profileID = p.ID
+	}
+
+	p, err := s.profiles.Get(profileID)
+	if err != nil {
+		writeProfileError(w, err)
+		return
+	}
+
+	profileStats := s.stats.Get(p.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(StatsResponse{
+		Stats:       profileStats,
+		DisplayName: p.DisplayName,
+		AvatarColor: p.AvatarColor,
+		Highlights:  highlights(profileStats),
+	})
+}
+
+// LeaderboardEntry is a profile's place on a leaderboard.
+type LeaderboardEntry struct {
+	stats.Standing
+	DisplayName string `json:"displayName"`
+	AvatarColor string `json:"avatarColor"`
+}
+
+// LeaderboardResponse is the response for a group leaderboard.
+type LeaderboardResponse struct {
+	GameType  string             `json:"gameType"`
+	Sort      string             `json:"sort"`
+	Team      string             `json:"team,omitempty"`
+	Standings []LeaderboardEntry `json:"standings"`
+}
+
+// HandleLeaderboard ranks a group of profiles, such as the players in a room,
+// by their record in one game, or by their rating with sort=rating. A team,
+// such as "evil" in Avalon, ranks ratings playing that side.
+// Expected format: GET /api/leaderboard?game=avalon&profiles=id1,id2[&sort=rating][&team=good]
+func (s *Server) HandleLeaderboard(w http.ResponseWriter, r *http.Request) {
+	gameType := r.URL.Query().Get("game")
+	if !s.gameRegistry.IsRegistered(gameType) {
+		http.Error(w, "Unknown game type", http.StatusBadRequest)
+		return
+	}
+
+	sortBy := r.URL.Query().Get("sort")
+	if sortBy == "" {
+		sortBy = "winRate"
+	}
+	if sortBy != "winRate" && sortBy != "rating" {
+		http.Error(w, "Sort must be winRate or rating", http.StatusBadRequest)
+		return
+	}
+	team := r.URL.Query().Get("team")
+	if team != "" && sortBy != "rating" {
+		http.Error(w, "Team requires sort=rating", http.StatusBadRequest)
+		return
+	}
+
+	var profileIDs []string
+	for _, id := range strings.Split(r.URL.Query().Get("profiles"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			profileIDs = append(profileIDs, id)
+		}
+	}
+	if len(profileIDs) == 0 {
+		http.Error(w, "Profiles required", http.StatusBadRequest)
+		return
+	}
+	if len(profileIDs) > maxLeaderboardProfiles {
+		http.Error(w, "Too many profiles", http.StatusBadRequest)
+		return
+	}
+
+	ranked := s.stats.Leaderboard(gameType, profileIDs)
+	if sortBy == "rating" {
+		ranked = s.stats.RatingLeaderboard(gameType, team, profileIDs)
+	}
+
+	standings := make([]LeaderboardEntry, 0, len(ranked))
+	for _, standing := range ranked {
+		// Deleted profiles drop off the leaderboard
+		p, err := s.profiles.Get(standing.ProfileID)
+		if err != nil {
+			continue
+		}
+		standings = append(standings, LeaderboardEntry{
+			Standing:    standing,
+			DisplayName: p.DisplayName,
+			AvatarColor: p.AvatarColor,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(LeaderboardResponse{GameType: gameType, Sort: sortBy, Team: team, Standings: standings})
+}
@@ -0,0 +1,330 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/games/werewolf"
+	"github.com/KonradHerman/roundtable/internal/profile"
+	"github.com/KonradHerman/roundtable/internal/store"
+)
+
+// oneMoveGame is an Avalon stand-in that finishes on the first action: the
+// host wins as Merlin and everyone else loses as the assassin.
+type oneMoveGame struct {
+	hostID   string
+	players  []*core.Player
+	finished bool
+}
+
+type oneMoveConfig struct{}
+
+func (oneMoveConfig) GameType() string { return "avalon" }
+func (oneMoveConfig) Validate() error  { return nil }
+
+func (g *oneMoveGame) Initialize(config core.GameConfig, players []*core.Player) ([]core.GameEvent, error) {
+	g.players = players
+	return nil, nil
+}
+func (g *oneMoveGame) SetHost(hostID string)                               { g.hostID = hostID }
+func (g *oneMoveGame) ValidateAction(playerID string, a core.Action) error { return nil }
+func (g *oneMoveGame) ProcessAction(playerID string, a core.Action) ([]core.GameEvent, error) {
+	g.finished = true
+	event, _ := core.NewPublicEvent(core.EventGameFinished, "system", core.GameFinishedPayload{Results: g.GetResults()})
+	return []core.GameEvent{event}, nil
+}
+func (g *oneMoveGame) GetPlayerState(playerID string) core.PlayerState { return nil }
+func (g *oneMoveGame) GetPublicState() core.PublicState                { return nil }
+func (g *oneMoveGame) GetPhase() core.GamePhase                        { return core.GamePhase{} }
+func (g *oneMoveGame) IsFinished() bool                                { return g.finished }
+func (g *oneMoveGame) GetResults() core.GameResults {
+	return core.GameResults{Winners: []string{g.hostID}}
+}
+func (g *oneMoveGame) CheckPhaseTimeout() ([]core.GameEvent, error) { return nil, nil }
+
+func (g *oneMoveGame) PlayerOutcomes() []core.PlayerOutcome {
+	outcomes := make([]core.PlayerOutcome, 0, len(g.players))
+	for _, player := range g.players {
+		if player.ID == g.hostID {
+			outcomes = append(outcomes, core.PlayerOutcome{PlayerID: player.ID, Role: "merlin", Team: "good", Won: true})
+		} else {
+			outcomes = append(outcomes, core.PlayerOutcome{PlayerID: player.ID, Role: "assassin", Team: "evil"})
+		}
+	}
+	return outcomes
+}
+
+// playOneMoveGame plays a finished game in a room of players joined with the
+// given profile keys; an empty key joins without a profile.
+func playOneMoveGame(t *testing.T, s *Server, hostKey string, keys ...string) {
+	t.Helper()
+
+	code, _ := s.codes.Allocate()
+	host, _ := s.newPlayer(code, "Host", authenticate(t, s, hostKey))
+	room := core.NewRoom(code, "avalon", host, 10)
+	for i, key := range keys {
+		player, _ := s.newPlayer(room.ID, "Player"+string(rune('A'+i)), authenticate(t, s, key))
+		room.AddPlayer(player)
+	}
+	if err := room.StartGame(&oneMoveGame{}, oneMoveConfig{}); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	if err := s.connMgr.processAction(room, room.HostID, core.Action{Type: "finish"}); err != nil {
+		t.Fatalf("failed to process action: %v", err)
+	}
+}
+
+// authenticate returns the profile of a key, or nil for an empty key.
+func authenticate(t *testing.T, s *Server, key string) *profile.Profile {
+	t.Helper()
+
+	if key == "" {
+		return nil
+	}
+	p, err := s.profiles.Authenticate(key)
+	if err != nil {
+		t.Fatalf("failed to authenticate profile: %v", err)
+	}
+	return &p
+}
+
+func TestServer_RecordsProfileStats(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+	alice := createProfile(t, s, CreateProfileRequest{DisplayName: "Alice"})
+	bob := createProfile(t, s, CreateProfileRequest{DisplayName: "Bob"})
+
+	playOneMoveGame(t, s, alice.Key, bob.Key, "")
+	playOneMoveGame(t, s, bob.Key, alice.Key)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/profiles/"+alice.Profile.ID+"/stats", nil)
+	req.SetPathValue("id", alice.Profile.ID)
+	rec := httptest.NewRecorder()
+	s.HandleProfileStats(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp StatsResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	avalon := resp.Games["avalon"]
+	if avalon == nil || avalon.Played != 2 || avalon.Wins != 1 {
+		t.Fatalf("expected 1 of 2 games won, got %+v", avalon)
+	}
+	if avalon.Role("merlin").Wins != 1 || avalon.Teams["evil"].Played != 1 {
+		t.Errorf("unexpected role and team records: %+v", avalon)
+	}
+	if resp.DisplayName != "Alice" || resp.Highlights.MerlinSurvivalRate == nil || *resp.Highlights.MerlinSurvivalRate != 1 {
+		t.Errorf("unexpected profile details: %+v", resp)
+	}
+
+	// "me" resolves the caller's profile
+	req = httptest.NewRequest(http.MethodGet, "/api/profiles/me/stats", nil)
+	req.SetPathValue("id", "me")
+	req.Header.Set("X-Profile-Key", bob.Key)
+	rec = httptest.NewRecorder()
+	s.HandleProfileStats(rec, req)
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if resp.ProfileID != bob.Profile.ID {
+		t.Errorf("expected Bob's stats, got %s", resp.ProfileID)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/profiles/missing/stats", nil)
+	req.SetPathValue("id", "missing")
+	rec = httptest.NewRecorder()
+	s.HandleProfileStats(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown profile, got %d", rec.Code)
+	}
+}
+
+func TestHandleLeaderboard(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+	alice := createProfile(t, s, CreateProfileRequest{DisplayName: "Alice"})
+	bob := createProfile(t, s, CreateProfileRequest{DisplayName: "Bob"})
+	carol := createProfile(t, s, CreateProfileRequest{DisplayName: "Carol"})
+
+	playOneMoveGame(t, s, alice.Key, bob.Key)
+	playOneMoveGame(t, s, alice.Key, bob.Key)
+	playOneMoveGame(t, s, bob.Key, alice.Key)
+
+	leaderboard := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.HandleLeaderboard(rec, httptest.NewRequest(http.MethodGet, "/api/leaderboard?"+query, nil))
+		return rec
+	}
+
+	rec := leaderboard("game=avalon&profiles=" + bob.Profile.ID + "," + alice.Profile.ID + "," + carol.Profile.ID)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp LeaderboardResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Standings) != 2 {
+		t.Fatalf("expected Alice and Bob ranked, got %+v", resp.Standings)
+	}
+	if resp.Standings[0].DisplayName != "Alice" || resp.Standings[0].Wins != 2 || resp.Standings[1].DisplayName != "Bob" {
+		t.Errorf("expected Alice ahead of Bob, got %+v", resp.Standings)
+	}
+
+	for _, standing := range resp.Standings {
+		if standing.Rating == 0 || standing.Deviation >= 350 {
+			t.Errorf("expected rated standings, got %+v", standing)
+		}
+	}
+
+	rec = leaderboard("game=avalon&sort=rating&team=evil&profiles=" + bob.Profile.ID + "," + alice.Profile.ID)
+	resp = LeaderboardResponse{}
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if rec.Code != http.StatusOK || resp.Sort != "rating" || len(resp.Standings) != 2 {
+		t.Fatalf("expected evil ratings for Alice and Bob, got %d: %s", rec.Code, rec.Body.String())
+	}
+	first, second := resp.Standings[0], resp.Standings[1]
+	if first.Rating-2*first.Deviation < second.Rating-2*second.Deviation {
+		t.Errorf("expected standings ranked by conservative rating, got %+v", resp.Standings)
+	}
+	if first.Wins != 0 || second.Wins != 0 {
+		t.Errorf("expected evil records, got %+v", resp.Standings)
+	}
+
+	if rec := leaderboard("game=avalon&sort=elo&profiles=" + alice.Profile.ID); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown sort, got %d", rec.Code)
+	}
+	if rec := leaderboard("game=avalon&team=good&profiles=" + alice.Profile.ID); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a team without sort=rating, got %d", rec.Code)
+	}
+	if rec := leaderboard("game=chess&profiles=" + alice.Profile.ID); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown game, got %d", rec.Code)
+	}
+	if rec := leaderboard("game=avalon"); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without profiles, got %d", rec.Code)
+	}
+}
+
+func TestServer_RecordsWerewolfResults(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+	alice := createProfile(t, s, CreateProfileRequest{DisplayName: "Alice"})
+	bob := createProfile(t, s, CreateProfileRequest{DisplayName: "Bob"})
+
+	code, _ := s.codes.Allocate()
+	host, _ := s.newPlayer(code, "Alice", authenticate(t, s, alice.Key))
+	room := core.NewRoom(code, "werewolf", host, 10)
+	player, _ := s.newPlayer(room.ID, "Bob", authenticate(t, s, bob.Key))
+	room.AddPlayer(player)
+
+	config := &werewolf.Config{Roles: []werewolf.RoleType{
+		werewolf.RoleWerewolf, werewolf.RoleVillager, werewolf.RoleVillager, werewolf.RoleVillager, werewolf.RoleVillager,
+	}}
+	if err := room.StartGame(werewolf.NewGame(), config); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	// The host ends the day with advance_to_results once a vote is in,
+	// without waiting for the rest of the table
+	vote, _ := json.Marshal(werewolf.VotePayload{TargetID: player.ID})
+	actions := []struct {
+		playerID string
+		action   core.Action
+	}{
+		{host.ID, core.Action{Type: "acknowledge_role"}},
+		{player.ID, core.Action{Type: "acknowledge_role"}},
+		{host.ID, core.Action{Type: "advance_phase"}},
+		{host.ID, core.Action{Type: "vote", Payload: vote}},
+		{host.ID, core.Action{Type: "advance_to_results"}},
+	}
+	for _, a := range actions {
+		if err := s.connMgr.processAction(room, a.playerID, a.action); err != nil {
+			t.Fatalf("%s: failed to process action: %v", a.action.Type, err)
+		}
+	}
+
+	for _, id := range []string{alice.Profile.ID, bob.Profile.ID} {
+		if games := s.stats.Get(id).Games["werewolf"]; games == nil || games.Played != 1 {
+			t.Errorf("expected 1 werewolf game recorded for %s, got %+v", id, games)
+		}
+	}
+	if summary := room.SessionSummary(); len(summary.Rounds) != 1 {
+		t.Errorf("expected 1 scored round, got %+v", summary)
+	}
+}
+
+func TestServer_SkipsWerewolfResultsWithoutVotes(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+	alice := createProfile(t, s, CreateProfileRequest{DisplayName: "Alice"})
+	bob := createProfile(t, s, CreateProfileRequest{DisplayName: "Bob"})
+
+	code, _ := s.codes.Allocate()
+	host, _ := s.newPlayer(code, "Alice", authenticate(t, s, alice.Key))
+	room := core.NewRoom(code, "werewolf", host, 10)
+	player, _ := s.newPlayer(room.ID, "Bob", authenticate(t, s, bob.Key))
+	room.AddPlayer(player)
+
+	config := &werewolf.Config{Roles: []werewolf.RoleType{
+		werewolf.RoleWerewolf, werewolf.RoleVillager, werewolf.RoleVillager, werewolf.RoleVillager, werewolf.RoleVillager,
+	}}
+	if err := room.StartGame(werewolf.NewGame(), config); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	for _, a := range []struct{ playerID, action string }{
+		{host.ID, "acknowledge_role"},
+		{player.ID, "acknowledge_role"},
+		{host.ID, "advance_phase"},
+	} {
+		if err := s.connMgr.processAction(room, a.playerID, core.Action{Type: a.action}); err != nil {
+			t.Fatalf("%s: failed to process action: %v", a.action, err)
+		}
+	}
+
+	if err := s.connMgr.processAction(room, player.ID, core.Action{Type: "advance_to_results"}); err == nil {
+		t.Error("expected a non-host advance_to_results to be rejected")
+	}
+	if err := s.connMgr.processAction(room, host.ID, core.Action{Type: "advance_to_results"}); err != nil {
+		t.Fatalf("failed to advance to results: %v", err)
+	}
+
+	for _, id := range []string{alice.Profile.ID, bob.Profile.ID} {
+		if games := s.stats.Get(id).Games["werewolf"]; games != nil {
+			t.Errorf("expected no werewolf game recorded for %s, got %+v", id, games)
+		}
+	}
+	if summary := room.SessionSummary(); len(summary.Rounds) != 0 {
+		t.Errorf("expected no scored rounds, got %+v", summary)
+	}
+}
+
+func TestServer_SkipsStatsWithBots(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+	alice := createProfile(t, s, CreateProfileRequest{DisplayName: "Alice"})
+
+	code, _ := s.codes.Allocate()
+	host, _ := s.newPlayer(code, "Alice", authenticate(t, s, alice.Key))
+	room := core.NewRoom(code, "avalon", host, 10)
+	room.AddPlayer(core.NewBotPlayer("Robo", core.BotSettings{Strategy: "random"}))
+	if err := room.StartGame(&oneMoveGame{}, oneMoveConfig{}); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	if err := s.connMgr.processAction(room, room.HostID, core.Action{Type: "finish"}); err != nil {
+		t.Fatalf("failed to process action: %v", err)
+	}
+
+	if games := s.stats.Get(alice.Profile.ID).Games["avalon"]; games != nil {
+		t.Errorf("expected no stats for a game against bots, got %+v", games)
+	}
+	if summary := room.SessionSummary(); len(summary.Rounds) != 1 {
+		t.Errorf("expected the round still scored on the room scoreboard, got %+v", summary)
+	}
+}
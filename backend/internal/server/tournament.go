@@ -0,0 +1,317 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/profile"
+	"github.com/KonradHerman/roundtable/internal/tournament"
+)
+
+// SetTournaments replaces the server's in-memory tournament store, e.g. with
+// one persisted to the data directory. Call it before serving requests.
+func (s *Server) SetTournaments(tournaments *tournament.Store) {
+	s.tournaments = tournaments
+}
+
+// openTable creates the room for a tournament table. The first entrant hosts
+// and starts the game; everyone picks up their seat with
+// GET /api/tournaments/{id}/seat.
+func (s *Server) openTable(gameType string) tournament.OpenTable {
+	return func(number int, entrants []tournament.Entrant) (string, time.Time, []tournament.Seat, error) {
+		players := make([]*core.Player, 0, len(entrants))
+		var room *core.Room
+		for i, entrant := range entrants {
+			// A profile deleted since registering still plays under its ID,
+			// so the table's results can be recorded
+			p, err := s.profiles.Get(entrant.ProfileID)
+			if err != nil {
+				p = profile.Profile{ID: entrant.ProfileID, DisplayName: entrant.DisplayName}
+			}
+
+			if i == 0 {
+				host, hostPlayer, err := s.createRoom(CreateRoomRequest{
+					GameType:   gameType,
+					MaxPlayers: len(entrants),
+				}, entrant.DisplayName, &p)
+				if err != nil {
+					return "", time.Time{}, nil, err
+				}
+				room = host
+				players = append(players, hostPlayer)
+				continue
+			}
+
+			player, err := s.newPlayer(room.ID, entrant.DisplayName, &p)
+			if err != nil {
+				return "", time.Time{}, nil, err
+			}
+			if err := s.joinRoom(room, player); err != nil {
+				return "", time.Time{}, nil, err
+			}
+			players = append(players, player)
+		}
+
+		seats := make([]tournament.Seat, len(players))
+		for i, player := range players {
+			seats[i] = tournament.Seat{ProfileID: player.ProfileID, PlayerID: player.ID}
+		}
+		return room.ID, room.CreatedAt, seats, nil
+	}
+}
+
+// recordTournamentTable records a finished game in the tournament its room
+// was opened for, if any.
+func (s *Server) recordTournamentTable(room *core.Room) {
+	rules := s.scoringRules(room.GameType)
+	results := make(map[string]tournament.Result)
+	for _, outcome := range room.GameOutcomes() {
+		player, err := room.GetPlayer(outcome.PlayerID)
+		if err != nil || player.ProfileID == "" {
+			continue
+		}
+		results[player.ProfileID] = tournament.Result{Points: rules.Points(outcome), Won: outcome.Won}
+	}
+
+	t, recorded, err := s.tournaments.RecordTable(room.ID, room.CreatedAt, results)
+	if err != nil {
+		slog.Error("failed to record tournament table", "roomCode", room.ID, "error", err)
+	}
+	if recorded {
+		slog.Info("recorded tournament table", "tournamentID", t.ID, "roomCode", room.ID, "status", t.Status)
+	}
+}
+
+// tournamentError maps a tournament store error to a response status.
+func tournamentError(err error) int {
+	switch {
+	case errors.Is(err, tournament.ErrNotFound), errors.Is(err, tournament.ErrNotSeated),
+		errors.Is(err, tournament.ErrTableNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, tournament.ErrNotOrganizer):
+		return http.StatusForbidden
+	case errors.Is(err, tournament.ErrRegistrationClosed), errors.Is(err, tournament.ErrAlreadyRegistered),
+		errors.Is(err, tournament.ErrTournamentFull), errors.Is(err, tournament.ErrRoundInProgress),
+		errors.Is(err, tournament.ErrFinished), errors.Is(err, tournament.ErrNotEnoughPlayers),
+		errors.Is(err, tournament.ErrTableFinished):
+		return http.StatusConflict
+	case errors.Is(err, tournament.ErrInvalidOptions):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeTournamentError responds with a tournament store error.
+func writeTournamentError(w http.ResponseWriter, err error) {
+	status := tournamentError(err)
+	if status == http.StatusInternalServerError {
+		slog.Error("tournament request failed", "error", err)
+		http.Error(w, "Tournament request failed", status)
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// TournamentResponse is a tournament with its current standings.
+type TournamentResponse struct {
+	tournament.Tournament
+	Standings []tournament.Standing `json:"standings"`
+}
+
+// writeTournament responds with a tournament and its standings.
+func writeTournament(w http.ResponseWriter, t tournament.Tournament) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(TournamentResponse{Tournament: t, Standings: t.Standings()})
+}
+
+// HandleCreateTournament creates a tournament organized by the caller's
+// profile.
+// Expected format: POST /api/tournaments
+func (s *Server) HandleCreateTournament(w http.ResponseWriter, r *http.Request) {
+	organizer, ok := s.authenticatedProfile(w, r)
+	if !ok {
+		return
+	}
+
+	// Limit request body to 1MB
+	r.Body = http.MaxBytesReader(w, r.Body, 1*1024*1024)
+
+	var options tournament.Options
+	if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+		http.Error(w, "Request too large or malformed", http.StatusBadRequest)
+		return
+	}
+	if !s.gameRegistry.IsRegistered(options.GameType) {
+		http.Error(w, "Unknown game type", http.StatusBadRequest)
+		return
+	}
+
+	t, err := s.tournaments.Create(organizer.ID, options)
+	if err != nil {
+		writeTournamentError(w, err)
+		return
+	}
+
+	slog.Info("created tournament", "tournamentID", t.ID, "gameType", t.GameType, "pairing", t.Pairing)
+	writeTournament(w, t)
+}
+
+// HandleGetTournament returns a tournament's rounds and standings.
+// Expected format: GET /api/tournaments/{id}
+func (s *Server) HandleGetTournament(w http.ResponseWriter, r *http.Request) {
+	t, err := s.tournaments.Get(r.PathValue("id"))
+	if err != nil {
+		writeTournamentError(w, err)
+		return
+	}
+	writeTournament(w, t)
+}
+
+// HandleRegisterTournament enters the caller's profile into a tournament
+// before its first round.
+// Expected format: POST /api/tournaments/{id}/register
+func (s *Server) HandleRegisterTournament(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.authenticatedProfile(w, r)
+	if !ok {
+		return
+	}
+
+	t, err := s.tournaments.Register(r.PathValue("id"), tournament.Entrant{
+		ProfileID:   p.ID,
+		DisplayName: p.DisplayName,
+	})
+	if err != nil {
+		writeTournamentError(w, err)
+		return
+	}
+	writeTournament(w, t)
+}
+
+// HandleStartTournamentRound lets the organizer seat the entrants for the
+// next round, opening a room per table.
+// Expected format: POST /api/tournaments/{id}/rounds
+func (s *Server) HandleStartTournamentRound(w http.ResponseWriter, r *http.Request) {
+	organizer, ok := s.authenticatedProfile(w, r)
+	if !ok {
+		return
+	}
+
+	current, err := s.tournaments.Get(r.PathValue("id"))
+	if err != nil {
+		writeTournamentError(w, err)
+		return
+	}
+
+	t, err := s.tournaments.StartRound(current.ID, organizer.ID, s.openTable(current.GameType))
+	if err != nil {
+		writeTournamentError(w, err)
+		return
+	}
+
+	round := t.Rounds[len(t.Rounds)-1]
+	slog.Info("started tournament round", "tournamentID", t.ID, "round", round.Number, "tables", len(round.Tables))
+	writeTournament(w, t)
+}
+
+// HandleCloseTournamentTable lets the organizer finish a table of the current
+// round without a result, such as one whose room closed before its game
+// finished.
+// Expected format: POST /api/tournaments/{id}/tables/{table}/close
+func (s *Server) HandleCloseTournamentTable(w http.ResponseWriter, r *http.Request) {
+	organizer, ok := s.authenticatedProfile(w, r)
+	if !ok {
+		return
+	}
+
+	number, err := strconv.Atoi(r.PathValue("table"))
+	if err != nil {
+		http.Error(w, "Invalid table number", http.StatusBadRequest)
+		return
+	}
+
+	t, err := s.tournaments.CloseTable(r.PathValue("id"), organizer.ID, number)
+	if err != nil {
+		writeTournamentError(w, err)
+		return
+	}
+
+	slog.Info("closed tournament table", "tournamentID", t.ID, "table", number, "status", t.Status)
+	writeTournament(w, t)
+}
+
+// HandleFinishTournament lets the organizer end a tournament early with the
+// standings so far.
+// Expected format: POST /api/tournaments/{id}/finish
+func (s *Server) HandleFinishTournament(w http.ResponseWriter, r *http.Request) {
+	organizer, ok := s.authenticatedProfile(w, r)
+	if !ok {
+		return
+	}
+
+	t, err := s.tournaments.Finish(r.PathValue("id"), organizer.ID)
+	if err != nil {
+		writeTournamentError(w, err)
+		return
+	}
+
+	slog.Info("finished tournament", "tournamentID", t.ID, "rounds", len(t.Rounds))
+	writeTournament(w, t)
+}
+
+// TournamentSeatResponse is the caller's seat in the current round, with the
+// session to connect to its room as.
+type TournamentSeatResponse struct {
+	Round        int    `json:"round"`
+	Table        int    `json:"table"`
+	RoomCode     string `json:"roomCode"`
+	PlayerID     string `json:"playerId"`
+	SessionToken string `json:"sessionToken"`
+	Host         bool   `json:"host"` // Starts the game
+}
+
+// HandleTournamentSeat returns the caller's seat in the current round.
+// Expected format: GET /api/tournaments/{id}/seat
+func (s *Server) HandleTournamentSeat(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.authenticatedProfile(w, r)
+	if !ok {
+		return
+	}
+
+	t, err := s.tournaments.Get(r.PathValue("id"))
+	if err != nil {
+		writeTournamentError(w, err)
+		return
+	}
+	table, seat, err := s.tournaments.Seat(t.ID, p.ID)
+	if err != nil {
+		writeTournamentError(w, err)
+		return
+	}
+
+	room, err := s.store.GetRoom(table.RoomCode)
+	if err != nil {
+		http.Error(w, "Table room has closed", http.StatusGone)
+		return
+	}
+	player, err := room.GetPlayer(seat.PlayerID)
+	if err != nil {
+		http.Error(w, "Table room has closed", http.StatusGone)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(TournamentSeatResponse{
+		Round:        len(t.Rounds),
+		Table:        table.Number,
+		RoomCode:     room.ID,
+		PlayerID:     player.ID,
+		SessionToken: player.SessionToken,
+		Host:         room.IsHost(player.ID),
+	})
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/store"
+	"github.com/KonradHerman/roundtable/internal/tournament"
+)
+
+// tournamentRequest calls a tournament handler as a profile.
+func tournamentRequest(handler http.HandlerFunc, method, id, key string, body interface{}) (TournamentResponse, int) {
+	wrapped := func(w http.ResponseWriter, r *http.Request) {
+		r.SetPathValue("id", id)
+		handler(w, r)
+	}
+	rec := profileRequest(wrapped, method, key, body)
+
+	var resp TournamentResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	return resp, rec.Code
+}
+
+func TestServer_Tournament(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+	organizer := createProfile(t, s, CreateProfileRequest{DisplayName: "Organizer"})
+
+	options := tournament.Options{
+		Name:         "Game night cup",
+		GameType:     "avalon",
+		Pairing:      tournament.PairingSwiss,
+		Rounds:       1,
+		TableSize:    3,
+		MinTableSize: 3,
+	}
+	if _, code := tournamentRequest(s.HandleCreateTournament, http.MethodPost, "", "", options); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a profile, got %d", code)
+	}
+	unknown := options
+	unknown.GameType = "chess"
+	if _, code := tournamentRequest(s.HandleCreateTournament, http.MethodPost, "", organizer.Key, unknown); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown game, got %d", code)
+	}
+
+	created, code := tournamentRequest(s.HandleCreateTournament, http.MethodPost, "", organizer.Key, options)
+	if code != http.StatusOK || created.Status != tournament.StatusRegistration {
+		t.Fatalf("expected tournament created, got %d: %+v", code, created)
+	}
+
+	var keys []string
+	for _, name := range []string{"Alice", "Bob", "Carol", "Dave", "Erin", "Frank"} {
+		entrant := createProfile(t, s, CreateProfileRequest{DisplayName: name})
+		keys = append(keys, entrant.Key)
+		if _, code := tournamentRequest(s.HandleRegisterTournament, http.MethodPost, created.ID, entrant.Key, nil); code != http.StatusOK {
+			t.Fatalf("failed to register %s: %d", name, code)
+		}
+	}
+
+	if _, code := tournamentRequest(s.HandleStartTournamentRound, http.MethodPost, created.ID, keys[0], nil); code != http.StatusForbidden {
+		t.Errorf("expected 403 for a non-organizer, got %d", code)
+	}
+	started, code := tournamentRequest(s.HandleStartTournamentRound, http.MethodPost, created.ID, organizer.Key, nil)
+	if code != http.StatusOK || len(started.Rounds) != 1 || len(started.Rounds[0].Tables) != 2 {
+		t.Fatalf("expected a round at two tables, got %d: %+v", code, started)
+	}
+
+	// Every entrant finds their seat, and each table's host finishes its game
+	hosts := make(map[string]string)
+	for _, key := range keys {
+		rec := profileRequest(func(w http.ResponseWriter, r *http.Request) {
+			r.SetPathValue("id", created.ID)
+			s.HandleTournamentSeat(w, r)
+		}, http.MethodGet, key, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected a seat, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var seat TournamentSeatResponse
+		json.Unmarshal(rec.Body.Bytes(), &seat)
+		if seat.SessionToken == "" || seat.RoomCode == "" {
+			t.Errorf("incomplete seat: %+v", seat)
+		}
+		if seat.Host {
+			hosts[seat.RoomCode] = seat.PlayerID
+		}
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("expected a host per table, got %v", hosts)
+	}
+	for roomCode, hostID := range hosts {
+		room, err := s.store.GetRoom(roomCode)
+		if err != nil {
+			t.Fatalf("table room missing: %v", err)
+		}
+		if err := room.StartGame(&oneMoveGame{}, oneMoveConfig{}); err != nil {
+			t.Fatalf("failed to start game: %v", err)
+		}
+		if err := s.connMgr.processAction(room, hostID, core.Action{Type: "finish"}); err != nil {
+			t.Fatalf("failed to process action: %v", err)
+		}
+	}
+
+	final, code := tournamentRequest(s.HandleGetTournament, http.MethodGet, created.ID, "", nil)
+	if code != http.StatusOK || final.Status != tournament.StatusFinished {
+		t.Fatalf("expected the tournament finished, got %d: %+v", code, final)
+	}
+	if len(final.Standings) != 6 || final.Standings[0].Wins != 1 || final.Standings[1].Rank != 1 || final.Standings[2].Wins != 0 {
+		t.Errorf("expected both table hosts sharing first, got %+v", final.Standings)
+	}
+	if _, code := tournamentRequest(s.HandleStartTournamentRound, http.MethodPost, created.ID, organizer.Key, nil); code != http.StatusConflict {
+		t.Errorf("expected 409 after the last round, got %d", code)
+	}
+	if _, code := tournamentRequest(s.HandleGetTournament, http.MethodGet, "missing", "", nil); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown tournament, got %d", code)
+	}
+}
+
+func TestHandleCloseTournamentTable(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(store.NewMemoryStore())
+	organizer := createProfile(t, s, CreateProfileRequest{DisplayName: "Organizer"})
+	created, _ := tournamentRequest(s.HandleCreateTournament, http.MethodPost, "", organizer.Key, tournament.Options{
+		Name:         "Game night cup",
+		GameType:     "avalon",
+		Pairing:      tournament.PairingRandom,
+		Rounds:       1,
+		TableSize:    3,
+		MinTableSize: 3,
+	})
+	var keys []string
+	for _, name := range []string{"Alice", "Bob", "Carol"} {
+		entrant := createProfile(t, s, CreateProfileRequest{DisplayName: name})
+		keys = append(keys, entrant.Key)
+		tournamentRequest(s.HandleRegisterTournament, http.MethodPost, created.ID, entrant.Key, nil)
+	}
+	started, code := tournamentRequest(s.HandleStartTournamentRound, http.MethodPost, created.ID, organizer.Key, nil)
+	if code != http.StatusOK {
+		t.Fatalf("expected a round to start, got %d", code)
+	}
+
+	// The table's room is closed before anyone plays
+	s.store.DeleteRoom(started.Rounds[0].Tables[0].RoomCode)
+
+	closeTable := func(key, table string) (TournamentResponse, int) {
+		return tournamentRequest(func(w http.ResponseWriter, r *http.Request) {
+			r.SetPathValue("table", table)
+			s.HandleCloseTournamentTable(w, r)
+		}, http.MethodPost, created.ID, key, nil)
+	}
+
+	if _, code := closeTable(keys[0], "1"); code != http.StatusForbidden {
+		t.Errorf("expected 403 for a non-organizer, got %d", code)
+	}
+	if _, code := closeTable(organizer.Key, "one"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a malformed table number, got %d", code)
+	}
+	if _, code := closeTable(organizer.Key, "2"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown table, got %d", code)
+	}
+
+	closed, code := closeTable(organizer.Key, "1")
+	if code != http.StatusOK || !closed.Rounds[0].Tables[0].Closed {
+		t.Fatalf("expected the table closed, got %d: %+v", code, closed)
+	}
+	if closed.Status != tournament.StatusFinished {
+		t.Errorf("expected closing the last table to finish the tournament, got %s", closed.Status)
+	}
+	if _, code := closeTable(organizer.Key, "1"); code != http.StatusConflict {
+		t.Errorf("expected 409 once finished, got %d", code)
+	}
+}
@@ -5,13 +5,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sort"
 	"sync"
 	"time"
 
 	"nhooyr.io/websocket"
-	"nhooyr.io/websocket/wsjson"
 
+	"github.com/KonradHerman/roundtable/internal/auth"
+	"github.com/KonradHerman/roundtable/internal/bot"
 	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/metrics"
+	"github.com/KonradHerman/roundtable/internal/ratelimit"
 	"github.com/KonradHerman/roundtable/internal/store"
 )
 
@@ -20,24 +24,116 @@ type ConnectionManager struct {
 	store       store.Store
 	connections map[string]*Connection // playerID → Connection
 	mu          sync.RWMutex
+	options     ConnectionOptions
+	tokens      *auth.Issuer // Signs and verifies session tokens
+
+	minProtocolVersion int                // Oldest protocol version accepted from clients
+	actionLimiter      *ratelimit.Limiter // Per-session action limiter (nil = unlimited)
+	chatLimiter        *ratelimit.Limiter // Per-session chat limiter (nil = unlimited)
+	reactionLimiter    *ratelimit.Limiter // Per-session reaction limiter (nil = unlimited)
+	metrics            *metrics.Metrics   // Activity metrics (nil = disabled)
+	draining           bool               // Refusing new connections during shutdown
+	reconnectAfter     time.Duration      // Reconnect hint given to clients while draining
+	onGameFinished     func(*core.Room)   // Called when an action finishes a room's game (nil = ignored)
+	bots               *bot.Manager       // Woken by every event broadcast (nil = no bots)
 }
 
-// NewConnectionManager creates a new connection manager.
+// NewConnectionManager creates a new connection manager with default options.
 func NewConnectionManager(store store.Store) *ConnectionManager {
+	return NewConnectionManagerWithOptions(store, DefaultConnectionOptions())
+}
+
+// NewConnectionManagerWithOptions creates a new connection manager.
+func NewConnectionManagerWithOptions(store store.Store, options ConnectionOptions) *ConnectionManager {
 	return &ConnectionManager{
-		store:       store,
-		connections: make(map[string]*Connection),
+		store:              store,
+		connections:        make(map[string]*Connection),
+		options:            options.withDefaults(),
+		tokens:             newIssuer(nil, DefaultOptions().SessionTTL),
+		minProtocolVersion: MinProtocolVersion,
 	}
 }
 
-// Connection represents a single WebSocket connection.
+// SetMinProtocolVersion sets the oldest protocol version clients may negotiate.
+// Raising it ends the deprecation window for older versions.
+func (cm *ConnectionManager) SetMinProtocolVersion(version int) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.minProtocolVersion = version
+}
+
+// negotiate resolves the protocol version and capabilities for a client.
+func (cm *ConnectionManager) negotiate(requestedVersion int, requestedCapabilities []string) (int, []string, error) {
+	cm.mu.RLock()
+	minVersion := cm.minProtocolVersion
+	cm.mu.RUnlock()
+
+	version, err := negotiateProtocol(requestedVersion, minVersion)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return version, negotiateCapabilities(requestedCapabilities), nil
+}
+
+// Connection represents a single client connection.
+// WebSocket connections carry the underlying socket in Conn; SSE connections
+// leave it nil and are drained by the HTTP handler instead of writePump.
 type Connection struct {
 	PlayerID string
 	RoomCode string
-	Conn     *websocket.Conn
+	Conn     *websocket.Conn // nil for SSE connections
 	Send     chan ServerMessage
 	ctx      context.Context
 	cancel   context.CancelFunc
+
+	protocolVersion int             // Negotiated protocol version
+	capabilities    map[string]bool // Negotiated optional features
+	codec           Codec           // Negotiated wire codec
+}
+
+// Transport names the connection's transport ("websocket" or "sse").
+func (c *Connection) Transport() string {
+	if c.Conn == nil {
+		return "sse"
+	}
+	return "websocket"
+}
+
+// HasCapability reports whether the client negotiated the given capability.
+func (c *Connection) HasCapability(capability string) bool {
+	return c.capabilities[capability]
+}
+
+// newConnection creates a connection with negotiated protocol settings.
+func (cm *ConnectionManager) newConnection(ctx context.Context, player *core.Player, roomCode string, conn *websocket.Conn, codec Codec, protocolVersion int, capabilities []string) *Connection {
+	connCtx, cancel := context.WithCancel(ctx)
+
+	capabilitySet := make(map[string]bool, len(capabilities))
+	for _, capability := range capabilities {
+		capabilitySet[capability] = true
+	}
+
+	return &Connection{
+		PlayerID:        player.ID,
+		RoomCode:        roomCode,
+		Conn:            conn,
+		Send:            make(chan ServerMessage, cm.options.SendBuffer),
+		ctx:             connCtx,
+		cancel:          cancel,
+		protocolVersion: protocolVersion,
+		capabilities:    capabilitySet,
+		codec:           codec,
+	}
+}
+
+// readMessage reads and decodes one client message with the given codec.
+func readMessage(ctx context.Context, conn *websocket.Conn, codec Codec, msg *ClientMessage) error {
+	_, data, err := conn.Read(ctx)
+	if err != nil {
+		return err
+	}
+	return codec.Decode(data, msg)
 }
 
 // HandleConnection manages a WebSocket connection lifecycle.
@@ -46,13 +142,16 @@ func (cm *ConnectionManager) HandleConnection(ctx context.Context, conn *websock
 	// The message size limit is handled by the library's default (32KB)
 	// For larger messages, the library will automatically stream them
 
-	// Set 10-second timeout for auth message
-	authCtx, authCancel := context.WithTimeout(ctx, 10*time.Second)
+	// The codec was negotiated through the WebSocket subprotocol at accept time
+	codec := codecForSubprotocol(conn.Subprotocol())
+
+	// Client must authenticate within the auth timeout
+	authCtx, authCancel := context.WithTimeout(ctx, cm.options.AuthTimeout)
 	defer authCancel()
 
 	// First, client must authenticate with session token
 	var authMsg ClientMessage
-	if err := wsjson.Read(authCtx, conn, &authMsg); err != nil {
+	if err := readMessage(authCtx, conn, codec, &authMsg); err != nil {
 		slog.Error("failed to read auth message", "error", err)
 		conn.Close(websocket.StatusPolicyViolation, "authentication required")
 		return
@@ -79,61 +178,41 @@ func (cm *ConnectionManager) HandleConnection(ctx context.Context, conn *websock
 		return
 	}
 
-	player, err := room.GetPlayerByToken(authPayload.SessionToken)
+	player, err := cm.authenticatePlayer(room, authPayload.SessionToken)
 	if err != nil {
-		slog.Warn("invalid session token", "roomCode", roomCode)
-		conn.Close(websocket.StatusPolicyViolation, "invalid session token")
+		slog.Warn("rejected session token", "roomCode", roomCode, "error", err)
+		conn.Close(websocket.StatusPolicyViolation, sessionError(err))
 		return
 	}
 
-	// Mark player as connected
-	player.Reconnect()
-
-	// Create connection context
-	connCtx, cancel := context.WithCancel(ctx)
-	connection := &Connection{
-		PlayerID: player.ID,
-		RoomCode: roomCode,
-		Conn:     conn,
-		Send:     make(chan ServerMessage, 256),
-		ctx:      connCtx,
-		cancel:   cancel,
+	// Negotiate protocol version and capabilities
+	version, capabilities, err := cm.negotiate(authPayload.ProtocolVersion, authPayload.Capabilities)
+	if err != nil {
+		slog.Warn("protocol negotiation failed", "roomCode", roomCode, "requested", authPayload.ProtocolVersion, "error", err)
+		conn.Close(websocket.StatusPolicyViolation, "unsupported protocol version")
+		return
 	}
-
-	// Register connection
-	cm.mu.Lock()
-	// Close existing connection if player reconnecting
-	if existingConn, exists := cm.connections[player.ID]; exists {
-		existingConn.Close()
+	if version < CurrentProtocolVersion {
+		slog.Warn("client using deprecated protocol version",
+			"playerID", player.ID,
+			"version", version,
+			"current", CurrentProtocolVersion,
+		)
 	}
-	cm.connections[player.ID] = connection
-	cm.mu.Unlock()
-
-	slog.Info("player connected",
-		"playerName", player.DisplayName,
-		"playerID", player.ID,
-		"roomCode", roomCode,
-	)
 
-	// Send authenticated message with current state
-	authResponse, _ := NewAuthenticatedMessage(player.ID, room.GetState())
-	connection.Send <- authResponse
+	connection := cm.newConnection(ctx, player, roomCode, conn, codec, version, capabilities)
 
-	// Send event history for this player
-	events := room.GetEventsForPlayer(player.ID)
-	if len(events) > 0 {
-		eventsMsg, _ := NewEventsMessage(events)
-		connection.Send <- eventsMsg
+	// Hand out a fresh token to clients that can store it
+	var sessionToken string
+	if connection.HasCapability(CapabilityTokenRotation) {
+		sessionToken, err = cm.rotateToken(room, player, authPayload.SessionToken)
+		if err != nil {
+			slog.Error("failed to rotate session token", "playerID", player.ID, "error", err)
+		}
 	}
 
-	// Broadcast player reconnected event if game in progress
-	if room.Status == core.RoomStatusPlaying {
-		event, _ := core.NewPublicEvent(core.EventPlayerReconnected, "system", core.PlayerReconnectedPayload{
-			PlayerID: player.ID,
-		})
-		room.AppendEvent(event)
-		cm.BroadcastEvent(roomCode, event)
-	}
+	cm.register(connection, player)
+	cm.sendInitialState(connection, room, player, sessionToken)
 
 	// Start read and write pumps
 	go connection.writePump()
@@ -152,7 +231,7 @@ func (c *Connection) readPump(cm *ConnectionManager, room *core.Room) {
 		readCtx, readCancel := context.WithTimeout(c.ctx, 60*time.Second)
 
 		var msg ClientMessage
-		err := wsjson.Read(readCtx, c.Conn, &msg)
+		err := readMessage(readCtx, c.Conn, c.codec, &msg)
 		readCancel() // Always cancel to release resources
 
 		if err != nil {
@@ -177,12 +256,26 @@ func (c *Connection) writePump() {
 	for {
 		select {
 		case msg := <-c.Send:
-			ctx, cancel := context.WithTimeout(c.ctx, 10*time.Second)
-			err := wsjson.Write(ctx, c.Conn, msg)
-			cancel()
-
+			data, ok, err := msg.encode(c.protocolVersion, c.codec)
 			if err != nil {
-				slog.Error("write error", "playerID", c.PlayerID, "error", err)
+				slog.Error("encode error", "playerID", c.PlayerID, "type", msg.Type, "error", err)
+				continue
+			}
+			if ok {
+				ctx, cancel := context.WithTimeout(c.ctx, 10*time.Second)
+				err = c.Conn.Write(ctx, c.codec.MessageType(), data)
+				cancel()
+
+				if err != nil {
+					slog.Error("write error", "playerID", c.PlayerID, "error", err)
+					return
+				}
+			}
+
+			if msg.closeAfter != nil {
+				// Close performs the close handshake; readPump then sees the
+				// closed connection and ends the session.
+				c.Conn.Close(msg.closeAfter.status, msg.closeAfter.reason)
 				return
 			}
 
@@ -211,35 +304,176 @@ func (cm *ConnectionManager) handleClientMessage(conn *Connection, room *core.Ro
 		conn.Send <- pong
 
 	case ClientMsgAction:
+		if allowed, _ := cm.allowAction(conn.PlayerID); !allowed {
+			errMsg, _ := NewCodedErrorMessage(ErrorCodeRateLimited, "Too many actions, slow down")
+			conn.Send <- errMsg
+			return
+		}
+
 		var actionPayload ActionPayload
 		if err := json.Unmarshal(msg.Payload, &actionPayload); err != nil {
-			errMsg, _ := NewErrorMessage("Invalid action payload")
+			errMsg, _ := NewCodedErrorMessage(ErrorCodeInvalidPayload, "Invalid action payload")
 			conn.Send <- errMsg
 			return
 		}
 
-		// Process action
-		events, err := room.ProcessAction(conn.PlayerID, actionPayload.Action)
-		if err != nil {
-			errMsg, _ := NewErrorMessage(fmt.Sprintf("Action failed: %v", err))
+		if err := cm.processAction(room, conn.PlayerID, actionPayload.Action); err != nil {
+			errMsg, _ := NewCodedErrorMessage(ErrorCodeActionFailed, fmt.Sprintf("Action failed: %v", err))
 			conn.Send <- errMsg
 			return
 		}
 
-		// Broadcast events to affected players
-		for _, event := range events {
-			cm.BroadcastEvent(room.ID, event)
+	case ClientMsgChat:
+		if allowed, _ := cm.allowChat(conn.PlayerID); !allowed {
+			errMsg, _ := NewCodedErrorMessage(ErrorCodeRateLimited, "Too many chat messages, slow down")
+			conn.Send <- errMsg
+			return
+		}
+
+		var chatMsg core.ChatMessage
+		if err := json.Unmarshal(msg.Payload, &chatMsg); err != nil {
+			errMsg, _ := NewCodedErrorMessage(ErrorCodeInvalidPayload, "Invalid chat payload")
+			conn.Send <- errMsg
+			return
+		}
+
+		if err := cm.processChat(room, conn.PlayerID, chatMsg); err != nil {
+			errMsg, _ := NewCodedErrorMessage(ErrorCodeChatFailed, fmt.Sprintf("Chat failed: %v", err))
+			conn.Send <- errMsg
+			return
+		}
+
+	case ClientMsgReact:
+		if allowed, _ := cm.allowReaction(conn.PlayerID); !allowed {
+			errMsg, _ := NewCodedErrorMessage(ErrorCodeRateLimited, "Too many reactions, slow down")
+			conn.Send <- errMsg
+			return
+		}
+
+		var reaction core.Reaction
+		if err := json.Unmarshal(msg.Payload, &reaction); err != nil {
+			errMsg, _ := NewCodedErrorMessage(ErrorCodeInvalidPayload, "Invalid reaction payload")
+			conn.Send <- errMsg
+			return
+		}
+
+		if err := cm.processReaction(room, conn.PlayerID, reaction); err != nil {
+			errMsg, _ := NewCodedErrorMessage(ErrorCodeReactionFailed, fmt.Sprintf("Reaction failed: %v", err))
+			conn.Send <- errMsg
+			return
 		}
 
 	default:
-		errMsg, _ := NewErrorMessage(fmt.Sprintf("Unknown message type: %s", msg.Type))
+		errMsg, _ := NewCodedErrorMessage(ErrorCodeUnknownMessage, fmt.Sprintf("Unknown message type: %s", msg.Type))
 		conn.Send <- errMsg
 	}
 }
 
+// register marks the player as connected and stores the connection,
+// closing any previous connection the player still had open.
+func (cm *ConnectionManager) register(conn *Connection, player *core.Player) {
+	player.Reconnect()
+
+	cm.mu.Lock()
+	if existingConn, exists := cm.connections[player.ID]; exists {
+		existingConn.Close()
+	}
+	cm.connections[player.ID] = conn
+	if cm.draining {
+		// Connected while Drain was closing the others
+		if msg, err := NewServerRestartingMessage(cm.reconnectAfter); err == nil {
+			conn.sendAndClose(msg, websocket.StatusServiceRestart, "server restarting")
+		}
+	}
+	cm.mu.Unlock()
+
+	slog.Info("player connected",
+		"playerName", player.DisplayName,
+		"playerID", player.ID,
+		"roomCode", conn.RoomCode,
+	)
+}
+
+// sendInitialState queues the authenticated message and the player's event
+// and chat history, then announces the reconnect if a game is in progress.
+// sessionToken is the rotated token, if any.
+func (cm *ConnectionManager) sendInitialState(conn *Connection, room *core.Room, player *core.Player, sessionToken string) {
+	capabilities := make([]string, 0, len(conn.capabilities))
+	for capability := range conn.capabilities {
+		capabilities = append(capabilities, capability)
+	}
+	sort.Strings(capabilities)
+
+	authResponse, _ := NewAuthenticatedMessage(player.ID, room.GetState(), conn.protocolVersion, capabilities, sessionToken)
+	conn.Send <- authResponse
+
+	events := room.GetEventsForPlayer(player.ID)
+	if len(events) > 0 {
+		eventsMsg, _ := NewEventsMessage(events)
+		conn.Send <- eventsMsg
+	}
+	cm.sendChatHistory(conn, room)
+
+	if room.Status == core.RoomStatusPlaying {
+		event, _ := core.NewPublicEvent(core.EventPlayerReconnected, "system", core.PlayerReconnectedPayload{
+			PlayerID: player.ID,
+		})
+		room.AppendEvent(event)
+		cm.BroadcastEvent(room.ID, event)
+	}
+}
+
+// allowAction applies the per-session action limiter.
+func (cm *ConnectionManager) allowAction(playerID string) (bool, time.Duration) {
+	return allowPlayer(cm.actionLimiter, ScopeActions, playerID)
+}
+
+// allowPlayer applies a per-session limiter; a nil limiter allows everything.
+func allowPlayer(limiter *ratelimit.Limiter, scope RateLimitScope, playerID string) (bool, time.Duration) {
+	if limiter == nil {
+		return true, 0
+	}
+
+	allowed, retryAfter := limiter.Allow(playerID)
+	if !allowed {
+		slog.Warn("rate limited", "scope", scope, "playerID", playerID)
+	}
+	return allowed, retryAfter
+}
+
+// processAction runs an action through the room and broadcasts the resulting
+// events. It is shared by the WebSocket and HTTP action transports.
+func (cm *ConnectionManager) processAction(room *core.Room, playerID string, action core.Action) error {
+	events, err := room.ProcessAction(playerID, action)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		cm.BroadcastEvent(room.ID, event)
+	}
+
+	if cm.onGameFinished != nil {
+		for _, event := range events {
+			if event.Type == core.EventGameFinished {
+				cm.onGameFinished(room)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
 // handleDisconnect cleans up after a connection closes.
 func (cm *ConnectionManager) handleDisconnect(conn *Connection, room *core.Room) {
 	cm.mu.Lock()
+	// Only remove the entry if it still points at this connection; a newer
+	// connection for the same player may already have replaced it.
+	if current, exists := cm.connections[conn.PlayerID]; !exists || current != conn {
+		cm.mu.Unlock()
+		return
+	}
 	delete(cm.connections, conn.PlayerID)
 	cm.mu.Unlock()
 
@@ -255,10 +489,127 @@ func (cm *ConnectionManager) handleDisconnect(conn *Connection, room *core.Room)
 	}
 }
 
+// closeRequest asks a pump to close its connection after writing a message.
+type closeRequest struct {
+	status websocket.StatusCode
+	reason string
+}
+
+// sendAndClose queues a final message and closes the connection once it has
+// been written. If the send buffer is full the connection is closed at once.
+func (c *Connection) sendAndClose(msg ServerMessage, status websocket.StatusCode, reason string) {
+	msg.closeAfter = &closeRequest{status: status, reason: reason}
+
+	select {
+	case c.Send <- msg:
+	default:
+		c.Close()
+	}
+}
+
 // Close closes the connection.
 func (c *Connection) Close() {
 	c.cancel()
-	c.Conn.Close(websocket.StatusNormalClosure, "connection closed")
+	if c.Conn != nil {
+		c.Conn.Close(websocket.StatusNormalClosure, "connection closed")
+	}
+}
+
+// BroadcastMessage sends a message to every connection in a room, or to every
+// connection on the server when roomCode is empty. It returns the number of
+// connections the message was queued for.
+func (cm *ConnectionManager) BroadcastMessage(roomCode string, msg ServerMessage) int {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	sent := 0
+	for _, conn := range cm.connections {
+		if roomCode != "" && conn.RoomCode != roomCode {
+			continue
+		}
+
+		select {
+		case conn.Send <- msg:
+			sent++
+		default:
+			slog.Warn("failed to send message", "playerID", conn.PlayerID, "type", msg.Type, "reason", "channel full")
+			cm.metrics.BroadcastDropped(msg.Type)
+		}
+	}
+	return sent
+}
+
+// CloseRoom sends a final message to every connection in a room and then
+// closes them.
+func (cm *ConnectionManager) CloseRoom(roomCode string, msg ServerMessage, reason string) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	for _, conn := range cm.connections {
+		if conn.RoomCode == roomCode {
+			conn.sendAndClose(msg, websocket.StatusNormalClosure, reason)
+		}
+	}
+}
+
+// drainPollInterval is how often Drain checks for remaining connections.
+const drainPollInterval = 50 * time.Millisecond
+
+// Drain tells every client the server is restarting and closes their
+// connections, then waits until all of them are gone or ctx expires.
+// New connections are refused from then on.
+func (cm *ConnectionManager) Drain(ctx context.Context, reconnectAfter time.Duration) error {
+	msg, err := NewServerRestartingMessage(reconnectAfter)
+	if err != nil {
+		return err
+	}
+
+	cm.mu.Lock()
+	cm.draining = true
+	cm.reconnectAfter = reconnectAfter
+	for _, conn := range cm.connections {
+		conn.sendAndClose(msg, websocket.StatusServiceRestart, "server restarting")
+	}
+	cm.mu.Unlock()
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		cm.mu.RLock()
+		remaining := len(cm.connections)
+		cm.mu.RUnlock()
+
+		if remaining == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d connections still open: %w", remaining, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Draining reports whether the manager is shutting down, and how long
+// clients were told to wait before reconnecting.
+func (cm *ConnectionManager) Draining() (bool, time.Duration) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return cm.draining, cm.reconnectAfter
+}
+
+// ConnectionCounts returns the number of open connections per transport.
+func (cm *ConnectionManager) ConnectionCounts() map[string]int {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, conn := range cm.connections {
+		counts[conn.Transport()]++
+	}
+	return counts
 }
 
 // BroadcastEvent sends an event to all players who can see it.
@@ -288,8 +639,12 @@ func (cm *ConnectionManager) BroadcastEvent(roomCode string, event core.GameEven
 		case conn.Send <- eventMsg:
 		default:
 			slog.Warn("failed to send event", "playerID", player.ID, "reason", "channel full")
+			cm.metrics.BroadcastDropped(ServerMsgEvent)
 		}
 	}
+
+	// Bots read the event log themselves, so one wake-up covers it
+	cm.bots.Notify(room)
 }
 
 // BroadcastRoomState sends updated room state to all connected players.
@@ -315,6 +670,7 @@ func (cm *ConnectionManager) BroadcastRoomState(roomCode string) {
 		case conn.Send <- stateMsg:
 		default:
 			slog.Warn("failed to send room state", "playerID", player.ID, "reason", "channel full")
+			cm.metrics.BroadcastDropped(ServerMsgRoomState)
 		}
 	}
 }
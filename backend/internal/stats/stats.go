@@ -0,0 +1,454 @@
+// Package stats keeps per-profile game statistics: games played and won per
+// game type, team and role, and skill ratings per game type and team.
+package stats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/rating"
+	"github.com/KonradHerman/roundtable/internal/util"
+)
+
+// Record counts games played, won and lost by elimination.
+type Record struct {
+	Played     int `json:"played"`
+	Wins       int `json:"wins"`
+	Eliminated int `json:"eliminated,omitempty"`
+}
+
+// WinRate returns the share of games won, or 0 if none were played.
+func (r Record) WinRate() float64 {
+	if r.Played == 0 {
+		return 0
+	}
+	return float64(r.Wins) / float64(r.Played)
+}
+
+// add counts one game.
+func (r *Record) add(outcome core.PlayerOutcome) {
+	r.Played++
+	if outcome.Won {
+		r.Wins++
+	}
+	if outcome.Eliminated {
+		r.Eliminated++
+	}
+}
+
+// GameStats are a profile's statistics for one game type.
+type GameStats struct {
+	Record
+	Teams map[string]*Record `json:"teams,omitempty"` // Team → record
+	Roles map[string]*Record `json:"roles,omitempty"` // Role → record
+
+	Rating      *rating.Rating            `json:"rating,omitempty"`      // Nil until a rated game
+	TeamRatings map[string]*rating.Rating `json:"teamRatings,omitempty"` // Team → rating playing that side
+}
+
+// Role returns the record for a role, empty if it was never played.
+func (g *GameStats) Role(role string) Record {
+	if record, ok := g.Roles[role]; ok {
+		return *record
+	}
+	return Record{}
+}
+
+// add counts one game.
+func (g *GameStats) add(outcome core.PlayerOutcome) {
+	g.Record.add(outcome)
+	if outcome.Team != "" {
+		addTo(g.Teams, outcome.Team, outcome)
+	}
+	if outcome.Role != "" {
+		addTo(g.Roles, outcome.Role, outcome)
+	}
+}
+
+// addTo counts one game in the record under key.
+func addTo(records map[string]*Record, key string, outcome core.PlayerOutcome) {
+	record, ok := records[key]
+	if !ok {
+		record = &Record{}
+		records[key] = record
+	}
+	record.add(outcome)
+}
+
+// clone returns a deep copy.
+func (g *GameStats) clone() *GameStats {
+	c := &GameStats{
+		Record: g.Record,
+		Teams:  make(map[string]*Record, len(g.Teams)),
+		Roles:  make(map[string]*Record, len(g.Roles)),
+	}
+	for team, record := range g.Teams {
+		r := *record
+		c.Teams[team] = &r
+	}
+	for role, record := range g.Roles {
+		r := *record
+		c.Roles[role] = &r
+	}
+	if g.Rating != nil {
+		r := *g.Rating
+		c.Rating = &r
+	}
+	if len(g.TeamRatings) > 0 {
+		c.TeamRatings = make(map[string]*rating.Rating, len(g.TeamRatings))
+		for team, teamRating := range g.TeamRatings {
+			r := *teamRating
+			c.TeamRatings[team] = &r
+		}
+	}
+	return c
+}
+
+// currentRating returns the profile's rating, on one team if team is not
+// empty, or a new rating if it has none.
+func (g *GameStats) currentRating(team string) rating.Rating {
+	r := g.Rating
+	if team != "" {
+		r = g.TeamRatings[team]
+	}
+	if r == nil {
+		return rating.New()
+	}
+	return *r
+}
+
+// setRating stores the profile's rating, on one team if team is not empty.
+func (g *GameStats) setRating(team string, r rating.Rating) {
+	if team == "" {
+		g.Rating = &r
+		return
+	}
+	if g.TeamRatings == nil {
+		g.TeamRatings = make(map[string]*rating.Rating)
+	}
+	g.TeamRatings[team] = &r
+}
+
+// Stats are a profile's statistics across game types.
+type Stats struct {
+	ProfileID string                `json:"profileId"`
+	Games     map[string]*GameStats `json:"games"` // Game type → stats
+	UpdatedAt time.Time             `json:"updatedAt,omitempty"`
+}
+
+// clone returns a deep copy.
+func (s *Stats) clone() Stats {
+	c := Stats{
+		ProfileID: s.ProfileID,
+		Games:     make(map[string]*GameStats, len(s.Games)),
+		UpdatedAt: s.UpdatedAt,
+	}
+	for gameType, games := range s.Games {
+		c.Games[gameType] = games.clone()
+	}
+	return c
+}
+
+// Standing is a profile's place on a leaderboard.
+type Standing struct {
+	ProfileID string  `json:"profileId"`
+	Played    int     `json:"played"`
+	Wins      int     `json:"wins"`
+	WinRate   float64 `json:"winRate"`
+	Rating    float64 `json:"rating,omitempty"` // Zero if unrated
+	Deviation float64 `json:"deviation,omitempty"`
+}
+
+// RatedPlayer is a player's result in a game, for rating.
+type RatedPlayer struct {
+	ProfileID string // Empty for a player without a profile
+	Team      string
+	Won       bool
+}
+
+// Store holds statistics in memory, optionally persisted to a JSON file that
+// is rewritten after every recorded game. It is safe for concurrent use.
+type Store struct {
+	mu    sync.Mutex
+	stats map[string]*Stats // Profile ID → stats
+	path  string            // Empty for an in-memory store
+	now   func() time.Time  // Replaced in tests
+}
+
+// NewStore creates an in-memory statistics store.
+func NewStore() *Store {
+	return &Store{
+		stats: make(map[string]*Stats),
+		now:   time.Now,
+	}
+}
+
+// OpenStore creates a statistics store persisted to path, loading the
+// statistics saved there if the file exists.
+func OpenStore(path string) (*Store, error) {
+	s := NewStore()
+	s.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []*Stats
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("stats %s: %w", path, err)
+	}
+	for _, stats := range saved {
+		s.stats[stats.ProfileID] = stats
+	}
+	return s, nil
+}
+
+// RecordGame counts a finished game for each profile, given as profile ID →
+// that profile's outcome.
+func (s *Store) RecordGame(gameType string, outcomes map[string]core.PlayerOutcome) error {
+	if len(outcomes) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for profileID, outcome := range outcomes {
+		s.gameStats(profileID, gameType).add(outcome)
+		s.stats[profileID].UpdatedAt = now
+	}
+
+	return s.save()
+}
+
+// RateGame updates the ratings of every profile in a finished game, overall
+// and for the team it played. Players without a profile count as opponents
+// with a new rating, but their ratings are not kept.
+func (s *Store) RateGame(gameType string, players []RatedPlayer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rated := false
+	for _, player := range players {
+		if player.ProfileID != "" {
+			rated = true
+			break
+		}
+	}
+	if !rated {
+		return nil
+	}
+
+	// Each rating is looked up and updated in the same order as players
+	games := make([]*GameStats, len(players))
+	for i, player := range players {
+		if player.ProfileID != "" {
+			games[i] = s.gameStats(player.ProfileID, gameType)
+		} else {
+			games[i] = &GameStats{}
+		}
+	}
+
+	overall := make([]rating.Participant, len(players))
+	for i, player := range players {
+		overall[i] = rating.Participant{Rating: games[i].currentRating(""), Won: player.Won}
+	}
+
+	// Team ratings only make sense if every player was on a team
+	var byTeam []rating.Participant
+	for i, player := range players {
+		if player.Team == "" {
+			byTeam = nil
+			break
+		}
+		byTeam = append(byTeam, rating.Participant{Rating: games[i].currentRating(player.Team), Won: player.Won})
+	}
+
+	now := s.now()
+	updated := rating.UpdateGame(overall)
+	var updatedByTeam []rating.Rating
+	if byTeam != nil {
+		updatedByTeam = rating.UpdateGame(byTeam)
+	}
+	for i, player := range players {
+		if player.ProfileID == "" {
+			continue
+		}
+		games[i].setRating("", updated[i])
+		if updatedByTeam != nil {
+			games[i].setRating(player.Team, updatedByTeam[i])
+		}
+		s.stats[player.ProfileID].UpdatedAt = now
+	}
+
+	return s.save()
+}
+
+// gameStats returns a profile's statistics for a game type, creating them if
+// needed. The caller holds s.mu.
+func (s *Store) gameStats(profileID, gameType string) *GameStats {
+	stats, ok := s.stats[profileID]
+	if !ok {
+		stats = &Stats{ProfileID: profileID, Games: make(map[string]*GameStats)}
+		s.stats[profileID] = stats
+	}
+
+	games, ok := stats.Games[gameType]
+	if !ok {
+		games = &GameStats{Teams: make(map[string]*Record), Roles: make(map[string]*Record)}
+		stats.Games[gameType] = games
+	}
+	return games
+}
+
+// Get returns a profile's statistics, empty if it has not finished a game.
+func (s *Store) Get(profileID string) Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats, ok := s.stats[profileID]
+	if !ok {
+		return Stats{ProfileID: profileID, Games: map[string]*GameStats{}}
+	}
+	return stats.clone()
+}
+
+// Leaderboard ranks profiles by their record in a game type: by win rate,
+// then wins, then games played. Profiles that never played it are left out.
+func (s *Store) Leaderboard(gameType string, profileIDs []string) []Standing {
+	standings := s.standings(gameType, "", profileIDs)
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.WinRate != b.WinRate {
+			return a.WinRate > b.WinRate
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		if a.Played != b.Played {
+			return a.Played > b.Played
+		}
+		return a.ProfileID < b.ProfileID
+	})
+	return standings
+}
+
+// RatingLeaderboard ranks profiles by their rating in a game type, on one
+// team if team is not empty. Ratings are ranked two deviations below the
+// estimate, so a lucky streak over a few games does not top the board.
+// Profiles never rated there are left out.
+func (s *Store) RatingLeaderboard(gameType, team string, profileIDs []string) []Standing {
+	standings := s.standings(gameType, team, profileIDs)
+	kept := standings[:0]
+	for _, standing := range standings {
+		if standing.Deviation > 0 {
+			kept = append(kept, standing)
+		}
+	}
+	standings = kept
+
+	sort.Slice(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		ca := rating.Rating{Rating: a.Rating, Deviation: a.Deviation}.Conservative()
+		cb := rating.Rating{Rating: b.Rating, Deviation: b.Deviation}.Conservative()
+		if ca != cb {
+			return ca > cb
+		}
+		if a.Played != b.Played {
+			return a.Played > b.Played
+		}
+		return a.ProfileID < b.ProfileID
+	})
+	return standings
+}
+
+// standings returns the unsorted standings of profiles that played a game
+// type, on one team if team is not empty. Standings of unrated profiles have
+// a zero rating and deviation.
+func (s *Store) standings(gameType, team string, profileIDs []string) []Standing {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	standings := make([]Standing, 0, len(profileIDs))
+	seen := make(map[string]bool, len(profileIDs))
+	for _, id := range profileIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		stats, ok := s.stats[id]
+		if !ok {
+			continue
+		}
+		games, ok := stats.Games[gameType]
+		if !ok {
+			continue
+		}
+
+		record, r := games.Record, games.Rating
+		if team != "" {
+			record, r = Record{}, games.TeamRatings[team]
+			if teamRecord, ok := games.Teams[team]; ok {
+				record = *teamRecord
+			}
+		}
+		if record.Played == 0 {
+			continue
+		}
+
+		standing := Standing{
+			ProfileID: id,
+			Played:    record.Played,
+			Wins:      record.Wins,
+			WinRate:   record.WinRate(),
+		}
+		if r != nil {
+			standing.Rating = r.Rating
+			standing.Deviation = r.Deviation
+		}
+		standings = append(standings, standing)
+	}
+	return standings
+}
+
+// Delete removes a profile's statistics.
+func (s *Store) Delete(profileID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.stats[profileID]; !ok {
+		return nil
+	}
+	delete(s.stats, profileID)
+	return s.save()
+}
+
+// save writes all statistics to the store's file. Changes stay in memory
+// even if writing fails. The caller holds s.mu.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	saved := make([]*Stats, 0, len(s.stats))
+	for _, stats := range s.stats {
+		saved = append(saved, stats)
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(s.path, data)
+}
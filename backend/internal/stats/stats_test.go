@@ -0,0 +1,192 @@
+package stats
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+func TestStore_RecordGame(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	s.RecordGame("avalon", map[string]core.PlayerOutcome{
+		"alice": {Role: "merlin", Team: "good", Won: true},
+		"bob":   {Role: "assassin", Team: "evil"},
+	})
+	s.RecordGame("avalon", map[string]core.PlayerOutcome{
+		"alice": {Role: "merlin", Team: "good", Eliminated: true},
+	})
+	s.RecordGame("werewolf", map[string]core.PlayerOutcome{
+		"alice": {Role: "werewolf", Team: "werewolf", Eliminated: true},
+	})
+
+	alice := s.Get("alice")
+	avalon := alice.Games["avalon"]
+	if avalon.Played != 2 || avalon.Wins != 1 {
+		t.Errorf("expected 1 of 2 avalon games won, got %+v", avalon.Record)
+	}
+	if merlin := avalon.Role("merlin"); merlin.Played != 2 || merlin.Eliminated != 1 {
+		t.Errorf("expected Merlin assassinated once in 2 games, got %+v", merlin)
+	}
+	if good := avalon.Teams["good"]; good.WinRate() != 0.5 {
+		t.Errorf("expected good win rate 0.5, got %v", good.WinRate())
+	}
+	if werewolf := alice.Games["werewolf"].Role("werewolf"); werewolf.Eliminated != 1 {
+		t.Errorf("expected eliminated once as werewolf, got %+v", werewolf)
+	}
+
+	// Returned stats are copies
+	avalon.Played = 100
+	if s.Get("alice").Games["avalon"].Played != 2 {
+		t.Error("expected stored stats unchanged by callers")
+	}
+
+	if empty := s.Get("nobody"); len(empty.Games) != 0 || empty.ProfileID != "nobody" {
+		t.Errorf("expected empty stats, got %+v", empty)
+	}
+}
+
+func TestStore_Leaderboard(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	record := func(profileID string, wins, losses int) {
+		for i := 0; i < wins; i++ {
+			s.RecordGame("avalon", map[string]core.PlayerOutcome{profileID: {Won: true}})
+		}
+		for i := 0; i < losses; i++ {
+			s.RecordGame("avalon", map[string]core.PlayerOutcome{profileID: {}})
+		}
+	}
+	record("alice", 3, 1) // 75%
+	record("bob", 1, 1)   // 50%
+	record("carol", 2, 2) // 50%, more wins than bob
+	record("dave", 5, 0)  // Not in the group
+	s.RecordGame("werewolf", map[string]core.PlayerOutcome{"erin": {Won: true}})
+
+	standings := s.Leaderboard("avalon", []string{"bob", "carol", "alice", "erin", "alice"})
+
+	want := []string{"alice", "carol", "bob"}
+	if len(standings) != len(want) {
+		t.Fatalf("expected %d standings, got %+v", len(want), standings)
+	}
+	for i, id := range want {
+		if standings[i].ProfileID != id {
+			t.Errorf("standing %d = %s, want %s", i, standings[i].ProfileID, id)
+		}
+	}
+	if standings[0].WinRate != 0.75 || standings[0].Played != 4 {
+		t.Errorf("unexpected standing: %+v", standings[0])
+	}
+}
+
+func TestOpenStore_Persists(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "stats.json")
+	s, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	s.RecordGame("avalon", map[string]core.PlayerOutcome{"alice": {Role: "merlin", Team: "good", Won: true}})
+
+	reopened, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	if merlin := reopened.Get("alice").Games["avalon"].Role("merlin"); merlin.Wins != 1 {
+		t.Errorf("expected stats to persist, got %+v", merlin)
+	}
+
+	reopened.Delete("alice")
+	if again, _ := OpenStore(path); len(again.Get("alice").Games) != 0 {
+		t.Error("expected deleted stats to stay deleted")
+	}
+}
+
+func TestStore_RateGame(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	s.RecordGame("avalon", map[string]core.PlayerOutcome{"alice": {Team: "good", Won: true}, "bob": {Team: "evil"}})
+	s.RateGame("avalon", []RatedPlayer{
+		{ProfileID: "alice", Team: "good", Won: true},
+		{ProfileID: "bob", Team: "evil"},
+		{Team: "evil"}, // No profile
+	})
+
+	alice := s.Get("alice").Games["avalon"]
+	if alice.Rating == nil || alice.Rating.Rating <= 1500 || alice.Rating.Games != 1 {
+		t.Fatalf("expected Alice's rating to rise, got %+v", alice.Rating)
+	}
+	if good := alice.TeamRatings["good"]; good == nil || good.Rating <= 1500 {
+		t.Errorf("expected Alice's good rating to rise, got %+v", good)
+	}
+	if evil := alice.TeamRatings["evil"]; evil != nil {
+		t.Errorf("expected no evil rating for Alice, got %+v", evil)
+	}
+	if bob := s.Get("bob").Games["avalon"]; bob.Rating == nil || bob.Rating.Rating >= 1500 {
+		t.Errorf("expected Bob's rating to fall, got %+v", bob.Rating)
+	}
+
+	// Ratings are copied like the rest of the stats
+	alice.Rating.Rating = 0
+	if s.Get("alice").Games["avalon"].Rating.Rating == 0 {
+		t.Error("expected stored rating unchanged by callers")
+	}
+
+	// Games without profiles are not rated
+	s.RateGame("avalon", []RatedPlayer{{Won: true}, {}})
+	if len(s.stats) != 2 {
+		t.Errorf("expected only Alice and Bob stored, got %d profiles", len(s.stats))
+	}
+
+	// Team ratings need every player's team
+	s.RateGame("werewolf", []RatedPlayer{{ProfileID: "alice", Won: true}, {ProfileID: "bob", Team: "werewolf"}})
+	if werewolf := s.Get("bob").Games["werewolf"]; werewolf.Rating == nil || len(werewolf.TeamRatings) != 0 {
+		t.Errorf("expected only an overall werewolf rating, got %+v", werewolf)
+	}
+}
+
+func TestStore_RatingLeaderboard(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	play := func(winner, loser string) {
+		s.RecordGame("avalon", map[string]core.PlayerOutcome{
+			winner: {Team: "good", Won: true},
+			loser:  {Team: "evil"},
+		})
+		s.RateGame("avalon", []RatedPlayer{
+			{ProfileID: winner, Team: "good", Won: true},
+			{ProfileID: loser, Team: "evil"},
+		})
+	}
+	for i := 0; i < 5; i++ {
+		play("alice", "bob")
+	}
+	play("carol", "alice")
+	s.RecordGame("avalon", map[string]core.PlayerOutcome{"dave": {Team: "good", Won: true}}) // Played before ratings
+
+	standings := s.RatingLeaderboard("avalon", "", []string{"bob", "carol", "alice", "dave"})
+	want := []string{"alice", "carol", "bob"}
+	if len(standings) != len(want) {
+		t.Fatalf("expected %d standings, got %+v", len(want), standings)
+	}
+	for i, id := range want {
+		if standings[i].ProfileID != id {
+			t.Errorf("standing %d = %s, want %s", i, standings[i].ProfileID, id)
+		}
+	}
+
+	// Carol has only played good, and Bob only evil
+	good := s.RatingLeaderboard("avalon", "good", []string{"bob", "carol", "alice"})
+	if len(good) != 2 || good[0].ProfileID != "alice" || good[0].Played != 5 {
+		t.Errorf("unexpected good standings: %+v", good)
+	}
+	if evil := s.RatingLeaderboard("avalon", "evil", []string{"bob", "carol", "alice"}); len(evil) != 2 {
+		t.Errorf("expected Alice and Bob rated as evil, got %+v", evil)
+	}
+}
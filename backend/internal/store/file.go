@@ -0,0 +1,257 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+)
+
+// snapshotExt is the file extension of room snapshot files.
+const snapshotExt = ".json"
+
+// validFileRoomCode restricts room codes used as file names.
+var validFileRoomCode = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// GameFactory creates an empty game of the given type, used to restore games
+// from snapshots.
+type GameFactory func(gameType string) (core.Game, error)
+
+// FileStore keeps rooms in memory and persists them as one JSON snapshot file
+// per room in a directory. Rooms are written when created, updated and on
+// Flush; since rooms are mutated in place, callers should Flush periodically
+// and before shutting down.
+type FileStore struct {
+	*MemoryStore
+
+	dir    string
+	fileMu sync.Mutex // Serializes file writes
+}
+
+// NewFileStore opens a file store in dir, creating it if needed, and loads
+// every room snapshot found there.
+func NewFileStore(dir string, newGame GameFactory, options Options) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	s := &FileStore{
+		MemoryStore: NewMemoryStoreWithOptions(options),
+		dir:         dir,
+	}
+
+	snapshots, err := ReadSnapshots(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snapshot := range snapshots {
+		room, err := core.RestoreRoom(snapshot, newGame)
+		if err != nil {
+			// Keep the file for inspection rather than failing startup
+			slog.Error("failed to restore room", "roomCode", snapshot.ID, "error", err)
+			continue
+		}
+		s.MemoryStore.rooms[room.ID] = room
+	}
+
+	return s, nil
+}
+
+// Dir returns the directory the store persists to.
+func (s *FileStore) Dir() string {
+	return s.dir
+}
+
+// CreateRoom stores a new room and writes its snapshot.
+func (s *FileStore) CreateRoom(room *core.Room) error {
+	if !validFileRoomCode.MatchString(room.ID) {
+		return fmt.Errorf("invalid room code %q", room.ID)
+	}
+	if err := s.MemoryStore.CreateRoom(room); err != nil {
+		return err
+	}
+	return s.save(room)
+}
+
+// UpdateRoom writes the room's current snapshot.
+func (s *FileStore) UpdateRoom(room *core.Room) error {
+	if err := s.MemoryStore.UpdateRoom(room); err != nil {
+		return err
+	}
+	return s.save(room)
+}
+
+// DeleteRoom removes a room and its snapshot file.
+func (s *FileStore) DeleteRoom(roomCode string) error {
+	if err := s.MemoryStore.DeleteRoom(roomCode); err != nil {
+		return err
+	}
+	return s.remove(roomCode)
+}
+
+// CleanupStaleRooms removes stale rooms and their snapshot files.
+func (s *FileStore) CleanupStaleRooms() error {
+	if err := s.MemoryStore.CleanupStaleRooms(); err != nil {
+		return err
+	}
+	return s.pruneFiles()
+}
+
+// Flush writes the snapshot of every room.
+func (s *FileStore) Flush() error {
+	rooms, err := s.ListRooms()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, room := range rooms {
+		if err := s.save(room); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// save writes a room's snapshot atomically.
+func (s *FileStore) save(room *core.Room) error {
+	snapshot, err := room.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+
+	return WriteSnapshot(s.dir, snapshot)
+}
+
+// remove deletes a room's snapshot file.
+func (s *FileStore) remove(roomCode string) error {
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+
+	err := os.Remove(snapshotPath(s.dir, roomCode))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// pruneFiles deletes snapshot files of rooms no longer in the store.
+func (s *FileStore) pruneFiles() error {
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, entry := range entries {
+		roomCode, ok := strings.CutSuffix(entry.Name(), snapshotExt)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if _, err := s.GetRoom(roomCode); err == nil {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// snapshotPath returns the file holding a room's snapshot.
+func snapshotPath(dir string, roomCode string) string {
+	return filepath.Join(dir, roomCode+snapshotExt)
+}
+
+// ReadSnapshot reads one room snapshot from a store directory.
+func ReadSnapshot(dir string, roomCode string) (core.RoomSnapshot, error) {
+	if !validFileRoomCode.MatchString(roomCode) {
+		return core.RoomSnapshot{}, ErrRoomNotFound
+	}
+
+	data, err := os.ReadFile(snapshotPath(dir, roomCode))
+	if errors.Is(err, os.ErrNotExist) {
+		return core.RoomSnapshot{}, ErrRoomNotFound
+	}
+	if err != nil {
+		return core.RoomSnapshot{}, err
+	}
+
+	var snapshot core.RoomSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return core.RoomSnapshot{}, fmt.Errorf("room %s: %w", roomCode, err)
+	}
+	return snapshot, nil
+}
+
+// ReadSnapshots reads every room snapshot in a store directory.
+// Unreadable files are logged and skipped.
+func ReadSnapshots(dir string) ([]core.RoomSnapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]core.RoomSnapshot, 0, len(entries))
+	for _, entry := range entries {
+		roomCode, ok := strings.CutSuffix(entry.Name(), snapshotExt)
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		snapshot, err := ReadSnapshot(dir, roomCode)
+		if err != nil {
+			slog.Error("failed to read room snapshot", "file", entry.Name(), "error", err)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+// WriteSnapshot writes a room snapshot into a store directory, replacing any
+// existing snapshot of the room. The write is atomic: readers never see a
+// partially written file.
+func WriteSnapshot(dir string, snapshot core.RoomSnapshot) error {
+	if !validFileRoomCode.MatchString(snapshot.ID) {
+		return fmt.Errorf("invalid room code %q", snapshot.ID)
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, snapshot.ID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), snapshotPath(dir, snapshot.ID))
+}
+
+var _ Store = (*FileStore)(nil)
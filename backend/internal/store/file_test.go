@@ -0,0 +1,138 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KonradHerman/roundtable/internal/core"
+	"github.com/KonradHerman/roundtable/internal/games/werewolf"
+)
+
+// newWerewolf is a GameFactory for werewolf games only.
+func newWerewolf(gameType string) (core.Game, error) {
+	return werewolf.NewGame(), nil
+}
+
+func TestFileStore_PersistsAndReloads(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store, err := NewFileStore(dir, newWerewolf, DefaultOptions())
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	host := core.NewPlayer("Host")
+	room := core.NewRoom("FILE01", "werewolf", host, 10)
+	if err := store.CreateRoom(room); err != nil {
+		t.Fatalf("CreateRoom() error = %v", err)
+	}
+	for _, name := range []string{"Alice", "Bob"} {
+		room.AddPlayer(core.NewPlayer(name))
+	}
+	config := &werewolf.Config{Roles: []werewolf.RoleType{
+		werewolf.RoleWerewolf, werewolf.RoleWerewolf, werewolf.RoleSeer,
+		werewolf.RoleRobber, werewolf.RoleVillager, werewolf.RoleVillager,
+	}}
+	if err := room.StartGame(werewolf.NewGame(), config); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	reopened, err := NewFileStore(dir, newWerewolf, DefaultOptions())
+	if err != nil {
+		t.Fatalf("reopen error = %v", err)
+	}
+
+	restored, err := reopened.GetRoom("FILE01")
+	if err != nil {
+		t.Fatalf("expected room after reopen: %v", err)
+	}
+	if restored.Status != core.RoomStatusPlaying || restored.Game == nil {
+		t.Fatal("expected the running game to be restored")
+	}
+	if restored.GetEventLogLength() != room.GetEventLogLength() {
+		t.Errorf("expected %d events, got %d", room.GetEventLogLength(), restored.GetEventLogLength())
+	}
+	if _, err := restored.GetPlayerByToken(host.SessionToken); err != nil {
+		t.Error("expected host session token to survive reload")
+	}
+}
+
+func TestFileStore_DeleteAndCleanup(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store, err := NewFileStore(dir, newWerewolf, DefaultOptions())
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	deleted := core.NewRoom("DEL001", "werewolf", &core.Player{ID: "p1"}, 10)
+	stale := core.NewRoom("OLD001", "werewolf", &core.Player{ID: "p2"}, 10)
+	stale.CreatedAt = time.Now().Add(-25 * time.Hour)
+	kept := core.NewRoom("NEW001", "werewolf", &core.Player{ID: "p3", Connected: true}, 10)
+	for _, room := range []*core.Room{deleted, stale, kept} {
+		if err := store.CreateRoom(room); err != nil {
+			t.Fatalf("CreateRoom() error = %v", err)
+		}
+	}
+
+	if err := store.DeleteRoom("DEL001"); err != nil {
+		t.Fatalf("DeleteRoom() error = %v", err)
+	}
+	if err := store.CleanupStaleRooms(); err != nil {
+		t.Fatalf("CleanupStaleRooms() error = %v", err)
+	}
+
+	for code, wantFile := range map[string]bool{"DEL001": false, "OLD001": false, "NEW001": true} {
+		_, err := os.Stat(filepath.Join(dir, code+".json"))
+		if exists := err == nil; exists != wantFile {
+			t.Errorf("%s: file exists = %v, want %v", code, exists, wantFile)
+		}
+	}
+}
+
+func TestFileStore_InvalidRoomCode(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewFileStore(t.TempDir(), newWerewolf, DefaultOptions())
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	room := core.NewRoom("../escape", "werewolf", &core.Player{ID: "p1"}, 10)
+	if err := store.CreateRoom(room); err == nil {
+		t.Error("expected error for room code that is not a safe file name")
+	}
+
+	if _, err := ReadSnapshot(t.TempDir(), "../escape"); err != ErrRoomNotFound {
+		t.Errorf("expected ErrRoomNotFound, got %v", err)
+	}
+}
+
+func TestReadSnapshots_SkipsUnreadable(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	room := core.NewRoom("GOOD01", "werewolf", &core.Player{ID: "p1"}, 10)
+	snapshot, _ := room.Snapshot()
+	if err := WriteSnapshot(dir, snapshot); err != nil {
+		t.Fatalf("WriteSnapshot() error = %v", err)
+	}
+	os.WriteFile(filepath.Join(dir, "BAD001.json"), []byte("{not json"), 0o600)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600)
+
+	snapshots, err := ReadSnapshots(dir)
+	if err != nil {
+		t.Fatalf("ReadSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].ID != "GOOD01" {
+		t.Errorf("expected only GOOD01, got %d snapshots", len(snapshots))
+	}
+}
@@ -13,15 +13,33 @@ import (
 type MemoryStore struct {
 	mu    sync.RWMutex
 	rooms map[string]*core.Room // roomCode → Room
+
+	options         Options
+	cleanupListener CleanupListener // Optional, notified of cleaned up rooms
 }
 
-// NewMemoryStore creates a new in-memory store.
+// NewMemoryStore creates a new in-memory store with default options.
 func NewMemoryStore() *MemoryStore {
+	return NewMemoryStoreWithOptions(DefaultOptions())
+}
+
+// NewMemoryStoreWithOptions creates a new in-memory store.
+func NewMemoryStoreWithOptions(options Options) *MemoryStore {
 	return &MemoryStore{
-		rooms: make(map[string]*core.Room),
+		rooms:   make(map[string]*core.Room),
+		options: options,
 	}
 }
 
+// SetCleanupListener registers a function called for every room removed by
+// CleanupStaleRooms.
+func (s *MemoryStore) SetCleanupListener(listener CleanupListener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cleanupListener = listener
+}
+
 // CreateRoom stores a new room.
 func (s *MemoryStore) CreateRoom(room *core.Room) error {
 	s.mu.Lock()
@@ -94,27 +112,28 @@ func (s *MemoryStore) CleanupStaleRooms() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	const staleTimeout = 24 * time.Hour
-
-	toDelete := make([]string, 0)
+	toDelete := make(map[string]string) // roomCode → cleanup reason
 
 	for roomCode, room := range s.rooms {
 		// Get room info safely with internal locking
 		status, createdAt, anyConnected := room.GetCleanupInfo()
 
-		// Delete finished rooms older than 1 hour
-		if status == core.RoomStatusFinished && time.Since(createdAt) > 1*time.Hour {
-			toDelete = append(toDelete, roomCode)
+		// Delete finished rooms older than FinishedRoomTTL
+		if status == core.RoomStatusFinished && time.Since(createdAt) > s.options.FinishedRoomTTL {
+			toDelete[roomCode] = CleanupReasonFinished
 			continue
 		}
 
-		// Delete rooms with no connected players older than staleTimeout
-		if !anyConnected && time.Since(createdAt) > staleTimeout {
-			toDelete = append(toDelete, roomCode)
+		// Delete rooms with no connected players older than AbandonedRoomTTL
+		if !anyConnected && time.Since(createdAt) > s.options.AbandonedRoomTTL {
+			toDelete[roomCode] = CleanupReasonAbandoned
 		}
 	}
 
-	for _, roomCode := range toDelete {
+	for roomCode, reason := range toDelete {
+		if s.cleanupListener != nil {
+			s.cleanupListener(s.rooms[roomCode], reason)
+		}
 		delete(s.rooms, roomCode)
 	}
 
@@ -567,6 +567,73 @@ func TestMemoryStore_CleanupStaleRooms(t *testing.T) {
 	})
 }
 
+func TestMemoryStore_CleanupListener(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStore()
+
+	finished := core.NewRoom("FIN123", "werewolf", &core.Player{ID: "p1", DisplayName: "Player1"}, 10)
+	finished.SetStatus(core.RoomStatusFinished)
+	finished.CreatedAt = time.Now().Add(-2 * time.Hour)
+	store.CreateRoom(finished)
+
+	abandoned := core.NewRoom("ABN123", "avalon", &core.Player{ID: "p2", DisplayName: "Player2"}, 10)
+	abandoned.CreatedAt = time.Now().Add(-25 * time.Hour)
+	store.CreateRoom(abandoned)
+
+	store.CreateRoom(core.NewRoom("NEW123", "werewolf", &core.Player{ID: "p3", DisplayName: "Player3"}, 10))
+
+	reasons := make(map[string]string)
+	store.SetCleanupListener(func(room *core.Room, reason string) {
+		reasons[room.ID] = reason
+	})
+
+	if err := store.CleanupStaleRooms(); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+
+	if len(reasons) != 2 {
+		t.Fatalf("expected 2 cleaned up rooms, got %v", reasons)
+	}
+	if reasons["FIN123"] != CleanupReasonFinished {
+		t.Errorf("expected FIN123 reason %q, got %q", CleanupReasonFinished, reasons["FIN123"])
+	}
+	if reasons["ABN123"] != CleanupReasonAbandoned {
+		t.Errorf("expected ABN123 reason %q, got %q", CleanupReasonAbandoned, reasons["ABN123"])
+	}
+}
+
+func TestMemoryStore_CleanupOptions(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryStoreWithOptions(Options{
+		FinishedRoomTTL:  time.Minute,
+		AbandonedRoomTTL: 10 * time.Minute,
+	})
+
+	finished := core.NewRoom("FIN123", "werewolf", &core.Player{ID: "p1", DisplayName: "Player1"}, 10)
+	finished.SetStatus(core.RoomStatusFinished)
+	finished.CreatedAt = time.Now().Add(-2 * time.Minute)
+	store.CreateRoom(finished)
+
+	abandoned := core.NewRoom("ABN123", "avalon", &core.Player{ID: "p2", DisplayName: "Player2"}, 10)
+	abandoned.CreatedAt = time.Now().Add(-11 * time.Minute)
+	store.CreateRoom(abandoned)
+
+	recent := core.NewRoom("NEW123", "werewolf", &core.Player{ID: "p3", DisplayName: "Player3"}, 10)
+	recent.CreatedAt = time.Now().Add(-5 * time.Minute)
+	store.CreateRoom(recent)
+
+	if err := store.CleanupStaleRooms(); err != nil {
+		t.Fatalf("cleanup failed: %v", err)
+	}
+
+	rooms, _ := store.ListRooms()
+	if len(rooms) != 1 || rooms[0].ID != "NEW123" {
+		t.Errorf("expected only NEW123 to remain, got %d rooms", len(rooms))
+	}
+}
+
 func TestMemoryStore_ErrorCases(t *testing.T) {
 	t.Parallel()
 
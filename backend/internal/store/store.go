@@ -2,6 +2,7 @@ package store
 
 import (
 	"errors"
+	"time"
 
 	"github.com/KonradHerman/roundtable/internal/core"
 )
@@ -33,3 +34,36 @@ type Store interface {
 	// CleanupStaleRooms removes rooms that haven't been active recently.
 	CleanupStaleRooms() error
 }
+
+// Options configures room retention.
+type Options struct {
+	// FinishedRoomTTL is how long finished rooms are kept.
+	FinishedRoomTTL time.Duration `yaml:"finishedRoomTTL"`
+
+	// AbandonedRoomTTL is how long rooms without connected players are kept.
+	AbandonedRoomTTL time.Duration `yaml:"abandonedRoomTTL"`
+}
+
+// DefaultOptions returns the options used by NewMemoryStore.
+func DefaultOptions() Options {
+	return Options{
+		FinishedRoomTTL:  1 * time.Hour,
+		AbandonedRoomTTL: 24 * time.Hour,
+	}
+}
+
+// Flusher is implemented by stores that persist rooms and need to be flushed
+// before the server stops.
+type Flusher interface {
+	// Flush writes every room to durable storage.
+	Flush() error
+}
+
+// Reasons reported to a CleanupListener.
+const (
+	CleanupReasonFinished  = "finished"  // Game finished longer than FinishedRoomTTL ago
+	CleanupReasonAbandoned = "abandoned" // No connected players for too long
+)
+
+// CleanupListener is notified of each room removed by CleanupStaleRooms.
+type CleanupListener func(room *core.Room, reason string)
@@ -0,0 +1,371 @@
+package tournament
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/KonradHerman/roundtable/internal/util"
+)
+
+// OpenTable creates the room for a table of entrants and returns its code,
+// its creation time and the entrants' seats in it.
+type OpenTable func(number int, entrants []Entrant) (roomCode string, roomCreatedAt time.Time, seats []Seat, err error)
+
+// Store holds tournaments in memory, optionally persisted to a JSON file that
+// is rewritten on every change. It is safe for concurrent use.
+type Store struct {
+	mu          sync.Mutex
+	tournaments map[string]*Tournament // Tournament ID → tournament
+	rooms       map[string]string      // Room code of an unfinished table → tournament ID
+
+	path    string           // Empty for an in-memory store
+	now     func() time.Time // Replaced in tests
+	shuffle func([]string)   // Replaced in tests
+}
+
+// NewStore creates an in-memory tournament store.
+func NewStore() *Store {
+	return &Store{
+		tournaments: make(map[string]*Tournament),
+		rooms:       make(map[string]string),
+		now:         time.Now,
+		shuffle:     secureShuffle,
+	}
+}
+
+// OpenStore creates a tournament store persisted to path, loading the
+// tournaments saved there if the file exists.
+func OpenStore(path string) (*Store, error) {
+	s := NewStore()
+	s.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []*Tournament
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("tournaments %s: %w", path, err)
+	}
+	for _, t := range saved {
+		s.tournaments[t.ID] = t
+		s.indexRoomsLocked(t)
+	}
+	return s, nil
+}
+
+// indexRoomsLocked indexes the rooms of a tournament's unfinished tables.
+// The caller holds s.mu.
+func (s *Store) indexRoomsLocked(t *Tournament) {
+	if t.Status == StatusFinished {
+		return
+	}
+	for _, round := range t.Rounds {
+		for _, table := range round.Tables {
+			if !table.Finished {
+				s.rooms[table.RoomCode] = t.ID
+			}
+		}
+	}
+}
+
+// unindexRoomLocked forgets a table's room once the table is done with it,
+// unless the code has since been reused by another tournament's table.
+// The caller holds s.mu.
+func (s *Store) unindexRoomLocked(t *Tournament, table *Table) {
+	if s.rooms[table.RoomCode] == t.ID {
+		delete(s.rooms, table.RoomCode)
+	}
+}
+
+// Create creates a tournament organized by a profile. The game type must
+// have been checked by the caller.
+func (s *Store) Create(organizerID string, options Options) (Tournament, error) {
+	if err := options.Validate(); err != nil {
+		return Tournament{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	t := &Tournament{
+		ID:          uuid.New().String(),
+		OrganizerID: organizerID,
+		Options:     options,
+		Status:      StatusRegistration,
+		Entrants:    make([]Entrant, 0),
+		Rounds:      make([]*Round, 0),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	s.tournaments[t.ID] = t
+
+	return t.clone(), s.save()
+}
+
+// Get returns a tournament.
+func (s *Store) Get(id string) (Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[id]
+	if !ok {
+		return Tournament{}, ErrNotFound
+	}
+	return t.clone(), nil
+}
+
+// Register enters a profile into a tournament before its first round.
+func (s *Store) Register(id string, entrant Entrant) (Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[id]
+	if !ok {
+		return Tournament{}, ErrNotFound
+	}
+	if t.Status != StatusRegistration {
+		return Tournament{}, ErrRegistrationClosed
+	}
+	for _, registered := range t.Entrants {
+		if registered.ProfileID == entrant.ProfileID {
+			return Tournament{}, ErrAlreadyRegistered
+		}
+	}
+	if len(t.Entrants) >= MaxEntrants {
+		return Tournament{}, ErrTournamentFull
+	}
+
+	entrant.RegisteredAt = s.now()
+	t.Entrants = append(t.Entrants, entrant)
+	t.UpdatedAt = entrant.RegisteredAt
+
+	return t.clone(), s.save()
+}
+
+// StartRound seats the entrants at tables for the next round, opening a room
+// for each table. Registration closes with the first round. Only the
+// organizer can start rounds, and only once every table of the previous round
+// has finished.
+func (s *Store) StartRound(id, organizerID string, open OpenTable) (Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[id]
+	if !ok {
+		return Tournament{}, ErrNotFound
+	}
+	if t.OrganizerID != organizerID {
+		return Tournament{}, ErrNotOrganizer
+	}
+	if t.Status == StatusFinished {
+		return Tournament{}, ErrFinished
+	}
+	if current := t.currentRound(); current != nil && !current.finished() {
+		return Tournament{}, ErrRoundInProgress
+	}
+
+	seating, err := t.seat(s.shuffle)
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	entrants := make(map[string]Entrant, len(t.Entrants))
+	for _, entrant := range t.Entrants {
+		entrants[entrant.ProfileID] = entrant
+	}
+
+	round := &Round{Number: len(t.Rounds) + 1, StartedAt: s.now()}
+	for i, profileIDs := range seating {
+		table := make([]Entrant, len(profileIDs))
+		for j, id := range profileIDs {
+			table[j] = entrants[id]
+		}
+		roomCode, roomCreatedAt, seats, err := open(i+1, table)
+		if err != nil {
+			// Rooms already opened are abandoned and cleaned up by the store
+			return Tournament{}, fmt.Errorf("failed to open table %d: %w", i+1, err)
+		}
+		round.Tables = append(round.Tables, &Table{
+			Number:        i + 1,
+			RoomCode:      roomCode,
+			RoomCreatedAt: roomCreatedAt,
+			Seats:         seats,
+		})
+	}
+
+	t.Rounds = append(t.Rounds, round)
+	t.Status = StatusPlaying
+	t.UpdatedAt = round.StartedAt
+	s.indexRoomsLocked(t)
+
+	return t.clone(), s.save()
+}
+
+// RecordTable records the results of the game played in a tournament room,
+// identified by its code and creation time. Only the first game finished at
+// a table counts. It returns the tournament and whether the room belongs to
+// an unfinished table.
+func (s *Store) RecordTable(roomCode string, roomCreatedAt time.Time, results map[string]Result) (Tournament, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.rooms[roomCode]
+	if !ok {
+		return Tournament{}, false, nil
+	}
+	t := s.tournaments[id]
+	if t.Status == StatusFinished {
+		return Tournament{}, false, nil
+	}
+
+	for _, round := range t.Rounds {
+		for _, table := range round.Tables {
+			if table.RoomCode != roomCode || !table.RoomCreatedAt.Equal(roomCreatedAt) || table.Finished {
+				continue
+			}
+
+			// Only seated entrants score
+			table.Results = make(map[string]Result, len(table.Seats))
+			for _, seat := range table.Seats {
+				table.Results[seat.ProfileID] = results[seat.ProfileID]
+			}
+			s.finishTableLocked(t, round, table)
+			return t.clone(), true, s.save()
+		}
+	}
+	return Tournament{}, false, nil
+}
+
+// CloseTable lets the organizer finish a table of the current round without
+// a result, e.g. when its room was closed before the game finished. Nobody
+// at the table scores, and the next round can start once the others finish.
+func (s *Store) CloseTable(id, organizerID string, number int) (Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[id]
+	if !ok {
+		return Tournament{}, ErrNotFound
+	}
+	if t.OrganizerID != organizerID {
+		return Tournament{}, ErrNotOrganizer
+	}
+	if t.Status == StatusFinished {
+		return Tournament{}, ErrFinished
+	}
+
+	round := t.currentRound()
+	if round == nil {
+		return Tournament{}, ErrTableNotFound
+	}
+	for _, table := range round.Tables {
+		if table.Number != number {
+			continue
+		}
+		if table.Finished {
+			return Tournament{}, ErrTableFinished
+		}
+
+		table.Closed = true
+		s.finishTableLocked(t, round, table)
+		return t.clone(), s.save()
+	}
+	return Tournament{}, ErrTableNotFound
+}
+
+// finishTableLocked marks a table finished, finishing its round and the
+// tournament after the last table of the last round.
+// The caller holds s.mu.
+func (s *Store) finishTableLocked(t *Tournament, round *Round, table *Table) {
+	table.Finished = true
+	s.unindexRoomLocked(t, table)
+
+	now := s.now()
+	t.UpdatedAt = now
+	if round.finished() {
+		round.FinishedAt = now
+		if len(t.Rounds) >= t.Options.Rounds {
+			t.Status = StatusFinished
+		}
+	}
+}
+
+// Finish ends a tournament early, publishing the standings so far. Tables
+// still playing no longer count.
+func (s *Store) Finish(id, organizerID string) (Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[id]
+	if !ok {
+		return Tournament{}, ErrNotFound
+	}
+	if t.OrganizerID != organizerID {
+		return Tournament{}, ErrNotOrganizer
+	}
+	if t.Status == StatusFinished {
+		return Tournament{}, ErrFinished
+	}
+
+	t.Status = StatusFinished
+	t.UpdatedAt = s.now()
+	for _, round := range t.Rounds {
+		for _, table := range round.Tables {
+			s.unindexRoomLocked(t, table)
+		}
+	}
+	return t.clone(), s.save()
+}
+
+// Seat returns a profile's table in the tournament's current round.
+func (s *Store) Seat(id, profileID string) (Table, Seat, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tournaments[id]
+	if !ok {
+		return Table{}, Seat{}, ErrNotFound
+	}
+	current := t.currentRound()
+	if current == nil {
+		return Table{}, Seat{}, ErrNotSeated
+	}
+	for _, table := range current.Tables {
+		for _, seat := range table.Seats {
+			if seat.ProfileID == profileID {
+				return table.clone(), seat, nil
+			}
+		}
+	}
+	return Table{}, Seat{}, ErrNotSeated
+}
+
+// save writes all tournaments to the store's file. Changes stay in memory
+// even if writing fails. The caller holds s.mu.
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	saved := make([]*Tournament, 0, len(s.tournaments))
+	for _, t := range s.tournaments {
+		saved = append(saved, t)
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	return util.WriteFileAtomic(s.path, data)
+}
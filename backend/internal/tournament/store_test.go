@@ -0,0 +1,268 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// openTables returns an OpenTable that names rooms after their round and
+// table, and seats players under their profile IDs.
+func openTables(round *int) OpenTable {
+	return func(number int, entrants []Entrant) (string, time.Time, []Seat, error) {
+		seats := make([]Seat, len(entrants))
+		for i, entrant := range entrants {
+			seats[i] = Seat{ProfileID: entrant.ProfileID, PlayerID: "player-" + entrant.ProfileID}
+		}
+		return fmt.Sprintf("R%dT%d", *round, number), roomCreatedAt, seats, nil
+	}
+}
+
+// roomCreatedAt is when every room opened by openTables was created.
+var roomCreatedAt = time.Unix(1_700_000_000, 0)
+
+func newTournament(t *testing.T, s *Store, entrants int) Tournament {
+	t.Helper()
+
+	tournament, err := s.Create("organizer", Options{
+		Name:         "Spring cup",
+		GameType:     "werewolf",
+		Pairing:      PairingSwiss,
+		Rounds:       2,
+		TableSize:    4,
+		MinTableSize: 3,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for i := 0; i < entrants; i++ {
+		id := fmt.Sprintf("p%d", i)
+		if _, err := s.Register(tournament.ID, Entrant{ProfileID: id, DisplayName: id}); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+	return tournament
+}
+
+// finishRound records every table of the current round, with the first
+// seat winning.
+func finishRound(t *testing.T, s *Store, id string) Tournament {
+	t.Helper()
+
+	tournament, _ := s.Get(id)
+	var last Tournament
+	for _, table := range tournament.Rounds[len(tournament.Rounds)-1].Tables {
+		winner := table.Seats[0].ProfileID
+		updated, ok, err := s.RecordTable(table.RoomCode, table.RoomCreatedAt, map[string]Result{winner: {Points: 3, Won: true}})
+		if err != nil || !ok {
+			t.Fatalf("RecordTable() = %v, %v", ok, err)
+		}
+		last = updated
+	}
+	return last
+}
+
+func TestStore_Tournament(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	tournament := newTournament(t, s, 7)
+	round := 1
+
+	if _, err := s.Register(tournament.ID, Entrant{ProfileID: "p0"}); !errors.Is(err, ErrAlreadyRegistered) {
+		t.Errorf("expected ErrAlreadyRegistered, got %v", err)
+	}
+	if _, err := s.StartRound(tournament.ID, "someone", openTables(&round)); !errors.Is(err, ErrNotOrganizer) {
+		t.Errorf("expected ErrNotOrganizer, got %v", err)
+	}
+
+	started, err := s.StartRound(tournament.ID, "organizer", openTables(&round))
+	if err != nil {
+		t.Fatalf("StartRound() error = %v", err)
+	}
+	if started.Status != StatusPlaying || len(started.Rounds[0].Tables) != 2 {
+		t.Fatalf("expected two tables playing, got %+v", started)
+	}
+	if _, err := s.Register(tournament.ID, Entrant{ProfileID: "late"}); !errors.Is(err, ErrRegistrationClosed) {
+		t.Errorf("expected ErrRegistrationClosed, got %v", err)
+	}
+	if _, err := s.StartRound(tournament.ID, "organizer", openTables(&round)); !errors.Is(err, ErrRoundInProgress) {
+		t.Errorf("expected ErrRoundInProgress, got %v", err)
+	}
+
+	table, seat, err := s.Seat(tournament.ID, "p3")
+	if err != nil || seat.PlayerID != "player-p3" || table.RoomCode == "" {
+		t.Errorf("Seat() = %+v, %+v, %v", table, seat, err)
+	}
+	if _, _, err := s.Seat(tournament.ID, "stranger"); !errors.Is(err, ErrNotSeated) {
+		t.Errorf("expected ErrNotSeated, got %v", err)
+	}
+
+	finishRound(t, s, tournament.ID)
+
+	// Replays in the same room don't count again
+	if _, ok, _ := s.RecordTable("R1T1", roomCreatedAt, map[string]Result{"p0": {Points: 100}}); ok {
+		t.Error("expected a finished table to ignore later games")
+	}
+	if _, ok, _ := s.RecordTable("OTHER", roomCreatedAt, nil); ok {
+		t.Error("expected rooms outside tournaments to be ignored")
+	}
+
+	round = 2
+	second, err := s.StartRound(tournament.ID, "organizer", openTables(&round))
+	if err != nil {
+		t.Fatalf("StartRound() error = %v", err)
+	}
+
+	// Swiss pairing seats both round one winners at the first table
+	winners := 0
+	for _, seat := range second.Rounds[1].Tables[0].Seats {
+		for _, standing := range second.Standings() {
+			if standing.ProfileID == seat.ProfileID && standing.Wins == 1 {
+				winners++
+			}
+		}
+	}
+	if winners != 2 {
+		t.Errorf("expected both winners at table 1, got %+v", second.Rounds[1].Tables[0].Seats)
+	}
+
+	final := finishRound(t, s, tournament.ID)
+	if final.Status != StatusFinished {
+		t.Errorf("expected the tournament to finish after its last round, got %s", final.Status)
+	}
+	standings := final.Standings()
+	if standings[0].Points != 6 || standings[0].Wins != 2 {
+		t.Errorf("expected a double winner on top, got %+v", standings[0])
+	}
+	if _, err := s.StartRound(tournament.ID, "organizer", openTables(&round)); !errors.Is(err, ErrFinished) {
+		t.Errorf("expected ErrFinished, got %v", err)
+	}
+}
+
+func TestStore_Finish(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	tournament := newTournament(t, s, 3)
+
+	if _, err := s.Finish(tournament.ID, "someone"); !errors.Is(err, ErrNotOrganizer) {
+		t.Errorf("expected ErrNotOrganizer, got %v", err)
+	}
+	finished, err := s.Finish(tournament.ID, "organizer")
+	if err != nil || finished.Status != StatusFinished {
+		t.Fatalf("Finish() = %+v, %v", finished, err)
+	}
+	if _, err := s.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestOpenStore_Persists(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tournaments.json")
+	s, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	tournament := newTournament(t, s, 3)
+	round := 1
+	if _, err := s.StartRound(tournament.ID, "organizer", openTables(&round)); err != nil {
+		t.Fatalf("StartRound() error = %v", err)
+	}
+
+	reopened, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore() error = %v", err)
+	}
+	if _, ok, err := reopened.RecordTable("R1T1", roomCreatedAt, map[string]Result{"p0": {Points: 1, Won: true}}); !ok || err != nil {
+		t.Fatalf("expected tournament rooms to be indexed after reopening, got %v, %v", ok, err)
+	}
+	if got, _ := reopened.Get(tournament.ID); got.Name != "Spring cup" || len(got.Entrants) != 3 {
+		t.Errorf("expected tournament to persist, got %+v", got)
+	}
+}
+
+func TestStore_CloseTable(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	tournament := newTournament(t, s, 7)
+	round := 1
+
+	if _, err := s.CloseTable(tournament.ID, "organizer", 1); !errors.Is(err, ErrTableNotFound) {
+		t.Errorf("expected ErrTableNotFound before the first round, got %v", err)
+	}
+	if _, err := s.StartRound(tournament.ID, "organizer", openTables(&round)); err != nil {
+		t.Fatalf("StartRound() error = %v", err)
+	}
+
+	// Table 2's room closed before its game finished
+	if _, ok, err := s.RecordTable("R1T1", roomCreatedAt, map[string]Result{"p0": {Points: 3, Won: true}}); !ok || err != nil {
+		t.Fatalf("RecordTable() = %v, %v", ok, err)
+	}
+	if _, err := s.StartRound(tournament.ID, "organizer", openTables(&round)); !errors.Is(err, ErrRoundInProgress) {
+		t.Fatalf("expected ErrRoundInProgress, got %v", err)
+	}
+
+	if _, err := s.CloseTable(tournament.ID, "someone", 2); !errors.Is(err, ErrNotOrganizer) {
+		t.Errorf("expected ErrNotOrganizer, got %v", err)
+	}
+	if _, err := s.CloseTable(tournament.ID, "organizer", 1); !errors.Is(err, ErrTableFinished) {
+		t.Errorf("expected ErrTableFinished, got %v", err)
+	}
+	if _, err := s.CloseTable(tournament.ID, "organizer", 3); !errors.Is(err, ErrTableNotFound) {
+		t.Errorf("expected ErrTableNotFound, got %v", err)
+	}
+
+	closed, err := s.CloseTable(tournament.ID, "organizer", 2)
+	if err != nil {
+		t.Fatalf("CloseTable() error = %v", err)
+	}
+	table := closed.Rounds[0].Tables[1]
+	if !table.Finished || !table.Closed || table.Results != nil {
+		t.Errorf("expected table 2 closed without results, got %+v", table)
+	}
+	for _, standing := range closed.Standings() {
+		if standing.Played > 1 || (standing.Played == 0 && standing.Points != 0) {
+			t.Errorf("expected only table 1 to count, got %+v", standing)
+		}
+	}
+
+	// A game finishing in the closed room afterwards doesn't count
+	if _, ok, _ := s.RecordTable("R1T2", roomCreatedAt, map[string]Result{"p1": {Points: 3, Won: true}}); ok {
+		t.Error("expected a closed table to ignore later games")
+	}
+
+	round = 2
+	if _, err := s.StartRound(tournament.ID, "organizer", openTables(&round)); err != nil {
+		t.Errorf("expected the next round to start, got %v", err)
+	}
+}
+
+func TestStore_RecordTableMatchesRoom(t *testing.T) {
+	t.Parallel()
+
+	s := NewStore()
+	tournament := newTournament(t, s, 4)
+	round := 1
+	if _, err := s.StartRound(tournament.ID, "organizer", openTables(&round)); err != nil {
+		t.Fatalf("StartRound() error = %v", err)
+	}
+
+	// A later room given the table's code after its room closed
+	later := roomCreatedAt.Add(time.Hour)
+	if _, ok, _ := s.RecordTable("R1T1", later, map[string]Result{"p0": {Points: 3, Won: true}}); ok {
+		t.Error("expected a room reusing the table's code to be ignored")
+	}
+
+	if _, err := s.CloseTable(tournament.ID, "organizer", 1); err != nil {
+		t.Fatalf("CloseTable() error = %v", err)
+	}
+	if len(s.rooms) != 0 {
+		t.Errorf("expected finished tables' rooms to be forgotten, got %v", s.rooms)
+	}
+}
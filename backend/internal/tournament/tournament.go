@@ -0,0 +1,314 @@
+// Package tournament runs tournaments across several rooms: registered
+// profiles are seated at tables each round, every table plays one game in its
+// own room, and results add up to tournament standings.
+package tournament
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+var (
+	ErrNotFound           = errors.New("tournament not found")
+	ErrNotOrganizer       = errors.New("only the organizer can manage the tournament")
+	ErrRegistrationClosed = errors.New("registration is closed")
+	ErrAlreadyRegistered  = errors.New("already registered")
+	ErrTournamentFull     = errors.New("tournament is full")
+	ErrRoundInProgress    = errors.New("the current round has unfinished tables")
+	ErrFinished           = errors.New("tournament has finished")
+	ErrNotEnoughPlayers   = errors.New("not enough players to seat a table")
+	ErrNotSeated          = errors.New("not seated in the current round")
+	ErrTableNotFound      = errors.New("no such table in the current round")
+	ErrTableFinished      = errors.New("table has finished")
+	ErrInvalidOptions     = errors.New("invalid tournament options")
+)
+
+// Limits on tournament settings.
+const (
+	MaxEntrants = 200
+	MaxRounds   = 20
+	MaxNameLen  = 60
+)
+
+// Pairing is how players are seated each round.
+type Pairing string
+
+const (
+	PairingRandom Pairing = "random" // Shuffled every round
+	PairingSwiss  Pairing = "swiss"  // Players with similar standings share tables
+)
+
+// Status is a tournament's progress.
+type Status string
+
+const (
+	StatusRegistration Status = "registration" // Taking entrants, no round started
+	StatusPlaying      Status = "playing"      // At least one round started
+	StatusFinished     Status = "finished"     // Final standings published
+)
+
+// Entrant is a registered profile.
+type Entrant struct {
+	ProfileID    string    `json:"profileId"`
+	DisplayName  string    `json:"displayName"`
+	RegisteredAt time.Time `json:"registeredAt"`
+}
+
+// Seat is an entrant's place at a table, as a player in the table's room.
+type Seat struct {
+	ProfileID string `json:"profileId"`
+	PlayerID  string `json:"playerId"`
+}
+
+// Result is an entrant's result at a table.
+type Result struct {
+	Points int  `json:"points"`
+	Won    bool `json:"won"`
+}
+
+// Table is a group of entrants playing one game in a room.
+type Table struct {
+	Number        int               `json:"number"`
+	RoomCode      string            `json:"roomCode"`
+	RoomCreatedAt time.Time         `json:"roomCreatedAt"` // Tells the table's room from later ones reusing the code
+	Seats         []Seat            `json:"seats"`
+	Results       map[string]Result `json:"results,omitempty"` // Profile ID → result, once finished
+	Finished      bool              `json:"finished"`
+	Closed        bool              `json:"closed,omitempty"` // Finished by the organizer without a result
+}
+
+// clone returns a deep copy.
+func (t *Table) clone() Table {
+	c := *t
+	c.Seats = append([]Seat(nil), t.Seats...)
+	if t.Results != nil {
+		c.Results = make(map[string]Result, len(t.Results))
+		for id, result := range t.Results {
+			c.Results[id] = result
+		}
+	}
+	return c
+}
+
+// Round is one game at every table.
+type Round struct {
+	Number     int       `json:"number"`
+	Tables     []*Table  `json:"tables"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt,omitempty"`
+}
+
+// finished reports whether every table has a result or was closed.
+func (r *Round) finished() bool {
+	for _, table := range r.Tables {
+		if !table.Finished {
+			return false
+		}
+	}
+	return true
+}
+
+// Options are the settings an organizer creates a tournament with.
+type Options struct {
+	Name         string  `json:"name"`
+	GameType     string  `json:"gameType"`
+	Pairing      Pairing `json:"pairing"`
+	Rounds       int     `json:"rounds"`       // Rounds to play; the tournament finishes after the last
+	TableSize    int     `json:"tableSize"`    // Most players at a table
+	MinTableSize int     `json:"minTableSize"` // Fewest players a game can be played with
+}
+
+// Validate checks the options, except whether the game type exists.
+func (o Options) Validate() error {
+	switch {
+	case o.Name == "" || len(o.Name) > MaxNameLen:
+		return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidOptions, MaxNameLen)
+	case o.Pairing != PairingRandom && o.Pairing != PairingSwiss:
+		return fmt.Errorf("%w: pairing must be %q or %q", ErrInvalidOptions, PairingRandom, PairingSwiss)
+	case o.Rounds < 1 || o.Rounds > MaxRounds:
+		return fmt.Errorf("%w: rounds must be 1-%d", ErrInvalidOptions, MaxRounds)
+	case o.MinTableSize < 1 || o.TableSize < o.MinTableSize:
+		return fmt.Errorf("%w: table size must be at least the minimum table size, which must be positive", ErrInvalidOptions)
+	}
+	return nil
+}
+
+// Tournament is a tournament and its rounds so far.
+type Tournament struct {
+	ID          string `json:"id"`
+	OrganizerID string `json:"organizerId"` // Profile ID
+	Options
+	Status    Status    `json:"status"`
+	Entrants  []Entrant `json:"entrants"`
+	Rounds    []*Round  `json:"rounds"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// currentRound returns the latest round, or nil before the first.
+func (t *Tournament) currentRound() *Round {
+	if len(t.Rounds) == 0 {
+		return nil
+	}
+	return t.Rounds[len(t.Rounds)-1]
+}
+
+// clone returns a deep copy.
+func (t *Tournament) clone() Tournament {
+	c := *t
+	c.Entrants = append([]Entrant(nil), t.Entrants...)
+	c.Rounds = make([]*Round, len(t.Rounds))
+	for i, round := range t.Rounds {
+		r := *round
+		r.Tables = make([]*Table, len(round.Tables))
+		for j, table := range round.Tables {
+			tc := table.clone()
+			r.Tables[j] = &tc
+		}
+		c.Rounds[i] = &r
+	}
+	return c
+}
+
+// Standing is an entrant's place in the tournament.
+type Standing struct {
+	Rank        int    `json:"rank"` // Tied entrants share a rank
+	ProfileID   string `json:"profileId"`
+	DisplayName string `json:"displayName"`
+	Points      int    `json:"points"`
+	Wins        int    `json:"wins"`
+	Played      int    `json:"played"`
+}
+
+// Standings ranks the entrants by points, then wins, then fewest games
+// played.
+func (t *Tournament) Standings() []Standing {
+	byProfile := make(map[string]*Standing, len(t.Entrants))
+	standings := make([]*Standing, 0, len(t.Entrants))
+	for _, entrant := range t.Entrants {
+		standing := &Standing{ProfileID: entrant.ProfileID, DisplayName: entrant.DisplayName}
+		byProfile[entrant.ProfileID] = standing
+		standings = append(standings, standing)
+	}
+
+	for _, round := range t.Rounds {
+		for _, table := range round.Tables {
+			for id, result := range table.Results {
+				standing, ok := byProfile[id]
+				if !ok {
+					continue
+				}
+				standing.Points += result.Points
+				standing.Played++
+				if result.Won {
+					standing.Wins++
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return a.Played < b.Played
+	})
+
+	ranked := make([]Standing, len(standings))
+	for i, standing := range standings {
+		ranked[i] = *standing
+		ranked[i].Rank = i + 1
+		if i > 0 {
+			prev := ranked[i-1]
+			if prev.Points == standing.Points && prev.Wins == standing.Wins && prev.Played == standing.Played {
+				ranked[i].Rank = prev.Rank
+			}
+		}
+	}
+	return ranked
+}
+
+// tableSizes splits players into the fewest tables of at most max players,
+// as evenly as possible. It fails if a table would have fewer than min.
+func tableSizes(players, min, max int) ([]int, error) {
+	if players < min {
+		return nil, ErrNotEnoughPlayers
+	}
+
+	count := (players + max - 1) / max
+	sizes := make([]int, count)
+	for i := range sizes {
+		sizes[i] = players / count
+		if i < players%count {
+			sizes[i]++
+		}
+	}
+	if sizes[count-1] < min {
+		return nil, fmt.Errorf("%w: %d players do not fit tables of %d-%d", ErrNotEnoughPlayers, players, min, max)
+	}
+	return sizes, nil
+}
+
+// seat groups the entrants into tables for the next round. Random pairing
+// shuffles everyone; swiss pairing seats entrants in standings order, so
+// leaders play leaders, with ties shuffled.
+func (t *Tournament) seat(shuffle func([]string)) ([][]string, error) {
+	sizes, err := tableSizes(len(t.Entrants), t.MinTableSize, t.TableSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var order []string
+	switch t.Pairing {
+	case PairingSwiss:
+		// Shuffle within groups of tied entrants
+		standings := t.Standings()
+		for start := 0; start < len(standings); {
+			end := start + 1
+			for end < len(standings) && standings[end].Rank == standings[start].Rank {
+				end++
+			}
+			group := make([]string, 0, end-start)
+			for _, standing := range standings[start:end] {
+				group = append(group, standing.ProfileID)
+			}
+			shuffle(group)
+			order = append(order, group...)
+			start = end
+		}
+	default:
+		for _, entrant := range t.Entrants {
+			order = append(order, entrant.ProfileID)
+		}
+		shuffle(order)
+	}
+
+	tables := make([][]string, 0, len(sizes))
+	for _, size := range sizes {
+		tables = append(tables, order[:size])
+		order = order[size:]
+	}
+	return tables, nil
+}
+
+// secureShuffle shuffles profile IDs using cryptographically secure
+// randomness, so seating cannot be predicted.
+func secureShuffle(ids []string) {
+	for i := len(ids) - 1; i > 0; i-- {
+		jBig, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			// This should never happen with crypto/rand
+			panic(fmt.Sprintf("failed to generate random number: %v", err))
+		}
+		j := int(jBig.Int64())
+		ids[i], ids[j] = ids[j], ids[i]
+	}
+}
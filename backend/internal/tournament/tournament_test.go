@@ -0,0 +1,155 @@
+package tournament
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTableSizes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		players  int
+		min, max int
+		want     []int
+		wantErr  bool
+	}{
+		{name: "one table", players: 7, min: 5, max: 10, want: []int{7}},
+		{name: "even split", players: 20, min: 5, max: 10, want: []int{10, 10}},
+		{name: "balanced", players: 23, min: 5, max: 10, want: []int{8, 8, 7}},
+		{name: "forty", players: 40, min: 3, max: 8, want: []int{8, 8, 8, 8, 8}},
+		{name: "too few", players: 4, min: 5, max: 10, wantErr: true},
+		{name: "tables too small", players: 11, min: 6, max: 10, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := tableSizes(tt.players, tt.min, tt.max)
+		if tt.wantErr {
+			if !errors.Is(err, ErrNotEnoughPlayers) {
+				t.Errorf("%s: expected ErrNotEnoughPlayers, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: tableSizes() = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := Options{Name: "Spring cup", GameType: "avalon", Pairing: PairingSwiss, Rounds: 3, TableSize: 10, MinTableSize: 5}
+
+	tests := []struct {
+		name   string
+		modify func(*Options)
+		valid  bool
+	}{
+		{name: "valid", modify: func(o *Options) {}, valid: true},
+		{name: "no name", modify: func(o *Options) { o.Name = "" }},
+		{name: "unknown pairing", modify: func(o *Options) { o.Pairing = "knockout" }},
+		{name: "no rounds", modify: func(o *Options) { o.Rounds = 0 }},
+		{name: "too many rounds", modify: func(o *Options) { o.Rounds = MaxRounds + 1 }},
+		{name: "tables smaller than minimum", modify: func(o *Options) { o.TableSize = 4 }},
+	}
+
+	for _, tt := range tests {
+		options := valid
+		tt.modify(&options)
+		err := options.Validate()
+		if tt.valid != (err == nil) {
+			t.Errorf("%s: Validate() = %v", tt.name, err)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidOptions) {
+			t.Errorf("%s: expected ErrInvalidOptions, got %v", tt.name, err)
+		}
+	}
+}
+
+// finishedTournament is a swiss tournament of six entrants after one round,
+// in which a and b won their tables and c lost with more points than the
+// others.
+func finishedTournament() *Tournament {
+	t := &Tournament{
+		Options: Options{Pairing: PairingSwiss, TableSize: 3, MinTableSize: 3},
+	}
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		t.Entrants = append(t.Entrants, Entrant{ProfileID: id, DisplayName: id})
+	}
+	t.Rounds = []*Round{{Number: 1, Tables: []*Table{
+		{Number: 1, Finished: true, Results: map[string]Result{"a": {Points: 3, Won: true}, "c": {Points: 1}, "e": {}}},
+		{Number: 2, Finished: true, Results: map[string]Result{"b": {Points: 3, Won: true}, "d": {}, "f": {}}},
+	}}}
+	return t
+}
+
+func TestTournament_Standings(t *testing.T) {
+	t.Parallel()
+
+	standings := finishedTournament().Standings()
+
+	if len(standings) != 6 {
+		t.Fatalf("expected 6 standings, got %+v", standings)
+	}
+	if standings[0].Rank != 1 || standings[1].Rank != 1 || standings[0].Wins != 1 {
+		t.Errorf("expected a and b tied first, got %+v", standings[:2])
+	}
+	if standings[2].ProfileID != "c" || standings[2].Rank != 3 {
+		t.Errorf("expected c third, got %+v", standings[2])
+	}
+	for _, standing := range standings[3:] {
+		if standing.Rank != 4 || standing.Played != 1 {
+			t.Errorf("expected d, e and f tied fourth, got %+v", standing)
+		}
+	}
+}
+
+func TestTournament_SeatSwiss(t *testing.T) {
+	t.Parallel()
+
+	reverse := func(ids []string) {
+		for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+	}
+
+	tables, err := finishedTournament().seat(reverse)
+	if err != nil {
+		t.Fatalf("seat() error = %v", err)
+	}
+
+	// Leaders share the top table; ties are shuffled within their group
+	want := [][]string{{"b", "a", "c"}, {"f", "e", "d"}}
+	if !reflect.DeepEqual(tables, want) {
+		t.Errorf("seat() = %v, want %v", tables, want)
+	}
+}
+
+func TestTournament_SeatRandom(t *testing.T) {
+	t.Parallel()
+
+	tournament := finishedTournament()
+	tournament.Pairing = PairingRandom
+	tournament.TableSize = 4
+
+	tables, err := tournament.seat(secureShuffle)
+	if err != nil {
+		t.Fatalf("seat() error = %v", err)
+	}
+	if len(tables) != 2 || len(tables[0]) != 3 || len(tables[1]) != 3 {
+		t.Fatalf("expected two tables of 3, got %v", tables)
+	}
+
+	seated := make(map[string]bool)
+	for _, table := range tables {
+		for _, id := range table {
+			seated[id] = true
+		}
+	}
+	if len(seated) != 6 {
+		t.Errorf("expected every entrant seated once, got %v", tables)
+	}
+}
@@ -0,0 +1,140 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// allocateAttempts is how many random codes are tried before giving up.
+// Even with the code space 90% full, 10 misses in a row are rare.
+const allocateAttempts = 10
+
+var (
+	ErrCodeSpaceExhausted  = errors.New("no free room codes")
+	ErrCodeTaken           = errors.New("room code is taken")
+	ErrInvalidCode         = errors.New("invalid room code")
+	ErrCustomCodesDisabled = errors.New("custom room codes are disabled")
+)
+
+// CodeAllocator hands out room codes that are not in use and were not freed
+// recently. It is safe for concurrent use.
+//
+// Allocation does not reserve the code: two requests can race for the same
+// code, so creating the room must still fail on duplicates, and callers retry.
+type CodeAllocator struct {
+	options CodeOptions
+	inUse   func(code string) bool // Reports whether a room has the code
+
+	mu       sync.Mutex
+	released map[string]time.Time // Freed code → end of its cooldown
+	now      func() time.Time     // Replaced in tests
+}
+
+// NewCodeAllocator creates an allocator. inUse reports whether a room
+// currently has a code, usually by looking it up in the store.
+func NewCodeAllocator(options CodeOptions, inUse func(code string) bool) (*CodeAllocator, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &CodeAllocator{
+		options:  options,
+		inUse:    inUse,
+		released: make(map[string]time.Time),
+		now:      time.Now,
+	}, nil
+}
+
+// Allocate returns a random free code.
+func (a *CodeAllocator) Allocate() (string, error) {
+	for i := 0; i < allocateAttempts; i++ {
+		code, err := NewCode(a.options.Alphabet, a.options.Length)
+		if err != nil {
+			return "", err
+		}
+		if a.available(code) {
+			return code, nil
+		}
+	}
+	return "", ErrCodeSpaceExhausted
+}
+
+// Claim checks a code chosen by a host and returns it normalized.
+func (a *CodeAllocator) Claim(code string) (string, error) {
+	if !a.options.AllowCustom {
+		return "", ErrCustomCodesDisabled
+	}
+
+	code = a.Normalize(code)
+	if len(code) < MinCodeLength || len(code) > MaxCodeLength {
+		return "", fmt.Errorf("%w: must be %d to %d characters", ErrInvalidCode, MinCodeLength, MaxCodeLength)
+	}
+	for _, c := range code {
+		if !strings.ContainsRune(a.options.Alphabet, c) {
+			return "", fmt.Errorf("%w: %q is not allowed", ErrInvalidCode, c)
+		}
+	}
+
+	if !a.available(code) {
+		return "", ErrCodeTaken
+	}
+	return code, nil
+}
+
+// Take records that a room brought in from elsewhere, such as an imported
+// snapshot, has the code, ending any cooldown since the code is live again.
+// The code must be in normalized form, or players could never type it.
+func (a *CodeAllocator) Take(code string) error {
+	if code == "" || a.Normalize(code) != code {
+		return fmt.Errorf("%w: %q is not a code players can type", ErrInvalidCode, code)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.released, code)
+	return nil
+}
+
+// Normalize converts a code as typed by a player to its canonical form.
+// Codes are case-insensitive unless the alphabet mixes cases.
+func (a *CodeAllocator) Normalize(code string) string {
+	code = strings.TrimSpace(code)
+	if a.options.Alphabet == strings.ToUpper(a.options.Alphabet) {
+		code = strings.ToUpper(code)
+	}
+	return code
+}
+
+// Release starts the cooldown of a code whose room was removed.
+func (a *CodeAllocator) Release(code string) {
+	if a.options.Cooldown <= 0 {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	for released, until := range a.released {
+		if !now.Before(until) {
+			delete(a.released, released)
+		}
+	}
+	a.released[code] = now.Add(a.options.Cooldown)
+}
+
+// available reports whether a code is neither in use nor cooling down.
+func (a *CodeAllocator) available(code string) bool {
+	a.mu.Lock()
+	until, cooling := a.released[code]
+	if cooling && !a.now().Before(until) {
+		delete(a.released, code)
+		cooling = false
+	}
+	a.mu.Unlock()
+
+	return !cooling && !a.inUse(code)
+}
@@ -0,0 +1,208 @@
+package util
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRooms is a set of codes in use.
+type fakeRooms struct {
+	mu    sync.Mutex
+	codes map[string]bool
+}
+
+func (f *fakeRooms) inUse(code string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.codes[code]
+}
+
+func TestCodeOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		modify  func(o *CodeOptions)
+		wantErr string
+	}{
+		{name: "defaults", modify: func(o *CodeOptions) {}},
+		{name: "too short", modify: func(o *CodeOptions) { o.Length = 3 }, wantErr: "code length"},
+		{name: "too long", modify: func(o *CodeOptions) { o.Length = 13 }, wantErr: "code length"},
+		{name: "symbols", modify: func(o *CodeOptions) { o.Alphabet = "AB-C" }, wantErr: "only contain letters and digits"},
+		{name: "duplicates", modify: func(o *CodeOptions) { o.Alphabet = "ABCA" }, wantErr: "twice"},
+		{name: "single character", modify: func(o *CodeOptions) { o.Alphabet = "A" }, wantErr: "at least 2"},
+		{name: "negative cooldown", modify: func(o *CodeOptions) { o.Cooldown = -time.Second }, wantErr: "cooldown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			options := DefaultCodeOptions()
+			tt.modify(&options)
+			err := options.Validate()
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCodeAllocator_Allocate(t *testing.T) {
+	t.Parallel()
+
+	options := CodeOptions{Length: 5, Alphabet: "XYZ789"}
+	allocator, err := NewCodeAllocator(options, func(string) bool { return false })
+	if err != nil {
+		t.Fatalf("NewCodeAllocator() error = %v", err)
+	}
+
+	for i := 0; i < 50; i++ {
+		code, err := allocator.Allocate()
+		if err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		if len(code) != 5 {
+			t.Errorf("expected 5 characters, got %q", code)
+		}
+		for _, c := range code {
+			if !strings.ContainsRune(options.Alphabet, c) {
+				t.Errorf("code %q contains %q outside the alphabet", code, c)
+			}
+		}
+	}
+}
+
+func TestCodeAllocator_SkipsCodesInUse(t *testing.T) {
+	t.Parallel()
+
+	// 16 possible codes, 15 of them taken
+	rooms := &fakeRooms{codes: map[string]bool{}}
+	for _, a := range "AB" {
+		for _, b := range "AB" {
+			for _, c := range "AB" {
+				for _, d := range "AB" {
+					rooms.codes[string([]rune{a, b, c, d})] = true
+				}
+			}
+		}
+	}
+	delete(rooms.codes, "BABA")
+
+	allocator, _ := NewCodeAllocator(CodeOptions{Length: 4, Alphabet: "AB"}, rooms.inUse)
+
+	for i := 0; i < 20; i++ {
+		code, err := allocator.Allocate()
+		if errors.Is(err, ErrCodeSpaceExhausted) {
+			continue // Unlucky draws are allowed to give up
+		}
+		if err != nil {
+			t.Fatalf("Allocate() error = %v", err)
+		}
+		if code != "BABA" {
+			t.Fatalf("expected the only free code BABA, got %q", code)
+		}
+	}
+
+	rooms.codes["BABA"] = true
+	if _, err := allocator.Allocate(); !errors.Is(err, ErrCodeSpaceExhausted) {
+		t.Errorf("expected ErrCodeSpaceExhausted, got %v", err)
+	}
+}
+
+func TestCodeAllocator_Cooldown(t *testing.T) {
+	t.Parallel()
+
+	options := CodeOptions{Length: 4, Alphabet: "AB", Cooldown: time.Minute, AllowCustom: true}
+	allocator, _ := NewCodeAllocator(options, func(string) bool { return false })
+	start := time.Unix(1_700_000_000, 0)
+	allocator.now = func() time.Time { return start }
+
+	allocator.Release("ABAB")
+	if _, err := allocator.Claim("ABAB"); !errors.Is(err, ErrCodeTaken) {
+		t.Errorf("expected freed code to be held back, got %v", err)
+	}
+
+	allocator.now = func() time.Time { return start.Add(time.Minute) }
+	if code, err := allocator.Claim("ABAB"); err != nil || code != "ABAB" {
+		t.Errorf("expected code available after cooldown, got %q, %v", code, err)
+	}
+}
+
+func TestCodeAllocator_Take(t *testing.T) {
+	t.Parallel()
+
+	rooms := &fakeRooms{codes: map[string]bool{}}
+	options := CodeOptions{Length: 4, Alphabet: "AB", Cooldown: time.Minute}
+	allocator, _ := NewCodeAllocator(options, rooms.inUse)
+
+	// An imported room ends its code's cooldown
+	allocator.Release("ABAB")
+	if err := allocator.Take("ABAB"); err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	if _, cooling := allocator.released["ABAB"]; cooling {
+		t.Error("expected the taken code's cooldown to end")
+	}
+
+	if err := allocator.Take("abab"); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("expected ErrInvalidCode for a code players can't type, got %v", err)
+	}
+}
+
+func TestCodeAllocator_Claim(t *testing.T) {
+	t.Parallel()
+
+	rooms := &fakeRooms{codes: map[string]bool{"TAKEN": true}}
+	options := DefaultCodeOptions()
+	options.AllowCustom = true
+	allocator, _ := NewCodeAllocator(options, rooms.inUse)
+
+	disabled, _ := NewCodeAllocator(DefaultCodeOptions(), rooms.inUse)
+
+	tests := []struct {
+		name      string
+		allocator *CodeAllocator
+		code      string
+		want      string
+		wantErr   error
+	}{
+		{name: "vanity code", allocator: allocator, code: "PARTY", want: "PARTY"},
+		{name: "normalized", allocator: allocator, code: " party ", want: "PARTY"},
+		{name: "taken", allocator: allocator, code: "taken", wantErr: ErrCodeTaken},
+		{name: "too short", allocator: allocator, code: "ABC", wantErr: ErrInvalidCode},
+		{name: "too long", allocator: allocator, code: "ABCDEFGHJKLMN", wantErr: ErrInvalidCode},
+		{name: "ambiguous character", allocator: allocator, code: "GO0D", wantErr: ErrInvalidCode},
+		{name: "disabled", allocator: disabled, code: "PARTY", wantErr: ErrCustomCodesDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.allocator.Claim(tt.code)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Claim() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,28 +2,89 @@ package util
 
 import (
 	"crypto/rand"
+	"errors"
+	"fmt"
 	"math/big"
+	"time"
 )
 
-// GenerateRoomCode creates a random 6-character room code.
-// Uses characters that are easy to read and type on mobile: uppercase letters and numbers,
-// excluding ambiguous characters (0/O, 1/I).
-func GenerateRoomCode() string {
-	const charset = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // No 0,O,1,I
-	const codeLength = 6
+// DefaultCodeAlphabet contains characters that are easy to read and type on
+// mobile: uppercase letters and numbers, excluding ambiguous characters
+// (0/O, 1/I).
+const DefaultCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
 
-	code := make([]byte, codeLength)
-	charsetLen := big.NewInt(int64(len(charset)))
+// Bounds for code lengths, for generated and custom codes alike.
+const (
+	MinCodeLength = 4
+	MaxCodeLength = 12
+)
+
+// CodeOptions configures room code allocation.
+type CodeOptions struct {
+	// Length is the length of generated codes.
+	Length int `yaml:"length"`
+
+	// Alphabet is the set of characters codes are made of. Only ASCII
+	// letters and digits are allowed, since codes appear in URLs.
+	Alphabet string `yaml:"alphabet"`
+
+	// Cooldown is how long a freed code is held back before it is handed
+	// out again, so stale links and sessions never reach a new room.
+	Cooldown time.Duration `yaml:"cooldown"`
+
+	// AllowCustom lets hosts choose their own room code.
+	AllowCustom bool `yaml:"allowCustom"`
+}
+
+// DefaultCodeOptions returns 6-character codes from DefaultCodeAlphabet.
+func DefaultCodeOptions() CodeOptions {
+	return CodeOptions{
+		Length:   6,
+		Alphabet: DefaultCodeAlphabet,
+		Cooldown: 10 * time.Minute,
+	}
+}
+
+// Validate reports invalid options.
+func (o CodeOptions) Validate() error {
+	if o.Length < MinCodeLength || o.Length > MaxCodeLength {
+		return fmt.Errorf("code length must be between %d and %d, got %d", MinCodeLength, MaxCodeLength, o.Length)
+	}
+
+	seen := make(map[rune]bool, len(o.Alphabet))
+	for _, c := range o.Alphabet {
+		if !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9') {
+			return fmt.Errorf("code alphabet may only contain letters and digits, got %q", c)
+		}
+		if seen[c] {
+			return fmt.Errorf("code alphabet contains %q twice", c)
+		}
+		seen[c] = true
+	}
+	if len(seen) < 2 {
+		return errors.New("code alphabet needs at least 2 characters")
+	}
+
+	if o.Cooldown < 0 {
+		return fmt.Errorf("code cooldown must not be negative, got %s", o.Cooldown)
+	}
+	return nil
+}
+
+// NewCode creates a random code of the given length from alphabet, using a
+// cryptographically secure source. It fails rather than produce a
+// predictable code.
+func NewCode(alphabet string, length int) (string, error) {
+	code := make([]byte, length)
+	alphabetLen := big.NewInt(int64(len(alphabet)))
 
 	for i := range code {
-		num, err := rand.Int(rand.Reader, charsetLen)
+		num, err := rand.Int(rand.Reader, alphabetLen)
 		if err != nil {
-			// Fallback to a less secure but functional approach
-			code[i] = charset[i%len(charset)]
-			continue
+			return "", fmt.Errorf("failed to generate room code: %w", err)
 		}
-		code[i] = charset[num.Int64()]
+		code[i] = alphabet[num.Int64()]
 	}
 
-	return string(code)
+	return string(code), nil
 }
@@ -5,12 +5,23 @@ import (
 	"testing"
 )
 
-func TestGenerateRoomCode_Length(t *testing.T) {
+// newRoomCode creates a default 6-character room code.
+func newRoomCode(tb testing.TB) string {
+	tb.Helper()
+
+	code, err := NewCode(DefaultCodeAlphabet, 6)
+	if err != nil {
+		tb.Fatalf("NewCode() error = %v", err)
+	}
+	return code
+}
+
+func TestNewCode_Length(t *testing.T) {
 	t.Parallel()
 
 	// Generate multiple codes and verify length
 	for i := 0; i < 100; i++ {
-		code := GenerateRoomCode()
+		code := newRoomCode(t)
 
 		if len(code) != 6 {
 			t.Errorf("code length = %d, want 6 (code: %s)", len(code), code)
@@ -18,7 +29,7 @@ func TestGenerateRoomCode_Length(t *testing.T) {
 	}
 }
 
-func TestGenerateRoomCode_Charset(t *testing.T) {
+func TestNewCode_Charset(t *testing.T) {
 	t.Parallel()
 
 	// Valid characters (excluding 0, O, 1, I, L for readability)
@@ -26,7 +37,7 @@ func TestGenerateRoomCode_Charset(t *testing.T) {
 
 	// Generate many codes and verify all characters are valid
 	for i := 0; i < 100; i++ {
-		code := GenerateRoomCode()
+		code := newRoomCode(t)
 
 		for _, char := range code {
 			if !strings.ContainsRune(validChars, char) {
@@ -36,7 +47,7 @@ func TestGenerateRoomCode_Charset(t *testing.T) {
 	}
 }
 
-func TestGenerateRoomCode_NoAmbiguousCharacters(t *testing.T) {
+func TestNewCode_NoAmbiguousCharacters(t *testing.T) {
 	t.Parallel()
 
 	// Characters that should NOT appear (ambiguous) - based on actual charset
@@ -45,7 +56,7 @@ func TestGenerateRoomCode_NoAmbiguousCharacters(t *testing.T) {
 
 	// Generate many codes and verify no ambiguous characters
 	for i := 0; i < 100; i++ {
-		code := GenerateRoomCode()
+		code := newRoomCode(t)
 
 		for _, char := range code {
 			if strings.ContainsRune(ambiguousChars, char) {
@@ -55,7 +66,7 @@ func TestGenerateRoomCode_NoAmbiguousCharacters(t *testing.T) {
 	}
 }
 
-func TestGenerateRoomCode_Uniqueness(t *testing.T) {
+func TestNewCode_Uniqueness(t *testing.T) {
 	t.Parallel()
 
 	// Generate many codes and check for uniqueness
@@ -64,7 +75,7 @@ func TestGenerateRoomCode_Uniqueness(t *testing.T) {
 	duplicates := 0
 
 	for i := 0; i < numCodes; i++ {
-		code := GenerateRoomCode()
+		code := newRoomCode(t)
 
 		if codes[code] {
 			duplicates++
@@ -82,15 +93,15 @@ func TestGenerateRoomCode_Uniqueness(t *testing.T) {
 	}
 }
 
-func TestGenerateRoomCode_Randomness(t *testing.T) {
+func TestNewCode_Randomness(t *testing.T) {
 	t.Parallel()
 
 	t.Run("codes are not identical", func(t *testing.T) {
 		t.Parallel()
 
-		code1 := GenerateRoomCode()
-		code2 := GenerateRoomCode()
-		code3 := GenerateRoomCode()
+		code1 := newRoomCode(t)
+		code2 := newRoomCode(t)
+		code3 := newRoomCode(t)
 
 		// It's extremely unlikely that all three codes are the same
 		if code1 == code2 && code2 == code3 {
@@ -106,7 +117,7 @@ func TestGenerateRoomCode_Randomness(t *testing.T) {
 		charCounts := make(map[rune]int)
 
 		for i := 0; i < numCodes; i++ {
-			code := GenerateRoomCode()
+			code := newRoomCode(t)
 			for _, char := range code {
 				charCounts[char]++
 			}
@@ -141,7 +152,7 @@ func TestGenerateRoomCode_Randomness(t *testing.T) {
 		}
 
 		for i := 0; i < numCodes; i++ {
-			code := GenerateRoomCode()
+			code := newRoomCode(t)
 			for pos, char := range code {
 				positions[pos][char] = true
 			}
@@ -156,12 +167,12 @@ func TestGenerateRoomCode_Randomness(t *testing.T) {
 	})
 }
 
-func TestGenerateRoomCode_UppercaseOnly(t *testing.T) {
+func TestNewCode_UppercaseOnly(t *testing.T) {
 	t.Parallel()
 
 	// Generate codes and verify all are uppercase
 	for i := 0; i < 100; i++ {
-		code := GenerateRoomCode()
+		code := newRoomCode(t)
 
 		if strings.ToUpper(code) != code {
 			t.Errorf("code is not all uppercase: %s", code)
@@ -176,12 +187,12 @@ func TestGenerateRoomCode_UppercaseOnly(t *testing.T) {
 	}
 }
 
-func TestGenerateRoomCode_NoSpaces(t *testing.T) {
+func TestNewCode_NoSpaces(t *testing.T) {
 	t.Parallel()
 
 	// Generate codes and verify no spaces
 	for i := 0; i < 100; i++ {
-		code := GenerateRoomCode()
+		code := newRoomCode(t)
 
 		if strings.Contains(code, " ") {
 			t.Errorf("code contains space: %s", code)
@@ -194,17 +205,17 @@ func TestGenerateRoomCode_NoSpaces(t *testing.T) {
 	}
 }
 
-func TestGenerateRoomCode_Consistency(t *testing.T) {
+func TestNewCode_Consistency(t *testing.T) {
 	t.Parallel()
 
 	t.Run("always returns a string", func(t *testing.T) {
 		t.Parallel()
 
 		for i := 0; i < 10; i++ {
-			code := GenerateRoomCode()
+			code := newRoomCode(t)
 
 			if code == "" {
-				t.Error("GenerateRoomCode returned empty string")
+				t.Error("NewCode returned empty string")
 			}
 		}
 	})
@@ -215,7 +226,7 @@ func TestGenerateRoomCode_Consistency(t *testing.T) {
 		specialChars := "!@#$%^&*()_+-=[]{}\\|;:'\",.<>?/`~"
 
 		for i := 0; i < 100; i++ {
-			code := GenerateRoomCode()
+			code := newRoomCode(t)
 
 			for _, char := range code {
 				if strings.ContainsRune(specialChars, char) {
@@ -226,23 +237,23 @@ func TestGenerateRoomCode_Consistency(t *testing.T) {
 	})
 }
 
-func TestGenerateRoomCode_Performance(t *testing.T) {
+func TestNewCode_Performance(t *testing.T) {
 	t.Parallel()
 
 	// This test just verifies it doesn't hang or panic
 	// Generate a reasonable number of codes quickly
 	for i := 0; i < 10000; i++ {
-		_ = GenerateRoomCode()
+		_ = newRoomCode(t)
 	}
 }
 
-func BenchmarkGenerateRoomCode(b *testing.B) {
+func BenchmarkNewCode(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		GenerateRoomCode()
+		newRoomCode(b)
 	}
 }
 
-func TestGenerateRoomCode_StatisticalProperties(t *testing.T) {
+func TestNewCode_StatisticalProperties(t *testing.T) {
 	t.Parallel()
 
 	t.Run("no repeating patterns", func(t *testing.T) {
@@ -252,7 +263,7 @@ func TestGenerateRoomCode_StatisticalProperties(t *testing.T) {
 		numCodes := 100
 
 		for i := 0; i < numCodes; i++ {
-			code := GenerateRoomCode()
+			code := newRoomCode(t)
 
 			// Check for all same character (AAAAAA)
 			allSame := true
@@ -285,7 +296,7 @@ func TestGenerateRoomCode_StatisticalProperties(t *testing.T) {
 	})
 }
 
-func TestGenerateRoomCode_EdgeCases(t *testing.T) {
+func TestNewCode_EdgeCases(t *testing.T) {
 	t.Parallel()
 
 	t.Run("concurrent generation", func(t *testing.T) {
@@ -300,7 +311,10 @@ func TestGenerateRoomCode_EdgeCases(t *testing.T) {
 		for i := 0; i < numGoroutines; i++ {
 			go func() {
 				for j := 0; j < codesPerGoroutine; j++ {
-					code := GenerateRoomCode()
+					code, err := NewCode(DefaultCodeAlphabet, 6)
+					if err != nil {
+						t.Errorf("NewCode() error = %v", err)
+					}
 					results <- code
 				}
 			}()
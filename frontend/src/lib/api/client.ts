@@ -1,5 +1,7 @@
 // API client for backend communication
 
+import type { GameEvent } from '$lib/stores/game.svelte';
+
 // Normalize API URL - ensure it has a protocol if it's an absolute URL
 function normalizeApiUrl(url: string): string {
 	if (!url) return '/api';
@@ -26,6 +28,10 @@ export interface CreateRoomRequest {
 	gameType: string;
 	displayName: string;
 	maxPlayers?: number;
+	password?: string; // Required to join, unless invited
+	roomCode?: string; // Custom code, if the server allows them
+	public?: boolean; // List in the public room browser
+	title?: string; // Public room title, defaults to the host's name
 }
 
 export interface CreateRoomResponse {
@@ -36,6 +42,8 @@ export interface CreateRoomResponse {
 
 export interface JoinRoomRequest {
 	displayName: string;
+	password?: string;
+	invite?: string; // Invite token, instead of the password
 }
 
 export interface JoinRoomResponse {
@@ -55,16 +63,260 @@ export interface RoomState {
 	maxPlayers: number;
 	hostId: string;
 	players: Player[];
+	hasPassword?: boolean;
+	muted?: string[]; // Player IDs muted in chat
+	public?: boolean;
+	title?: string;
+	createdAt: string;
+	accusations?: Accusation[]; // Werewolf days and Avalon team votes
+}
+
+export interface PublicRoom {
+	roomCode: string;
+	title: string;
+	gameType: string;
+	players: number;
+	maxPlayers: number;
+	hasPassword?: boolean;
+	createdAt: string;
+}
+
+export interface QuickMatchRequest {
+	displayName: string;
+	gameType: string;
+}
+
+export interface QuickMatchResponse {
+	roomCode: string;
+	sessionToken: string;
+	playerId: string;
+	created: boolean; // A new room was opened, with the player as host
+}
+
+export interface InviteResponse {
+	roomCode: string;
+	invite: string;
+	expiresAt: string;
+}
+
+export interface RoundScore {
+	round: number;
+	points: Record<string, number>; // Player ID → points this round
+	winners: string[];
+	winReason?: string;
+	finishedAt: string;
+}
+
+export interface SessionStanding {
+	rank: number; // Tied players share a rank
+	playerId: string;
+	displayName: string;
+	points: number;
+	wins: number;
+}
+
+// Also the payload of the final_standings event
+export interface SessionSummary {
+	round: number;
+	rounds: RoundScore[];
+	standings: SessionStanding[];
+	startedAt: string;
+}
+
+// 'all', 'whisper', or a game's team channel such as Avalon's 'evil'
+export interface ChatMessage {
+	channel: string;
+	to?: string; // Recipient's player ID, for whispers
+	text: string;
+}
+
+// Payload of chat_message events, delivered in 'chat' server messages
+export interface ChatMessagePayload {
+	channel: string;
+	senderName: string;
+	to?: string;
+	text: string;
+}
+
+// Payload of the 'chat_history' server message
+export interface ChatHistory {
+	channels: string[]; // Channels the player can post to, apart from whispers
+	messages: GameEvent[];
+}
+
+// Ephemeral gesture; the server fills in playerId and at
+export interface Reaction {
+	kind: 'emoji' | 'point';
+	playerId?: string;
+	targetId?: string; // Required for pointing
+	emoji?: string; // One of REACTION_EMOJIS
+	at?: string;
+}
+
+// Emoji the server accepts (core.ReactionEmojis)
+export const REACTION_EMOJIS = ['👍', '👎', '😂', '😮', '🤔', '😡', '👀', '🤫'];
+
+// Summarized in the room state during Werewolf days and Avalon team votes
+export interface Accusation {
+	suspectId: string;
+	accusedBy: string[];
+}
+
+export interface TournamentOptions {
+	name: string;
+	gameType: string;
+	pairing: 'random' | 'swiss';
+	rounds: number;
+	tableSize: number; // Most players at a table
+	minTableSize: number;
+}
+
+export interface TournamentTable {
+	number: number;
+	roomCode: string;
+	roomCreatedAt: string; // Tells the table's room from later ones reusing the code
+	seats: { profileId: string; playerId: string }[];
+	results?: Record<string, { points: number; won: boolean }>; // Profile ID → result
+	finished: boolean;
+	closed?: boolean; // Finished by the organizer without a result
+}
+
+export interface TournamentStanding {
+	rank: number; // Tied entrants share a rank
+	profileId: string;
+	displayName: string;
+	points: number;
+	wins: number;
+	played: number;
+}
+
+export interface Tournament extends TournamentOptions {
+	id: string;
+	organizerId: string;
+	status: 'registration' | 'playing' | 'finished';
+	entrants: { profileId: string; displayName: string; registeredAt: string }[];
+	rounds: { number: number; tables: TournamentTable[]; startedAt: string; finishedAt?: string }[];
+	standings: TournamentStanding[];
+	createdAt: string;
+	updatedAt: string;
+}
+
+export interface TournamentSeat {
+	round: number;
+	table: number;
+	roomCode: string;
+	playerId: string;
+	sessionToken: string;
+	host: boolean; // Starts the game
 }
 
 export interface Player {
 	id: string;
 	displayName: string;
+	profileId?: string;
+	avatarColor?: string;
 	connected: boolean;
 	joinedAt: string;
 	lastSeenAt: string;
+	bot?: BotSettings; // Set for bot seats
+}
+
+export interface BotSettings {
+	strategy: string;
+	minThinkMs: number;
+	maxThinkMs: number;
+}
+
+// Every field is optional; the server picks "Bot N", the random strategy and
+// a think time of 1.5–4s
+export interface AddBotRequest {
+	displayName?: string;
+	strategy?: string;
+	minThinkMs?: number;
+	maxThinkMs?: number;
+}
+
+export interface AddBotResponse {
+	playerId: string;
+	displayName: string;
+	bot: BotSettings;
 }
 
+export interface Profile {
+	id: string;
+	username?: string;
+	displayName: string;
+	avatarColor: string;
+	preferences?: Record<string, string>;
+	memberships?: { roomCode: string; playerId: string; joinedAt: string }[];
+	createdAt: string;
+	updatedAt: string;
+}
+
+export interface CreateProfileRequest {
+	displayName: string;
+	avatarColor?: string;
+	username?: string; // For signing in on other devices
+	pin?: string; // 4-8 digits, required with a username
+}
+
+export interface ProfileResponse {
+	profile: Profile;
+	key: string; // Device key, sent as X-Profile-Key
+}
+
+export interface StatsRecord {
+	played: number;
+	wins: number;
+	eliminated?: number;
+}
+
+export interface SkillRating {
+	rating: number;
+	deviation: number; // True skill is within ±2 deviations with 95% confidence
+	volatility: number;
+	games: number;
+}
+
+export interface GameStats extends StatsRecord {
+	teams?: Record<string, StatsRecord>;
+	roles?: Record<string, StatsRecord>;
+	rating?: SkillRating;
+	teamRatings?: Record<string, SkillRating>; // Team → rating playing that side
+}
+
+export interface ProfileStats {
+	profileId: string;
+	displayName: string;
+	avatarColor: string;
+	games: Record<string, GameStats>; // Game type → stats
+	highlights: {
+		merlinSurvivalRate?: number;
+		eliminatedAsWerewolf: number;
+	};
+}
+
+export interface LeaderboardEntry {
+	profileId: string;
+	displayName: string;
+	avatarColor: string;
+	played: number;
+	wins: number;
+	winRate: number;
+	rating?: number; // Absent if unrated
+	deviation?: number;
+}
+
+const PROFILE_KEY_STORAGE = 'profileKey';
+
+// Device key of the player's profile, attached to every request when set
+export const profileKey = {
+	get: (): string | null =>
+		typeof localStorage !== 'undefined' ? localStorage.getItem(PROFILE_KEY_STORAGE) : null,
+	set: (key: string) => localStorage.setItem(PROFILE_KEY_STORAGE, key),
+	clear: () => localStorage.removeItem(PROFILE_KEY_STORAGE)
+};
+
 export class APIError extends Error {
 	constructor(public status: number, message: string) {
 		super(message);
@@ -79,10 +331,12 @@ async function request<T>(
 		const url = `${API_BASE}${endpoint}`;
 		console.log(`API Request: ${options?.method || 'GET'} ${url}`);
 
+		const key = profileKey.get();
 		const response = await fetch(url, {
 			...options,
 			headers: {
 				'Content-Type': 'application/json',
+				...(key ? { 'X-Profile-Key': key } : {}),
 				...options?.headers
 			}
 		});
@@ -127,6 +381,102 @@ export const api = {
 			body: JSON.stringify(req)
 		}),
 
+	listPublicRooms: (gameType?: string) =>
+		request<{ rooms: PublicRoom[] }>(
+			`/rooms?public=true${gameType ? `&game=${encodeURIComponent(gameType)}` : ''}`,
+			{ method: 'GET' }
+		),
+
+	quickMatch: (req: QuickMatchRequest) =>
+		request<QuickMatchResponse>('/quickmatch', {
+			method: 'POST',
+			body: JSON.stringify(req)
+		}),
+
+	createProfile: (req: CreateProfileRequest) =>
+		request<ProfileResponse>('/profiles', {
+			method: 'POST',
+			body: JSON.stringify(req)
+		}),
+
+	loginProfile: (username: string, pin: string) =>
+		request<ProfileResponse>('/profiles/login', {
+			method: 'POST',
+			body: JSON.stringify({ username, pin })
+		}),
+
+	getProfile: () =>
+		request<Profile>('/profiles/me', {
+			method: 'GET'
+		}),
+
+	updateProfile: (changes: { displayName?: string; avatarColor?: string; preferences?: Record<string, string> }) =>
+		request<Profile>('/profiles/me', {
+			method: 'PATCH',
+			body: JSON.stringify(changes)
+		}),
+
+	getProfileStats: (profileId: string = 'me') =>
+		request<ProfileStats>(`/profiles/${profileId}/stats`, {
+			method: 'GET'
+		}),
+
+	// Ranks a group of profiles, e.g. the players in a room, by win rate or
+	// by rating, optionally on one team
+	getLeaderboard: (
+		gameType: string,
+		profileIds: string[],
+		options: { sort?: 'winRate' | 'rating'; team?: string } = {}
+	) => {
+		const params = new URLSearchParams({ game: gameType, profiles: profileIds.join(',') });
+		if (options.sort) params.set('sort', options.sort);
+		if (options.team) params.set('team', options.team);
+		return request<{ gameType: string; sort: string; team?: string; standings: LeaderboardEntry[] }>(
+			`/leaderboard?${params}`,
+			{ method: 'GET' }
+		);
+	},
+
+	// Tournaments; all but getTournament need a profile
+	createTournament: (options: TournamentOptions) =>
+		request<Tournament>('/tournaments', {
+			method: 'POST',
+			body: JSON.stringify(options)
+		}),
+
+	getTournament: (id: string) =>
+		request<Tournament>(`/tournaments/${id}`, {
+			method: 'GET'
+		}),
+
+	registerForTournament: (id: string) =>
+		request<Tournament>(`/tournaments/${id}/register`, {
+			method: 'POST'
+		}),
+
+	// Organizer only; opens a room per table
+	startTournamentRound: (id: string) =>
+		request<Tournament>(`/tournaments/${id}/rounds`, {
+			method: 'POST'
+		}),
+
+	// Organizer only; finishes a table of the current round without a result
+	closeTournamentTable: (id: string, table: number) =>
+		request<Tournament>(`/tournaments/${id}/tables/${table}/close`, {
+			method: 'POST'
+		}),
+
+	// Organizer only
+	finishTournament: (id: string) =>
+		request<Tournament>(`/tournaments/${id}/finish`, {
+			method: 'POST'
+		}),
+
+	getTournamentSeat: (id: string) =>
+		request<TournamentSeat>(`/tournaments/${id}/seat`, {
+			method: 'GET'
+		}),
+
 	getRoomState: (roomCode: string) =>
 		request<RoomState>(`/rooms/${roomCode}`, {
 			method: 'GET'
@@ -141,5 +491,67 @@ export const api = {
 	resetGame: (roomCode: string) =>
 		request<void>(`/rooms/${roomCode}/reset`, {
 			method: 'POST'
+		}),
+
+	// Scoreboard of the games played since the session started
+	getSessionSummary: (roomCode: string) =>
+		request<SessionSummary>(`/rooms/${roomCode}/session_summary`, {
+			method: 'GET'
+		}),
+
+	// Host only; sends everyone the final standings and starts a new session
+	endSession: (roomCode: string, sessionToken: string) =>
+		request<SessionSummary>(`/rooms/${roomCode}/end_session`, {
+			method: 'POST',
+			headers: { 'X-Session-Token': sessionToken }
+		}),
+
+	// Server-rendered QR code of the join link, for <img src>
+	qrCodeUrl: (roomCode: string, format: 'png' | 'svg' = 'svg', invite?: string) =>
+		`${API_BASE}/rooms/${roomCode}/qr.${format}${invite ? `?invite=${encodeURIComponent(invite)}` : ''}`,
+
+	// Host only; lets players join a password-protected room
+	createInvite: (roomCode: string, sessionToken: string) =>
+		request<InviteResponse>(`/rooms/${roomCode}/invites`, {
+			method: 'POST',
+			headers: { 'X-Session-Token': sessionToken }
+		}),
+
+	// For SSE clients; WebSocket clients send a 'chat' message instead
+	sendChat: (roomCode: string, message: ChatMessage, sessionToken: string) =>
+		request<void>(`/rooms/${roomCode}/chat`, {
+			method: 'POST',
+			headers: { 'X-Session-Token': sessionToken },
+			body: JSON.stringify(message)
+		}),
+
+	// For SSE clients; WebSocket clients send a 'react' message instead
+	sendReaction: (roomCode: string, reaction: Reaction, sessionToken: string) =>
+		request<void>(`/rooms/${roomCode}/reactions`, {
+			method: 'POST',
+			headers: { 'X-Session-Token': sessionToken },
+			body: JSON.stringify(reaction)
+		}),
+
+	// Host only; muted players cannot chat or react until unmuted
+	setMuted: (roomCode: string, playerId: string, muted: boolean, sessionToken: string) =>
+		request<void>(`/rooms/${roomCode}/players/${playerId}/mute`, {
+			method: muted ? 'POST' : 'DELETE',
+			headers: { 'X-Session-Token': sessionToken }
+		}),
+
+	// Host only, before the game starts; bots are removed with kickPlayer
+	addBot: (roomCode: string, options: AddBotRequest, sessionToken: string) =>
+		request<AddBotResponse>(`/rooms/${roomCode}/bots`, {
+			method: 'POST',
+			headers: { 'X-Session-Token': sessionToken },
+			body: JSON.stringify(options)
+		}),
+
+	// Host only, before the game starts; the player's session is revoked
+	kickPlayer: (roomCode: string, playerId: string, sessionToken: string) =>
+		request<void>(`/rooms/${roomCode}/players/${playerId}`, {
+			method: 'DELETE',
+			headers: { 'X-Session-Token': sessionToken }
 		})
 };
@@ -1,4 +1,6 @@
 import { browser } from '$app/environment';
+import { session } from './session.svelte';
+import type { ChatMessage, Reaction } from '$lib/api/client';
 
 export interface ServerMessage {
 	type: string;
@@ -10,7 +12,22 @@ export interface ClientMessage {
 	payload?: any;
 }
 
-export type ConnectionStatus = 'disconnected' | 'connecting' | 'connected' | 'reconnecting';
+// Protocol version spoken by this client (see backend/internal/server/protocol.go)
+export const PROTOCOL_VERSION = 2;
+
+export type ConnectionStatus =
+	| 'disconnected'
+	| 'connecting'
+	| 'connected'
+	| 'reconnecting'
+	| 'restarting';
+
+// Close code the server uses when it restarts (RFC 6455 "Service Restart")
+const CLOSE_SERVICE_RESTART = 1012;
+
+// Close code for connections the server refuses for good, e.g. an expired or
+// revoked session (RFC 6455 "Policy Violation"); reconnecting cannot help
+const CLOSE_POLICY_VIOLATION = 1008;
 
 class WebSocketStore {
 	status = $state<ConnectionStatus>('disconnected');
@@ -20,6 +37,9 @@ class WebSocketStore {
 	#ws: WebSocket | null = null;
 	#reconnectAttempts = 0;
 	#maxReconnectAttempts = 5;
+	#maxRestartAttempts = 10; // A restarting server may take a while to come back
+	#restarting = false;
+	#restartDelay = 3000; // Updated from the server's server_restarting hint
 	#reconnectTimeout: ReturnType<typeof setTimeout> | null = null;
 	#roomCode: string;
 	#sessionToken: string;
@@ -61,11 +81,16 @@ class WebSocketStore {
 		this.#ws.onopen = () => {
 			console.log('WebSocket connected');
 			this.#reconnectAttempts = 0;
+			this.#restarting = false;
 
 			// Send authentication message
 			this.send({
 				type: 'authenticate',
-				payload: { sessionToken: this.#sessionToken }
+				payload: {
+					sessionToken: this.#sessionToken,
+					protocolVersion: PROTOCOL_VERSION,
+					capabilities: ['token_rotation', 'chat', 'reactions']
+				}
 			});
 
 			this.status = 'connected';
@@ -77,6 +102,20 @@ class WebSocketStore {
 				const message: ServerMessage = JSON.parse(event.data);
 				console.log('WebSocket message:', message);
 
+				// With token_rotation the server hands out a new token on every connect
+				if (message.type === 'authenticated' && message.payload?.sessionToken) {
+					this.#sessionToken = message.payload.sessionToken;
+					session.update((s) =>
+						s && s.roomCode === this.#roomCode
+							? { ...s, sessionToken: message.payload.sessionToken }
+							: s
+					);
+				}
+
+				if (message.type === 'server_restarting' && message.payload?.reconnectAfterMs) {
+					this.#restartDelay = message.payload.reconnectAfterMs;
+				}
+
 				this.messages = [...this.messages, message];
 			} catch (err) {
 				console.error('Failed to parse WebSocket message:', err);
@@ -92,7 +131,28 @@ class WebSocketStore {
 			console.log('WebSocket closed:', event.code, event.reason);
 			this.#ws = null;
 
-			if (event.code !== 1000 && this.#reconnectAttempts < this.#maxReconnectAttempts) {
+			if (event.code === CLOSE_SERVICE_RESTART) {
+				// Server restart: our room survives, so start a fresh retry budget
+				this.#restarting = true;
+				this.#reconnectAttempts = 0;
+			}
+
+			if (event.code === CLOSE_POLICY_VIOLATION) {
+				this.#restarting = false;
+				this.error = event.reason || 'Session is no longer valid';
+				this.status = 'disconnected';
+			} else if (this.#restarting && this.#reconnectAttempts < this.#maxRestartAttempts) {
+				const delay = this.#restartDelay + 1000 * this.#reconnectAttempts;
+				this.#reconnectAttempts++;
+
+				this.status = 'restarting';
+
+				console.log(`Server restarting, reconnecting in ${delay}ms`);
+
+				this.#reconnectTimeout = setTimeout(() => {
+					this.connect();
+				}, delay);
+			} else if (event.code !== 1000 && this.#reconnectAttempts < this.#maxReconnectAttempts) {
 				// Abnormal closure, attempt reconnect
 				const delay = Math.min(1000 * Math.pow(2, this.#reconnectAttempts), 10000);
 				this.#reconnectAttempts++;
@@ -107,12 +167,15 @@ class WebSocketStore {
 					this.connect();
 				}, delay);
 			} else {
+				this.#restarting = false;
 				this.status = 'disconnected';
 			}
 		};
 	}
 
 	disconnect() {
+		this.#restarting = false;
+
 		if (this.#reconnectTimeout) {
 			clearTimeout(this.#reconnectTimeout);
 			this.#reconnectTimeout = null;
@@ -141,6 +204,20 @@ class WebSocketStore {
 		});
 	}
 
+	sendChat(message: ChatMessage) {
+		this.send({
+			type: 'chat',
+			payload: message
+		});
+	}
+
+	sendReaction(reaction: Reaction) {
+		this.send({
+			type: 'react',
+			payload: reaction
+		});
+	}
+
 	reconnect() {
 		this.connect();
 	}